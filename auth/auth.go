@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/redact"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const appName = `generate-bazel-workspace-gradle`
+
+// EnvVariable holds authentication entries, one per line, for when flags would leak them into shell history.
+const EnvVariable = `GENERATE_BAZEL_WORKSPACE_AUTH`
+
+const basicScheme = `basic`
+const bearerScheme = `bearer`
+const headerScheme = `header`
+
+var envRegex = regexp.MustCompile(`\${env\.([^}]+)}`)
+var quotedRegex = regexp.MustCompile("`[^`]*`")
+
+// Config authenticates requests to repositories, read from a YAML file like:
+//
+//	repositories:
+//	  - url: https://nexus.example.com/repository/releases
+//	    username: deployer
+//	    password: ${env.NEXUS_PASSWORD}
+//	  - url: https://gitlab.example.com/api/v4/groups/1/-/packages/maven
+//	    headers:
+//	      Private-Token: ${env.GITLAB_TOKEN}
+type Config struct {
+	Repositories []*Repository `yaml:"repositories"`
+}
+
+type Repository struct {
+	URL      string            `yaml:"url"`
+	Username string            `yaml:"username,omitempty"`
+	Password string            `yaml:"password,omitempty"`
+	Token    string            `yaml:"token,omitempty"`
+	Headers  map[string]string `yaml:"headers,omitempty"`
+}
+
+// DefaultPath follows the XDG base directory specification, falling back to `~/.config`.
+func DefaultPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, appName, "auth.yml")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".config", appName, "auth.yml")
+}
+
+// Load reads a config file, treating a missing file as an empty config.
+func Load(path string) (*Config, error) {
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read authentication config [%s]", path)
+	}
+
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(contents, c); err != nil {
+		// YAML errors quote the offending values, which may be credentials
+		message := quotedRegex.ReplaceAllString(err.Error(), "`"+redact.Mask+"`")
+		return nil, errors.Errorf("error parsing authentication config [%s] : %s", path, message)
+	}
+	for i, r := range c.Repositories {
+		if r.URL == "" {
+			return nil, errors.Errorf("error parsing authentication config [%s] : repository %d has no url", path, i+1)
+		}
+		r.expand()
+	}
+	return c, nil
+}
+
+// ParseEntries reads authentication entries of the form `url=scheme:value`, where the scheme is one of :
+//
+//	basic:username:password
+//	bearer:token
+//	header:Name:value
+//
+// Errors never quote the values of entries, as those are credentials.
+func ParseEntries(entries []string) ([]*Repository, error) {
+	repositories := make([]*Repository, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		r, err := parseEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		r.expand()
+		repositories = append(repositories, r)
+	}
+	return repositories, nil
+}
+
+// EnvEntries reads the authentication entries held by `EnvVariable`.
+func EnvEntries() []string {
+	return strings.Split(os.Getenv(EnvVariable), "\n")
+}
+
+func parseEntry(entry string) (*Repository, error) {
+	separator := strings.Index(entry, "=")
+	if separator < 1 {
+		return nil, errors.New("invalid authentication entry, expected `url=scheme:value`")
+	}
+	r := &Repository{URL: entry[:separator]}
+
+	parts := strings.SplitN(entry[separator+1:], ":", 2)
+	if len(parts) < 2 || parts[1] == "" {
+		return nil, errors.Errorf("invalid authentication entry for [%s], expected `scheme:value`", r.URL)
+	}
+	switch scheme, value := parts[0], parts[1]; scheme {
+	case basicScheme:
+		credentials := strings.SplitN(value, ":", 2)
+		if len(credentials) < 2 {
+			return nil, errors.Errorf("invalid authentication entry for [%s], expected `basic:username:password`", r.URL)
+		}
+		r.Username, r.Password = credentials[0], credentials[1]
+	case bearerScheme:
+		r.Token = value
+	case headerScheme:
+		header := strings.SplitN(value, ":", 2)
+		if len(header) < 2 || strings.TrimSpace(header[0]) == "" {
+			return nil, errors.Errorf("invalid authentication entry for [%s], expected `header:Name:value`", r.URL)
+		}
+		r.Headers = map[string]string{strings.TrimSpace(header[0]): strings.TrimSpace(header[1])}
+	default:
+		return nil, errors.Errorf("invalid authentication entry for [%s], unknown scheme [%s], expected one of : %s",
+			r.URL, scheme, strings.Join([]string{basicScheme, bearerScheme, headerScheme}, ", "))
+	}
+	return r, nil
+}
+
+// Merge combines the credentials configured for the same repository, later ones taking precedence.
+func Merge(sources ...[]*Repository) []*Repository {
+	merged := make([]*Repository, 0)
+	byURL := map[string]*Repository{}
+	for _, repositories := range sources {
+		for _, r := range repositories {
+			url := strings.TrimSuffix(r.URL, "/")
+			m, isMerged := byURL[url]
+			if !isMerged {
+				m = &Repository{URL: url}
+				byURL[url] = m
+				merged = append(merged, m)
+			}
+			m.merge(r)
+		}
+	}
+	return merged
+}
+
+func (r *Repository) merge(other *Repository) {
+	if other.Username != "" || other.Password != "" {
+		r.Username, r.Password = other.Username, other.Password
+	}
+	if other.Token != "" {
+		r.Token = other.Token
+	}
+	for name, value := range other.Headers {
+		if r.Headers == nil {
+			r.Headers = map[string]string{}
+		}
+		r.Headers[name] = value
+	}
+}
+
+// Validate rejects credentials too short to be masked from logs and errors. Errors never quote the values of
+// credentials.
+func Validate(repositories []*Repository) error {
+	for _, r := range repositories {
+		names := []string{"password", "token"}
+		values := []string{r.Password, r.Token}
+		headers := make([]string, 0, len(r.Headers))
+		for name := range r.Headers {
+			headers = append(headers, name)
+		}
+		sort.Strings(headers)
+		for _, name := range headers {
+			names, values = append(names, "header "+name), append(values, r.Headers[name])
+		}
+		for i, value := range values {
+			if value != "" && len(value) < redact.MinSecretLength {
+				return errors.Errorf("invalid credentials for [%s], %s is shorter than %d characters",
+					r.URL, names[i], redact.MinSecretLength)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Repository) Credentials() *maven.Credentials {
+	return &maven.Credentials{Username: r.Username, Password: r.Password, Token: r.Token, Headers: r.Headers}
+}
+
+// expand replaces `${env.NAME}` with the value of environment variables, so secrets can stay out of files and flags.
+func (r *Repository) expand() {
+	r.Username = expand(r.Username)
+	r.Password = expand(r.Password)
+	r.Token = expand(r.Token)
+	for name, value := range r.Headers {
+		r.Headers[name] = expand(value)
+	}
+}
+
+func expand(value string) string {
+	return envRegex.ReplaceAllStringFunc(value, func(expression string) string {
+		return os.Getenv(envRegex.FindStringSubmatch(expression)[1])
+	})
+}
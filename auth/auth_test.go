@@ -0,0 +1,130 @@
+package auth_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/auth"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("Auth", func() {
+	BeforeEach(func() {
+		os.Setenv("AUTH_TEST_TOKEN", "t0k3n")
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("AUTH_TEST_TOKEN")
+	})
+
+	Context("given entries", func() {
+		It("should read every scheme", func() {
+			repositories, err := ParseEntries([]string{
+				"https://nexus.example.com/releases=basic:deployer:pass:word",
+				"",
+				"https://artifactory.example.com/libs=header:X-JFrog-Art-Api: ${env.AUTH_TEST_TOKEN}",
+				"https://bearer.example.com=bearer:${env.AUTH_TEST_TOKEN}",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(repositories).To(Equal([]*Repository{
+				{URL: "https://nexus.example.com/releases", Username: "deployer", Password: "pass:word"},
+				{URL: "https://artifactory.example.com/libs", Headers: map[string]string{"X-JFrog-Art-Api": "t0k3n"}},
+				{URL: "https://bearer.example.com", Token: "t0k3n"},
+			}))
+		})
+
+		It("should not quote credentials in errors", func() {
+			_, err := ParseEntries([]string{"https://nexus.example.com=digest:hunter2"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"invalid authentication entry for [https://nexus.example.com], unknown scheme [digest], expected one of : basic, bearer, header"))
+
+			_, err = ParseEntries([]string{"https://nexus.example.com=basic:hunter2"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		})
+	})
+
+	Context("given a config file", func() {
+		var (
+			dir string
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "auth_test")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		load := func(contents string) (*Config, error) {
+			path := filepath.Join(dir, "auth.yml")
+			Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+			return Load(path)
+		}
+
+		It("should read credentials", func() {
+			config, err := load(`repositories:
+  - url: https://gitlab.example.com/api/v4/packages/maven
+    headers:
+      Private-Token: ${env.AUTH_TEST_TOKEN}
+`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Repositories).To(Equal([]*Repository{{
+				URL:     "https://gitlab.example.com/api/v4/packages/maven",
+				Headers: map[string]string{"Private-Token": "t0k3n"},
+			}}))
+		})
+
+		It("should treat a missing file as empty", func() {
+			config, err := Load(filepath.Join(dir, "missing.yml"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Repositories).To(BeEmpty())
+		})
+
+		It("should not quote credentials in errors", func() {
+			_, err := load(`repositories:
+  - url: https://nexus.example.com
+    headers: hunter2
+`)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("error parsing authentication config ["))
+			Expect(err.Error()).ToNot(ContainSubstring("hunter2"))
+		})
+	})
+
+	Context("given credentials for the same repository from different sources", func() {
+		It("should merge them, later ones taking precedence", func() {
+			merged := Merge(
+				[]*Repository{{URL: "https://nexus.example.com/", Username: "settings", Password: "one"}},
+				[]*Repository{{URL: "https://nexus.example.com", Headers: map[string]string{"X-Custom": "a"}}},
+				[]*Repository{{URL: "https://nexus.example.com", Username: "flag", Password: "two"}},
+			)
+			Expect(merged).To(HaveLen(1))
+			Expect(merged[0].Credentials()).To(Equal(&maven.Credentials{
+				Username: "flag",
+				Password: "two",
+				Headers:  map[string]string{"X-Custom": "a"},
+			}))
+		})
+	})
+
+	Context("given credentials too short to be masked", func() {
+		It("should reject them without quoting them", func() {
+			err := Validate([]*Repository{
+				{URL: "https://nexus.example.com", Username: "me", Password: "hunter2"},
+				{URL: "https://gitlab.example.com", Headers: map[string]string{"Private-Token": "abc"}},
+			})
+			Expect(err).To(MatchError("invalid credentials for [https://gitlab.example.com], header Private-Token is " +
+				"shorter than 4 characters"))
+			Expect(Validate([]*Repository{{URL: "https://nexus.example.com", Username: "me", Password: "hunter2"}})).
+				To(Succeed())
+		})
+	})
+})
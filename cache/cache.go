@@ -0,0 +1,270 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	_ "github.com/jspawar/generate-bazel-workspace-gradle/logging"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const appName = `generate-bazel-workspace-gradle`
+const blobsDir = `blobs`
+const indexDir = `index`
+const tmpPrefix = `.tmp-`
+
+var logger = zap.S()
+
+// Cache is a content-addressed store of downloaded files on disk. Files are stored once per checksum of their
+// contents, and looked up through an index keyed by the URL they were downloaded from.
+type Cache struct {
+	Dir string
+	// TTL is how long entries stored as mutable stay valid, immutable entries never expire.
+	TTL time.Duration
+	now func() time.Time
+}
+
+type entry struct {
+	URL     string    `json:"url"`
+	Blob    string    `json:"blob"`
+	Fetched time.Time `json:"fetched"`
+	Mutable bool      `json:"mutable"`
+}
+
+type Stats struct {
+	Entries        int
+	ExpiredEntries int
+	Blobs          int
+	Bytes          int64
+}
+
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{Dir: dir, TTL: ttl, now: time.Now}
+}
+
+// DefaultDir follows the XDG base directory specification, falling back to `~/.cache`.
+func DefaultDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, appName)
+	}
+	if home := os.Getenv("HOME"); home != "" {
+		return filepath.Join(home, ".cache", appName)
+	}
+	return filepath.Join(os.TempDir(), appName)
+}
+
+// Get returns the cached contents downloaded from `url`, if they are present and haven't expired.
+func (c *Cache) Get(url string) ([]byte, bool) {
+	return c.get(url, false)
+}
+
+// GetStale returns the cached contents downloaded from `url` even if they have expired, for when there is no way to
+// download them again.
+func (c *Cache) GetStale(url string) ([]byte, bool) {
+	return c.get(url, true)
+}
+
+func (c *Cache) get(url string, allowExpired bool) ([]byte, bool) {
+	e, err := c.readEntry(c.indexPath(url))
+	if err != nil {
+		if !os.IsNotExist(errors.Cause(err)) {
+			logger.Debugf("Ignoring unreadable cache entry for [%s] : %s", url, err)
+		}
+		return nil, false
+	}
+	if c.isExpired(e) && !allowExpired {
+		logger.Debugf("Cache entry expired : %s", url)
+		return nil, false
+	}
+
+	contents, err := ioutil.ReadFile(c.blobPath(e.Blob))
+	if err != nil {
+		logger.Debugf("Ignoring missing cache blob for [%s] : %s", url, err)
+		return nil, false
+	}
+	// never trust contents which don't match their address
+	if checksum(contents) != e.Blob {
+		logger.Debugf("Ignoring corrupted cache blob for [%s]", url)
+		return nil, false
+	}
+	return contents, true
+}
+
+// Put stores the contents downloaded from `url`. Mutable contents, like Maven metadata or snapshots, expire after
+// the cache's TTL.
+func (c *Cache) Put(url string, contents []byte, mutable bool) error {
+	blob := checksum(contents)
+	if err := c.writeFile(c.blobPath(blob), contents); err != nil {
+		return errors.Wrapf(err, "failed to cache contents of [%s]", url)
+	}
+
+	bs, err := json.Marshal(&entry{URL: url, Blob: blob, Fetched: c.now().UTC(), Mutable: mutable})
+	if err != nil {
+		return err
+	}
+	if err := c.writeFile(c.indexPath(url), bs); err != nil {
+		return errors.Wrapf(err, "failed to cache contents of [%s]", url)
+	}
+	return nil
+}
+
+// Clean removes every entry from the cache, or only the expired ones. Only the directories the cache writes to are
+// removed, as the cache directory may well hold other files.
+func (c *Cache) Clean(onlyExpired bool) (int, error) {
+	if !onlyExpired {
+		stats, err := c.Stats()
+		if err != nil {
+			return 0, err
+		}
+		for _, dir := range []string{indexDir, blobsDir} {
+			if err := os.RemoveAll(filepath.Join(c.Dir, dir)); err != nil {
+				return 0, errors.Wrapf(err, "failed to remove cache directory [%s]", filepath.Join(c.Dir, dir))
+			}
+		}
+		return stats.Entries, nil
+	}
+
+	removed := 0
+	err := c.walkEntries(func(path string, e *entry) error {
+		if !c.isExpired(e) {
+			return nil
+		}
+		removed++
+		return os.Remove(path)
+	})
+	if err != nil {
+		return removed, err
+	}
+	// blobs are shared between entries, so only remove those nothing refers to anymore
+	return removed, c.removeUnreferencedBlobs()
+}
+
+func (c *Cache) Stats() (*Stats, error) {
+	stats := &Stats{}
+	err := c.walkEntries(func(_ string, e *entry) error {
+		stats.Entries++
+		if c.isExpired(e) {
+			stats.ExpiredEntries++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.walkBlobs(func(_ string, info os.FileInfo) error {
+		stats.Blobs++
+		stats.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (c *Cache) removeUnreferencedBlobs() error {
+	referenced := map[string]bool{}
+	err := c.walkEntries(func(_ string, e *entry) error {
+		referenced[e.Blob] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return c.walkBlobs(func(path string, info os.FileInfo) error {
+		if referenced[info.Name()] {
+			return nil
+		}
+		return os.Remove(path)
+	})
+}
+
+func (c *Cache) walkEntries(fn func(path string, e *entry) error) error {
+	return c.walk(filepath.Join(c.Dir, indexDir), func(path string, _ os.FileInfo) error {
+		e, err := c.readEntry(path)
+		if err != nil {
+			logger.Debugf("Skipping unreadable cache entry [%s] : %s", path, err)
+			return nil
+		}
+		return fn(path, e)
+	})
+}
+
+func (c *Cache) walkBlobs(fn func(path string, info os.FileInfo) error) error {
+	return c.walk(filepath.Join(c.Dir, blobsDir), fn)
+}
+
+func (c *Cache) walk(dir string, fn func(path string, info os.FileInfo) error) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		// skip directories and anything still being written
+		if info.IsDir() || strings.HasPrefix(info.Name(), tmpPrefix) {
+			return nil
+		}
+		return fn(path, info)
+	})
+	return errors.Wrapf(err, "failed to read cache directory [%s]", dir)
+}
+
+func (c *Cache) readEntry(path string) (*entry, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	e := &entry{}
+	if err := json.Unmarshal(bs, e); err != nil {
+		return nil, errors.Wrapf(err, "error parsing cache entry")
+	}
+	return e, nil
+}
+
+func (c *Cache) isExpired(e *entry) bool {
+	return e.Mutable && c.now().Sub(e.Fetched) > c.TTL
+}
+
+// writeFile writes through a temporary file, so concurrent readers never see partially written contents.
+func (c *Cache) writeFile(path string, contents []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), tmpPrefix)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (c *Cache) blobPath(blob string) string {
+	return filepath.Join(c.Dir, blobsDir, blob[:2], blob)
+}
+
+func (c *Cache) indexPath(url string) string {
+	key := checksum([]byte(url))
+	return filepath.Join(c.Dir, indexDir, key[:2], key+".json")
+}
+
+func checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,164 @@
+package cache_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/cache"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		err   error
+		dir   string
+		ttl   time.Duration
+		cache *Cache
+	)
+
+	BeforeEach(func() {
+		dir, err = ioutil.TempDir("", "cache_test")
+		Expect(err).ToNot(HaveOccurred())
+		ttl = time.Hour
+	})
+
+	JustBeforeEach(func() {
+		cache = New(dir, ttl)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Context("given nothing was cached", func() {
+		It("should not find anything", func() {
+			_, found := cache.Get("http://localhost/foo.pom")
+			Expect(found).To(BeFalse())
+		})
+
+		It("should leave other files of the cache directory alone", func() {
+			other := filepath.Join(cache.Dir, "other.txt")
+			Expect(ioutil.WriteFile(other, []byte("keep me"), 0644)).To(Succeed())
+
+			_, err := cache.Clean(false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(other).To(BeARegularFile())
+			Expect(filepath.Join(cache.Dir, "index")).ToNot(BeADirectory())
+			Expect(filepath.Join(cache.Dir, "blobs")).ToNot(BeADirectory())
+		})
+
+		It("should report empty statistics", func() {
+			stats, err := cache.Stats()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stats).To(Equal(&Stats{}))
+		})
+	})
+
+	Context("given cached contents", func() {
+		JustBeforeEach(func() {
+			Expect(cache.Put("http://localhost/foo.pom", []byte("<project/>"), false)).To(Succeed())
+			Expect(cache.Put("http://mirror/foo.pom", []byte("<project/>"), false)).To(Succeed())
+			Expect(cache.Put("http://localhost/maven-metadata.xml", []byte("<metadata/>"), true)).To(Succeed())
+		})
+
+		It("should return them", func() {
+			contents, found := cache.Get("http://localhost/foo.pom")
+			Expect(found).To(BeTrue())
+			Expect(string(contents)).To(Equal("<project/>"))
+
+			contents, found = cache.Get("http://localhost/maven-metadata.xml")
+			Expect(found).To(BeTrue())
+			Expect(string(contents)).To(Equal("<metadata/>"))
+		})
+
+		It("should store identical contents only once", func() {
+			stats, err := cache.Stats()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stats).To(Equal(&Stats{Entries: 3, Blobs: 2, Bytes: int64(len("<project/>") + len("<metadata/>"))}))
+		})
+
+		Context("which have been corrupted", func() {
+			JustBeforeEach(func() {
+				err = filepath.Walk(filepath.Join(dir, "blobs"), func(path string, info os.FileInfo, err error) error {
+					if err != nil || info.IsDir() {
+						return err
+					}
+					return ioutil.WriteFile(path, []byte("garbage"), 0644)
+				})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should not return them", func() {
+				_, found := cache.Get("http://localhost/foo.pom")
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		Context("after the TTL passed", func() {
+			BeforeEach(func() {
+				ttl = -time.Second
+			})
+
+			It("should only expire mutable contents", func() {
+				_, found := cache.Get("http://localhost/maven-metadata.xml")
+				Expect(found).To(BeFalse())
+
+				_, found = cache.Get("http://localhost/foo.pom")
+				Expect(found).To(BeTrue())
+			})
+
+			It("should still return expired contents when asked for stale ones", func() {
+				contents, found := cache.GetStale("http://localhost/maven-metadata.xml")
+				Expect(found).To(BeTrue())
+				Expect(string(contents)).To(Equal("<metadata/>"))
+			})
+
+			It("should count expired entries", func() {
+				stats, err := cache.Stats()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stats.ExpiredEntries).To(Equal(1))
+			})
+
+			It("should be able to clean only expired entries", func() {
+				removed, err := cache.Clean(true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(removed).To(Equal(1))
+
+				stats, err := cache.Stats()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stats).To(Equal(&Stats{Entries: 2, Blobs: 1, Bytes: int64(len("<project/>"))}))
+			})
+		})
+
+		It("should be able to clean all entries", func() {
+			removed, err := cache.Clean(false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal(3))
+
+			_, found := cache.Get("http://localhost/foo.pom")
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Context("given the default directory", func() {
+		var (
+			xdgCacheHome string
+		)
+
+		BeforeEach(func() {
+			xdgCacheHome = os.Getenv("XDG_CACHE_HOME")
+			Expect(os.Setenv("XDG_CACHE_HOME", "/some/cache")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.Setenv("XDG_CACHE_HOME", xdgCacheHome)).To(Succeed())
+		})
+
+		It("should follow the XDG base directory specification", func() {
+			Expect(DefaultDir()).To(Equal("/some/cache/generate-bazel-workspace-gradle"))
+		})
+	})
+})
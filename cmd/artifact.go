@@ -3,8 +3,11 @@ package cmd
 import (
 	_ "github.com/jspawar/generate-bazel-workspace-gradle/logging"
 	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/policy"
+	"github.com/jspawar/generate-bazel-workspace-gradle/vuln"
 	"github.com/jspawar/generate-bazel-workspace-gradle/writer"
 	"github.com/spf13/cobra"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,8 +15,19 @@ import (
 
 const artifactLongHelp = ``
 
+// workspaceFormat is the default output of the artifact command, Bazel rules rather than a graph export.
+const workspaceFormat = "bzl"
+
 var (
-	searchRepositories string
+	templateFile string
+	namingScheme string
+	merge        bool
+	outputFormat string
+	policyFile   string
+	licenseRules bool
+	sbomFormats  []string
+	vulnDB       string
+	failSeverity string
 )
 
 var artifactCmd = &cobra.Command{
@@ -24,9 +38,29 @@ var artifactCmd = &cobra.Command{
 }
 
 func init() {
-	artifactCmd.Flags().StringVarP(&searchRepositories, "repos", "r",
-		"https://repo.maven.apache.org/maven2",
-		"Maven repositories to search through. First match is used.")
+	addResolutionFlags(artifactCmd)
+	artifactCmd.Flags().StringVarP(&templateFile, "template", "t", "",
+		"Go template `file` to render the dependency graph with, instead of the default Bazel rules.")
+	artifactCmd.Flags().StringVarP(&namingScheme, "naming", "n", writer.LegacyNaming,
+		"Scheme used to name generated Bazel rules, one of : "+strings.Join(writer.NamingSchemeNames(), ", "))
+	artifactCmd.Flags().BoolVarP(&merge, "merge", "m", false,
+		"Merge into a previously generated workspace file instead of overwriting it.")
+	artifactCmd.Flags().StringVarP(&outputFormat, "format", "o", workspaceFormat,
+		"Format to write, either Bazel rules or a graph export written to dependencies.<format>, one of : "+
+			strings.Join(append([]string{workspaceFormat}, writer.GraphFormatNames()...), ", "))
+	artifactCmd.Flags().StringVar(&policyFile, "policy", "",
+		"YAML or JSON policy `file` of artifacts to deny. Nothing is written if any resolved artifact is denied.")
+	artifactCmd.Flags().BoolVar(&licenseRules, "license-rules", false,
+		"Declare a rules_license license target for each artifact with recognized licenses. rules_license expects a "+
+			"LICENSE file in the package calling generated_java_libraries.")
+	artifactCmd.Flags().StringSliceVar(&sbomFormats, "sbom", nil,
+		"Also write a software bill of materials of the resolved artifacts to sbom.<format>.json, in any of : "+
+			strings.Join(writer.SBOMFormatNames(), ", "))
+	artifactCmd.Flags().StringVar(&vulnDB, "vuln-db", "",
+		"Local OSV export, a `path` to a directory or zip of JSON advisories, to match resolved artifacts against.")
+	artifactCmd.Flags().StringVar(&failSeverity, "fail-on-severity", "",
+		"Fail without writing anything if a vulnerability at or above this `severity` is found, one of : "+
+			"low, medium, high, critical. Vulnerabilities of unknown severity never fail.")
 }
 
 func artifactRunner(cmd *cobra.Command, args []string) {
@@ -35,18 +69,87 @@ func artifactRunner(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	artifactPom := maven.NewArtifact(args[0])
-	searchRepositories = strings.Replace(searchRepositories, ", ", ",", -1)
-	depWalker := &maven.DependencyWalker{
-		Repositories:     strings.Split(searchRepositories, ","),
-		RemoteRepository: maven.NewRemoteRepository(),
+	naming, err := writer.NewNamingScheme(namingScheme)
+	if err != nil {
+		logger.Errorf("Invalid flag(s) : %s", err)
+		os.Exit(1)
+	}
+	if merge && templateFile != "" {
+		logger.Errorf("Invalid flag(s) : --merge can not be combined with --template")
+		os.Exit(1)
+	}
+	if licenseRules && (templateFile != "" || outputFormat != workspaceFormat) {
+		logger.Errorf("Invalid flag(s) : --license-rules can not be combined with --template or --format")
+		os.Exit(1)
+	}
+	if outputFormat != workspaceFormat {
+		if merge || templateFile != "" {
+			logger.Errorf("Invalid flag(s) : --format can not be combined with --merge or --template")
+			os.Exit(1)
+		}
+		if _, err := writer.NewGraphWriter(ioutil.Discard, outputFormat); err != nil {
+			logger.Errorf("Invalid flag(s) : %s", err)
+			os.Exit(1)
+		}
+	}
+
+	for _, format := range sbomFormats {
+		if _, err := writer.NewSBOMWriter(ioutil.Discard, format); err != nil {
+			logger.Errorf("Invalid flag(s) : %s", err)
+			os.Exit(1)
+		}
 	}
 
-	traversedPom, err := depWalker.TraversePOM(artifactPom)
+	var dependencyPolicy *policy.Policy
+	if policyFile != "" {
+		dependencyPolicy, err = policy.Load(policyFile)
+		if err != nil {
+			logger.Errorf("Failed to configure policy : %s", err)
+			os.Exit(1)
+		}
+	}
+
+	var vulnerabilities *vuln.Database
+	var threshold vuln.Severity
+	if failSeverity != "" {
+		if vulnDB == "" {
+			logger.Errorf("Invalid flag(s) : --fail-on-severity requires --vuln-db")
+			os.Exit(1)
+		}
+		if threshold, err = vuln.ParseSeverity(failSeverity); err != nil {
+			logger.Errorf("Invalid flag(s) : %s", err)
+			os.Exit(1)
+		}
+	}
+	if vulnDB != "" {
+		vulnerabilities, err = vuln.Load(vulnDB)
+		if err != nil {
+			logger.Errorf("Failed to configure vulnerability database : %s", err)
+			os.Exit(1)
+		}
+	}
+
+	artifactPom := maven.NewArtifact(args[0])
+	depWalker := newDependencyWalker(cmd)
+
+	ctx, cancel := newContext()
+	defer cancel()
+	root, err := depWalker.ResolveTree(ctx, artifactPom)
+	exitOnResolutionError(artifactPom, err)
 	if err != nil {
 		logger.Errorf("Failed to traverse artifact [%s] : %s", artifactPom.GetMavenCoords(), err)
 		panic(err)
 	}
+	traversedPom := root.Artifact
+	if dependencyPolicy != nil {
+		if err := dependencyPolicy.Evaluate(root); err != nil {
+			logger.Errorf("Failed to resolve artifact [%s] within policy, %s", artifactPom.GetMavenCoords(), err)
+			os.Exit(1)
+		}
+	}
+	if vulnerabilities != nil {
+		exitOnVulnerabilities(artifactPom, vulnerabilities.Match(root), threshold)
+	}
 
 	// TODO: write Bazel workspace files
 	currentPath, err := os.Executable()
@@ -56,14 +159,120 @@ func artifactRunner(cmd *cobra.Command, args []string) {
 	outDir := filepath.Dir(currentPath)
 	logger.Debugf("Writing Bazel workspace file to directory : %s", outDir)
 
+	outFile := outDir + "/generate_workspace.bzl"
+	if outputFormat != workspaceFormat {
+		outFile = outDir + "/dependencies." + outputFormat
+	}
+
+	// read previously generated dependencies before the file gets truncated
+	var existing *writer.Workspace
+	if merge {
+		existing, err = readWorkspace(outFile)
+		if err != nil {
+			logger.Errorf("Failed to read existing workspace file [%s] : %s", outFile, err)
+			panic(err)
+		}
+	}
+
 	// write dependencies
-	out, err := os.Create(outDir + "/generate_workspace.bzl")
+	out, err := os.Create(outFile)
 	if err != nil {
 		panic(err)
 	}
-	wr := writer.NewWorkspaceWriter(out)
-	if err := wr.Write(traversedPom); err != nil {
+	defer out.Close()
+	wsWriter := writer.NewWorkspaceWriter(out, naming)
+	if existing != nil {
+		wsWriter = writer.NewMergingWorkspaceWriter(out, naming, existing)
+	}
+	if licenseRules {
+		wsWriter.WithLicenseRules()
+	}
+	var wr writer.Writer = wsWriter
+	if templateFile != "" {
+		wr, err = writer.NewTemplateWriter(out, templateFile, naming)
+		if err != nil {
+			panic(err)
+		}
+	}
+	if outputFormat != workspaceFormat {
+		graphWriter, err := writer.NewGraphWriter(out, outputFormat)
+		if err != nil {
+			panic(err)
+		}
+		if err := graphWriter.Write(root); err != nil {
+			panic(err)
+		}
+	} else if err := wr.Write(traversedPom); err != nil {
 		panic(err)
 	}
+	if report := wsWriter.MergeReport(); report != nil {
+		logMergeReport(report)
+	}
+	for _, format := range sbomFormats {
+		sbomFile := outDir + "/sbom." + format + ".json"
+		logger.Debugf("Writing %s SBOM to file : %s", format, sbomFile)
+		if err := writeSBOM(sbomFile, format, root); err != nil {
+			logger.Errorf("Failed to write SBOM [%s] : %s", sbomFile, err)
+			panic(err)
+		}
+	}
 	logger.Debug("Finished writing Bazel workspace files!")
 }
+
+// exitOnVulnerabilities prints the vulnerabilities found, exiting if any reaches the threshold, unless it is unset.
+func exitOnVulnerabilities(artifact *maven.Artifact, findings []*vuln.Finding, threshold vuln.Severity) {
+	if err := vuln.WriteReport(os.Stdout, findings); err != nil {
+		panic(err)
+	}
+	if threshold == vuln.SeverityUnknown {
+		return
+	}
+	failing := 0
+	for _, f := range findings {
+		if f.Severity >= threshold {
+			failing++
+		}
+	}
+	if failing > 0 {
+		logger.Errorf("Failed to resolve artifact [%s] : %d vulnerabilit(ies) at or above %s severity",
+			artifact.GetMavenCoords(), failing, threshold)
+		os.Exit(1)
+	}
+}
+
+func writeSBOM(path string, format string, root *maven.Node) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	sbomWriter, err := writer.NewSBOMWriter(out, format)
+	if err != nil {
+		return err
+	}
+	return sbomWriter.Write(root)
+}
+
+func readWorkspace(path string) (*writer.Workspace, error) {
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		logger.Debugf("No existing workspace file to merge into : %s", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return writer.ParseWorkspace(contents)
+}
+
+func logMergeReport(report *writer.MergeReport) {
+	for _, added := range report.Added {
+		logger.Infof("Added artifact : %s", added)
+	}
+	for _, updated := range report.Updated {
+		logger.Infof("Updated artifact : %s", updated)
+	}
+	for _, removed := range report.Removed {
+		logger.Warnf("Artifact is no longer resolved, kept it in case another root still needs it : %s", removed)
+	}
+}
@@ -61,42 +61,39 @@ var _ = Describe("Artifact", func() {
 				Expect(out).ToNot(BeEmpty())
 				Expect(string(out)).To(Equal(
 `def generated_maven_jars():
-  excludes = native.existing_rules().keys()
-
-  if "junit_junit" not in excludes:
-    native.maven_jar(
-        name = "junit_junit",
-        artifact = "junit:junit:4.9",
-        repository = "https://repo.maven.apache.org/maven2",
-    )
-
-  if "org_hamcrest_hamcrest_core" not in excludes:
-    native.maven_jar(
-        name = "org_hamcrest_hamcrest_core",
-        artifact = "org.hamcrest:hamcrest-core:1.1",
-        repository = "https://repo.maven.apache.org/maven2",
-    )
+    excludes = native.existing_rules().keys()
+
+    if "junit_junit" not in excludes:
+        native.maven_jar(
+            name = "junit_junit",
+            artifact = "junit:junit:4.9",
+            repository = "https://repo.maven.apache.org/maven2",
+        )
+
+    if "org_hamcrest_hamcrest_core" not in excludes:
+        native.maven_jar(
+            name = "org_hamcrest_hamcrest_core",
+            artifact = "org.hamcrest:hamcrest-core:1.1",
+            repository = "https://repo.maven.apache.org/maven2",
+        )
 
 def generated_java_libraries():
-  excludes = native.existing_rules().keys()
-
-  if "junit_junit" not in excludes:
-    native.java_library(
-        name = "junit_junit",
-        visibility = ["//visibility:public"],
-        exports = ["@junit_junit//jar"],
-        deps = [
-            ":org_hamcrest_hamcrest_core",
-        ],
-    )
-
-  if "org_hamcrest_hamcrest_core" not in excludes:
-    native.java_library(
-        name = "org_hamcrest_hamcrest_core",
-        visibility = ["//visibility:public"],
-        exports = ["@org_hamcrest_hamcrest_core//jar"],
-    )
-
+    excludes = native.existing_rules().keys()
+
+    if "junit_junit" not in excludes:
+        native.java_library(
+            name = "junit_junit",
+            visibility = ["//visibility:public"],
+            exports = ["@junit_junit//jar"],
+            deps = [":org_hamcrest_hamcrest_core"],
+        )
+
+    if "org_hamcrest_hamcrest_core" not in excludes:
+        native.java_library(
+            name = "org_hamcrest_hamcrest_core",
+            visibility = ["//visibility:public"],
+            exports = ["@org_hamcrest_hamcrest_core//jar"],
+        )
 `,
 				))
 			})
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var (
+	cleanOnlyExpired bool
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: `Manages the cache of downloaded POMs, metadata and checksums`,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: `Removes entries from the cache`,
+	Run:   cacheCleanRunner,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: `Prints statistics about the cache`,
+	Run:   cacheStatsRunner,
+}
+
+func init() {
+	cacheCleanCmd.Flags().BoolVar(&cleanOnlyExpired, "expired", false,
+		"Only remove entries which have expired.")
+
+	cacheCmd.AddCommand(cacheCleanCmd)
+	cacheCmd.AddCommand(cacheStatsCmd)
+}
+
+func cacheCleanRunner(cmd *cobra.Command, args []string) {
+	removed, err := newCache().Clean(cleanOnlyExpired)
+	if err != nil {
+		logger.Errorf("Failed to clean cache [%s] : %s", cacheDir, err)
+		os.Exit(1)
+	}
+	logger.Infof("Removed %d entries from cache : %s", removed, cacheDir)
+}
+
+func cacheStatsRunner(cmd *cobra.Command, args []string) {
+	stats, err := newCache().Stats()
+	if err != nil {
+		logger.Errorf("Failed to read cache [%s] : %s", cacheDir, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Directory:       %s\n", cacheDir)
+	fmt.Fprintf(cmd.OutOrStdout(), "Entries:         %d\n", stats.Entries)
+	fmt.Fprintf(cmd.OutOrStdout(), "Expired entries: %d\n", stats.ExpiredEntries)
+	fmt.Fprintf(cmd.OutOrStdout(), "Files:           %d\n", stats.Blobs)
+	fmt.Fprintf(cmd.OutOrStdout(), "Size:            %d bytes\n", stats.Bytes)
+}
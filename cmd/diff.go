@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const diffLongHelp = `Compares the dependency graphs of two Maven artifacts, typically two versions of the same one, resolved the same
+way as the artifact command. Either side may also be a dependency graph previously exported with --format json.
+
+Prints which transitive artifacts were added (+), removed (-), upgraded (^) or downgraded (v). Downgrades are also
+logged as warnings.
+`
+
+var diffFormat string
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: `Compares the dependency graphs of two Maven artifacts`,
+	Long:  diffLongHelp,
+	Run:   diffRunner,
+}
+
+func init() {
+	addResolutionFlags(diffCmd)
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "o", writer.TextFormat,
+		"Format of the change report, one of : "+writer.JSONFormat+", "+writer.TextFormat)
+}
+
+func diffRunner(cmd *cobra.Command, args []string) {
+	if len(args) != 2 {
+		logger.Errorf("Invalid arg(s), see correct usage below:\n%s", cmd.UsageString())
+		os.Exit(1)
+	}
+
+	diffWriter, err := writer.NewDiffWriter(os.Stdout, diffFormat)
+	if err != nil {
+		logger.Errorf("Invalid flag(s) : %s", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+	var depWalker *maven.DependencyWalker
+	graphs := make([]*writer.Graph, len(args))
+	for i, arg := range args {
+		if strings.HasSuffix(arg, ".json") {
+			contents, err := ioutil.ReadFile(arg)
+			if err == nil {
+				graphs[i], err = writer.ReadGraph(contents)
+			}
+			if err != nil {
+				logger.Errorf("Failed to read dependency graph [%s] : %s", arg, err)
+				os.Exit(1)
+			}
+			continue
+		}
+
+		if depWalker == nil {
+			depWalker = newDependencyWalker(cmd)
+		}
+		artifactPom := maven.NewArtifact(arg)
+		root, err := depWalker.ResolveTree(ctx, artifactPom)
+		exitOnResolutionError(artifactPom, err)
+		if err != nil {
+			logger.Errorf("Failed to traverse artifact [%s] : %s", artifactPom.GetMavenCoords(), err)
+			os.Exit(1)
+		}
+		graphs[i] = writer.NewGraph(root)
+	}
+
+	diff := writer.DiffGraphs(graphs[0], graphs[1])
+	for _, change := range diff.Downgraded {
+		logger.Warnf("Artifact downgraded : %s", change)
+	}
+	if err := diffWriter.Write(diff); err != nil {
+		logger.Errorf("Failed to print changes : %s", err)
+		os.Exit(1)
+	}
+}
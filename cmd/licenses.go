@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const licensesLongHelp = `Reports the licenses of a Maven artifact and of its transitive dependencies, resolved the same way as the
+artifact command. Licenses are read from the POMs, or from their nearest parent declaring any, and normalized to SPDX
+identifiers where they are recognized.
+
+The csv format lists one row per license, to be opened as a spreadsheet.
+`
+
+var licensesFormat string
+
+var licensesCmd = &cobra.Command{
+	Use:   "licenses",
+	Short: `Reports the licenses of a Maven artifact and its transitive dependencies`,
+	Long:  licensesLongHelp,
+	Run:   licensesRunner,
+}
+
+func init() {
+	addResolutionFlags(licensesCmd)
+	licensesCmd.Flags().StringVarP(&licensesFormat, "format", "o", writer.TextFormat,
+		"Format of the license report, one of : "+writer.CSVFormat+", "+writer.JSONFormat+", "+writer.TextFormat)
+}
+
+func licensesRunner(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		logger.Errorf("Invalid arg(s), see correct usage below:\n%s", cmd.UsageString())
+		os.Exit(1)
+	}
+
+	licenseWriter, err := writer.NewLicenseWriter(os.Stdout, licensesFormat)
+	if err != nil {
+		logger.Errorf("Invalid flag(s) : %s", err)
+		os.Exit(1)
+	}
+
+	artifactPom := maven.NewArtifact(args[0])
+	depWalker := newDependencyWalker(cmd)
+	ctx, cancel := newContext()
+	defer cancel()
+	traversedPom, err := depWalker.TraversePOM(ctx, artifactPom)
+	exitOnResolutionError(artifactPom, err)
+	if err != nil {
+		logger.Errorf("Failed to traverse artifact [%s] : %s", artifactPom.GetMavenCoords(), err)
+		os.Exit(1)
+	}
+
+	if err := licenseWriter.Write(traversedPom); err != nil {
+		logger.Errorf("Failed to print licenses : %s", err)
+		os.Exit(1)
+	}
+}
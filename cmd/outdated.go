@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const outdatedLongHelp = `Lists the artifacts of the dependency graph of a Maven artifact for which newer versions are available, resolved
+the same way as the artifact command. Versions are read from the maven-metadata.xml of every configured repository and
+ordered like Maven does.
+
+The newest patch, minor and major update of each artifact are reported. Pre-releases, like -alpha, -RC1 or -M1
+versions, are left out unless --pre-releases is set.
+`
+
+var (
+	outdatedFormat      string
+	outdatedPreReleases bool
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: `Lists newer versions of a Maven artifact and its transitive dependencies`,
+	Long:  outdatedLongHelp,
+	Run:   outdatedRunner,
+}
+
+func init() {
+	addResolutionFlags(outdatedCmd)
+	outdatedCmd.Flags().StringVarP(&outdatedFormat, "format", "o", writer.TextFormat,
+		"Format of the outdated report, one of : "+writer.JSONFormat+", "+writer.TextFormat)
+	outdatedCmd.Flags().BoolVar(&outdatedPreReleases, "pre-releases", false,
+		"Whether to consider pre-release versions, like -alpha, -RC1 or -M1, as updates")
+}
+
+func outdatedRunner(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		logger.Errorf("Invalid arg(s), see correct usage below:\n%s", cmd.UsageString())
+		os.Exit(1)
+	}
+
+	outdatedWriter, err := writer.NewOutdatedWriter(os.Stdout, outdatedFormat)
+	if err != nil {
+		logger.Errorf("Invalid flag(s) : %s", err)
+		os.Exit(1)
+	}
+
+	artifactPom := maven.NewArtifact(args[0])
+	depWalker := newDependencyWalker(cmd)
+	ctx, cancel := newContext()
+	defer cancel()
+	traversedPom, err := depWalker.TraversePOM(ctx, artifactPom)
+	exitOnResolutionError(artifactPom, err)
+	if err != nil {
+		logger.Errorf("Failed to traverse artifact [%s] : %s", artifactPom.GetMavenCoords(), err)
+		os.Exit(1)
+	}
+
+	updates, err := depWalker.CheckUpdates(ctx, traversedPom, outdatedPreReleases)
+	if err != nil {
+		logger.Errorf("Failed to check for newer versions : %s", err)
+		os.Exit(1)
+	}
+	if err := outdatedWriter.Write(updates); err != nil {
+		logger.Errorf("Failed to print outdated artifacts : %s", err)
+		os.Exit(1)
+	}
+}
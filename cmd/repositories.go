@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/auth"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/settings"
+	"github.com/jspawar/generate-bazel-workspace-gradle/transport"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+// repositories works out which repositories to search, and how to authenticate against them, from the `--repos` flag,
+// Maven settings and authentication flags. Repositories of the active profiles are only used when `--repos` isn't set,
+// but mirrors always apply.
+func repositories(cmd *cobra.Command) ([]*maven.Repository, []maven.RemoteRepositoryOption, error) {
+	s, err := settings.Load(settingsFile, settingsSecurityFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates := s.Repositories()
+	if cmd.Flags().Changed("repos") {
+		candidates = make([]*settings.Repository, 0)
+		for _, url := range strings.Split(strings.Replace(searchRepositories, ", ", ",", -1), ",") {
+			candidates = append(candidates, &settings.Repository{ID: repositoryID(url), URL: url})
+		}
+	}
+
+	repos := make([]*maven.Repository, 0)
+	urls := make([]string, 0)
+	servers := make([]*auth.Repository, 0)
+	for _, repository := range s.Mirror(candidates) {
+		repo, err := maven.NewRepository(repository.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+		repos = append(repos, repo)
+		urls = append(urls, repo.String())
+		if server := s.Server(repository.ID); server != nil {
+			servers = append(servers, &auth.Repository{
+				URL:      repository.URL,
+				Username: server.Username,
+				Password: server.Password,
+			})
+		}
+	}
+	logger.Debugf("Searching repositories : %s", strings.Join(urls, ", "))
+
+	authenticated, err := credentials(servers)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := transport.NewClient(httpConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	options := []maven.RemoteRepositoryOption{maven.WithHTTPClient(client)}
+	for _, c := range authenticated {
+		options = append(options, maven.WithCredentials(c.URL, c.Credentials()))
+	}
+	return repos, options, nil
+}
+
+// credentials combines the credentials of Maven settings with those of the authentication config file, environment
+// and flags, in increasing order of precedence.
+func credentials(servers []*auth.Repository) ([]*auth.Repository, error) {
+	config, err := auth.Load(authFile)
+	if err != nil {
+		return nil, err
+	}
+	fromEnv, err := auth.ParseEntries(auth.EnvEntries())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", auth.EnvVariable)
+	}
+	fromFlags, err := auth.ParseEntries(authEntries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read --auth")
+	}
+	merged := auth.Merge(servers, config.Repositories, fromEnv, fromFlags)
+	if err := auth.Validate(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// repositoryID identifies repositories given on the command line by their URL, except for Maven Central which mirrors
+// refer to by its well-known ID.
+func repositoryID(url string) string {
+	if strings.TrimSuffix(url, "/") == settings.CentralURL {
+		return settings.CentralID
+	}
+	return url
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/config"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/settings"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var (
+	searchRepositories string
+	jobs               int
+	overrideEntries    []string
+	providedAndTest    bool
+)
+
+// addResolutionFlags adds the flags of the commands resolving the dependency graph of an artifact, so that they all
+// resolve it the same way.
+func addResolutionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&searchRepositories, "repos", "r",
+		settings.CentralURL,
+		"Maven repositories to search through. First match is used. Local repositories, like ~/.m2/repository, are read from disk. "+
+			"Defaults to the repositories of the active profiles in Maven settings.")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 8,
+		"Maximum number of artifacts to fetch concurrently.")
+	cmd.Flags().StringArrayVar(&overrideEntries, "override", nil,
+		"Version to force wherever an artifact is declared, given as groupId:artifactId:`version`. "+
+			"Takes precedence over overrides of the config file.")
+	cmd.Flags().BoolVar(&providedAndTest, "include-provided-and-test", false,
+		"Also resolve the provided and test dependencies of the artifact, which its consumers never get.")
+}
+
+// newDependencyWalker configures the repositories, verification, cache, offline mode and overrides dependencies are
+// resolved with, exiting on invalid configuration.
+func newDependencyWalker(cmd *cobra.Command) *maven.DependencyWalker {
+	overrides, err := resolvedOverrides()
+	if err != nil {
+		logger.Errorf("Failed to configure overrides : %s", err)
+		os.Exit(1)
+	}
+	repos, repositoryOptions, err := repositories(cmd)
+	if err != nil {
+		logger.Errorf("Failed to configure repositories : %s", err)
+		os.Exit(1)
+	}
+	verificationOptions, err := verification()
+	if err != nil {
+		logger.Errorf("Failed to configure verification : %s", err)
+		os.Exit(1)
+	}
+	repositoryOptions = append(repositoryOptions, verificationOptions...)
+	if !noCache {
+		repositoryOptions = append(repositoryOptions, maven.WithCache(newCache()))
+	}
+	if offline {
+		repositoryOptions = append(repositoryOptions, maven.WithOffline())
+	}
+
+	return &maven.DependencyWalker{
+		Repositories:        repos,
+		Jobs:                jobs,
+		Offline:             offline,
+		Overrides:           overrides,
+		RootProvidedAndTest: providedAndTest,
+		RemoteRepository:    maven.NewRemoteRepository(repositoryOptions...),
+	}
+}
+
+// resolvedOverrides combines the overrides of the config file with those of the flags, in increasing order of precedence.
+func resolvedOverrides() (map[string]string, error) {
+	c, err := config.Load(configFile)
+	if err != nil {
+		return nil, err
+	}
+	return maven.ParseOverrides(append(append([]string{}, c.Overrides...), overrideEntries...))
+}
+
+// exitOnResolutionError reports artifacts missing offline, which isn't worth a stack trace.
+func exitOnResolutionError(artifact *maven.Artifact, err error) {
+	if missing, isMissing := err.(*maven.MissingArtifactsError); isMissing {
+		logger.Errorf("Failed to resolve artifact [%s] offline, %s", artifact.GetMavenCoords(), missing)
+		os.Exit(1)
+	}
+}
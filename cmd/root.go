@@ -1,16 +1,50 @@
 package cmd
 
 import (
+	"context"
+	"github.com/jspawar/generate-bazel-workspace-gradle/auth"
+	"github.com/jspawar/generate-bazel-workspace-gradle/cache"
+	"github.com/jspawar/generate-bazel-workspace-gradle/config"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/settings"
+	"github.com/jspawar/generate-bazel-workspace-gradle/transport"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 )
 
 var logger = zap.S()
 
+var (
+	configFile string
+
+	cacheDir string
+	cacheTTL time.Duration
+	noCache  bool
+	offline  bool
+
+	settingsFile         string
+	settingsSecurityFile string
+	authFile             string
+	authEntries          []string
+
+	httpConfig = transport.DefaultConfig()
+
+	checksumPolicy  string
+	signaturePolicy string
+	keyringFile     string
+	trustedKeys     []string
+)
+
 const rootLongHelp = `This utility is intended to assist migration of Maven/Gradle projects to Bazel.
 
 All of the subcommands output Bazel workspace files.
+
+Downloads go through the proxies set by the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
 `
 
 var rootCmd = &cobra.Command{
@@ -19,7 +53,72 @@ var rootCmd = &cobra.Command{
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", config.DefaultPath(),
+		"YAML config `file` to read version overrides from.")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", cache.DefaultDir(),
+		"Directory downloaded POMs, metadata and checksums are cached in.")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", 24*time.Hour,
+		"How long cached Maven metadata and snapshots stay valid. Releases never expire.")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false,
+		"Always download files instead of using the cache.")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false,
+		"Never access the network, only resolve from the cache and local repositories.")
+	rootCmd.PersistentFlags().StringVar(&settingsFile, "settings", settings.DefaultPath(),
+		"Maven settings `file` to read repositories, mirrors and credentials from.")
+	rootCmd.PersistentFlags().StringVar(&settingsSecurityFile, "settings-security", settings.DefaultSecurityPath(),
+		"Maven settings security `file` holding the master password encrypted passwords are decrypted with.")
+	rootCmd.PersistentFlags().StringVar(&authFile, "auth-file", auth.DefaultPath(),
+		"YAML `file` of credentials to send to repositories.")
+	rootCmd.PersistentFlags().StringArrayVar(&authEntries, "auth", nil,
+		"Credentials to send to a repository, an `entry` of the form url=basic:username:password, url=bearer:token or url=header:Name:value. "+
+			"Also read from the "+auth.EnvVariable+" environment variable, one per line. Values may refer to ${env.NAME}.")
+	rootCmd.PersistentFlags().DurationVar(&httpConfig.Timeout, "timeout", transport.DefaultTimeout,
+		"How long to wait for each attempt at downloading a file.")
+	rootCmd.PersistentFlags().DurationVar(&httpConfig.ConnectTimeout, "connect-timeout", transport.DefaultConnectTimeout,
+		"How long to wait for connections to repositories, including TLS handshakes.")
+	rootCmd.PersistentFlags().IntVar(&httpConfig.Retries, "retries", transport.DefaultRetries,
+		"How many times to retry downloads failing with transport errors, or 429 and 5xx statuses.")
+	rootCmd.PersistentFlags().StringArrayVar(&httpConfig.CABundles, "ca-bundle", nil,
+		"PEM `file` of certificate authorities to trust in addition to the system ones.")
+	rootCmd.PersistentFlags().StringVar(&checksumPolicy, "checksum-policy", string(maven.IgnorePolicy),
+		"What to do with POMs and JARs not matching their published checksum, one of : "+
+			strings.Join(maven.VerificationPolicyNames(), ", ")+". Verifying JARs means downloading them.")
+	rootCmd.PersistentFlags().StringVar(&keyringFile, "keyring", "",
+		"Keyring `file` of public keys, exported with gpg --export, to verify the PGP signatures of POMs and JARs with.")
+	rootCmd.PersistentFlags().StringArrayVar(&trustedKeys, "trusted-key", nil,
+		"Fingerprint or long key ID of a keyring `key` to trust signatures of. Defaults to trusting every key of the keyring.")
+	rootCmd.PersistentFlags().StringVar(&signaturePolicy, "signature-policy", string(maven.FailPolicy),
+		"What to do with POMs and JARs without a valid signature when a keyring is given, one of : "+
+			strings.Join(maven.VerificationPolicyNames(), ", "))
+
 	rootCmd.AddCommand(artifactCmd)
+	rootCmd.AddCommand(treeCmd)
+	rootCmd.AddCommand(whyCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(licensesCmd)
+	rootCmd.AddCommand(outdatedCmd)
+}
+
+func newCache() *cache.Cache {
+	return cache.New(cacheDir, cacheTTL)
+}
+
+// newContext is cancelled on interrupt, so that pending downloads stop straight away.
+func newContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-interrupts:
+			logger.Warn("Interrupted, cancelling pending downloads...")
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(interrupts)
+	}()
+	return ctx, cancel
 }
 
 func Execute() {
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const treeLongHelp = `Prints the dependency graph of a Maven artifact like mvn dependency:tree -Dverbose, resolved the same way as the
+artifact command. Dependencies left out of the graph are printed between parentheses, with the reason they were
+omitted : a duplicate of an artifact resolved elsewhere, a version losing to one nearer to the root, or an exclusion.
+`
+
+var (
+	treeDepth  int
+	treeFilter string
+)
+
+var treeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: `Prints the dependency graph of a single Maven artifact`,
+	Long:  treeLongHelp,
+	Run:   treeRunner,
+}
+
+func init() {
+	addResolutionFlags(treeCmd)
+	treeCmd.Flags().IntVarP(&treeDepth, "depth", "d", 0,
+		"Number of levels of dependencies to print, 0 printing all of them.")
+	treeCmd.Flags().StringVarP(&treeFilter, "filter", "f", "",
+		"Only print the paths to artifacts matching a groupId:artifactId[:version] `pattern`, where * matches any characters.")
+}
+
+func treeRunner(cmd *cobra.Command, args []string) {
+	if len(args) < 1 {
+		logger.Errorf("Invalid arg(s), see correct usage below:\n%s", cmd.UsageString())
+		os.Exit(1)
+	}
+
+	treeWriter, err := writer.NewTreeWriter(os.Stdout, treeDepth, treeFilter)
+	if err != nil {
+		logger.Errorf("Invalid flag(s) : %s", err)
+		os.Exit(1)
+	}
+
+	artifactPom := maven.NewArtifact(args[0])
+	depWalker := newDependencyWalker(cmd)
+
+	ctx, cancel := newContext()
+	defer cancel()
+	root, err := depWalker.ResolveTree(ctx, artifactPom)
+	exitOnResolutionError(artifactPom, err)
+	if err != nil {
+		logger.Errorf("Failed to traverse artifact [%s] : %s", artifactPom.GetMavenCoords(), err)
+		os.Exit(1)
+	}
+
+	if err := treeWriter.Write(root); err != nil {
+		logger.Errorf("Failed to print dependency tree : %s", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/pgp"
+	"github.com/pkg/errors"
+)
+
+// verification configures how downloaded POMs and JARs are verified against their published checksums and signatures.
+func verification() ([]maven.RemoteRepositoryOption, error) {
+	checksums, err := maven.NewVerificationPolicy(checksumPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid --checksum-policy")
+	}
+	options := []maven.RemoteRepositoryOption{maven.WithChecksums(checksums)}
+
+	if keyringFile == "" {
+		if len(trustedKeys) > 0 {
+			return nil, errors.New("--trusted-key requires --keyring")
+		}
+		return options, nil
+	}
+	signatures, err := maven.NewVerificationPolicy(signaturePolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid --signature-policy")
+	}
+	keyring, err := pgp.LoadKeyring(keyringFile, trustedKeys)
+	if err != nil {
+		return nil, err
+	}
+	return append(options, maven.WithSignatures(keyring, signatures)), nil
+}
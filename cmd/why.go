@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+const whyLongHelp = `Explains how an artifact, given as groupId:artifactId, entered the dependency graphs of the Maven
+artifacts given next, resolved the same way as the artifact command. Every path from the roots to the artifact is
+printed with the version requested on it, along with the rule which selected the resolved version : nearest wins, the
+dependencyManagement of the root, or an override.
+`
+
+var whyCmd = &cobra.Command{
+	Use:   "why",
+	Short: `Explains how an artifact entered the dependency graphs of Maven artifacts`,
+	Long:  whyLongHelp,
+	Run:   whyRunner,
+}
+
+func init() {
+	addResolutionFlags(whyCmd)
+}
+
+func whyRunner(cmd *cobra.Command, args []string) {
+	if len(args) < 2 {
+		logger.Errorf("Invalid arg(s), see correct usage below:\n%s", cmd.UsageString())
+		os.Exit(1)
+	}
+
+	out := cmd.OutOrStdout()
+	whyWriter, err := writer.NewWhyWriter(out, args[0])
+	if err != nil {
+		logger.Errorf("Invalid arg(s) : %s", err)
+		os.Exit(1)
+	}
+
+	depWalker := newDependencyWalker(cmd)
+	ctx, cancel := newContext()
+	defer cancel()
+	for i, coords := range args[1:] {
+		artifactPom := maven.NewArtifact(coords)
+		root, err := depWalker.ResolveTree(ctx, artifactPom)
+		exitOnResolutionError(artifactPom, err)
+		if err != nil {
+			logger.Errorf("Failed to traverse artifact [%s] : %s", artifactPom.GetMavenCoords(), err)
+			os.Exit(1)
+		}
+
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		if err := whyWriter.Write(root); err != nil {
+			logger.Errorf("Failed to print dependency paths : %s", err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const appName = `generate-bazel-workspace-gradle`
+
+// Config holds settings for every run, read from a YAML file like:
+//
+//	overrides:
+//	  - com.fasterxml.jackson.core:jackson-databind:2.9.10.8
+type Config struct {
+	// Overrides force versions of artifacts, given as groupId:artifactId:version.
+	Overrides []string `yaml:"overrides"`
+}
+
+// DefaultPath follows the XDG base directory specification, falling back to `~/.config`.
+func DefaultPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, appName, "config.yml")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".config", appName, "config.yml")
+}
+
+// Load reads a config file, treating a missing file as an empty config.
+func Load(path string) (*Config, error) {
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read config [%s]", path)
+	}
+
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(contents, c); err != nil {
+		return nil, errors.Errorf("error parsing config [%s] : %s", path, err)
+	}
+	return c, nil
+}
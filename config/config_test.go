@@ -0,0 +1,55 @@
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/config"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("Config", func() {
+	var (
+		dir string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "config_test")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	load := func(contents string) (*Config, error) {
+		path := filepath.Join(dir, "config.yml")
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return Load(path)
+	}
+
+	It("should read overrides", func() {
+		config, err := load(`overrides:
+  - com.fasterxml.jackson.core:jackson-databind:2.9.10.8
+`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Overrides).To(Equal([]string{"com.fasterxml.jackson.core:jackson-databind:2.9.10.8"}))
+	})
+
+	It("should treat a missing file as empty", func() {
+		config, err := Load(filepath.Join(dir, "missing.yml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.Overrides).To(BeEmpty())
+	})
+
+	It("should refuse unknown sections", func() {
+		_, err := load(`override:
+  - com.fasterxml.jackson.core:jackson-databind:2.9.10.8
+`)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("error parsing config ["))
+	})
+})
@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/redact"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -13,7 +14,41 @@ func init() {
 	logConfig := zap.NewDevelopmentConfig()
 	logConfig.EncoderConfig = logEncConfig
 
-	// replace global zap logger
-	globalLogger, _ := logConfig.Build()
+	// replace global zap logger, making sure credentials never end up in logs
+	globalLogger, _ := logConfig.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &redactingCore{core}
+	}))
 	zap.ReplaceGlobals(globalLogger)
 }
+
+// redactingCore masks secrets in messages and string fields before they are written.
+type redactingCore struct {
+	zapcore.Core
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{c.Core.With(redactFields(fields))}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = redact.String(entry.Message)
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		if field.Type == zapcore.StringType {
+			field.String = redact.String(field.String)
+		}
+		redacted[i] = field
+	}
+	return redacted
+}
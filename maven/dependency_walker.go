@@ -1,28 +1,66 @@
 package maven
 
 import (
+	"context"
 	_ "github.com/jspawar/generate-bazel-workspace-gradle/logging"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"sync"
 )
 
 var (
 	logger = zap.S()
 )
 
-// TODO: refactor this to instead have an array of `RemoteRepository` instances which are constructed with the actual remote's URL
 type DependencyWalker struct {
-	Repositories []string
-	cache        map[string]string
+	Repositories []*Repository
+	// Jobs limits how many artifacts are fetched at the same time, anything below 1 fetches them one by one.
+	Jobs int
+	// Offline keeps resolving past missing artifacts, so that all of them are reported at once.
+	Offline bool
+	// Overrides force the versions of artifacts, by groupId:artifactId, wherever they are declared.
+	Overrides map[string]string
+	// RootProvidedAndTest keeps the provided and test dependencies of the root, which consumers of it never get.
+	RootProvidedAndTest bool
+	cache               map[string]string
+	cacheLock           sync.Mutex
+	// nodes are the resolved nodes of the graph by versionless coordinates, only used between levels
+	nodes map[string]*Node
+	// root is the POM being resolved, the dependencyManagement of which applies to the whole graph
+	root *Artifact
 	RemoteRepository
 }
 
-func (w *DependencyWalker) TraversePOM(pom *Artifact) (*Artifact, error) {
-	repository := w.Repositories[0]
+// dependencyRequest is a dependency waiting to be fetched, along with the artifact that declared it.
+type dependencyRequest struct {
+	dependent *Artifact
+	artifact  *Artifact
+	scope     string
+	node      *Node
+	fetched   *Artifact
+	err       error
+}
 
-	logger.Debugf("Searching for POM in repository : %s", repository)
-	remotePom, err := w.RemoteRepository.FetchRemoteModel(pom, repository)
+// TraversePOM resolves the transitive dependencies of an artifact breadth first, one level of the graph at a time.
+// All artifacts of a level are fetched concurrently, but the graph is always assembled in declaration order so the
+// result does not depend on scheduling: the artifact nearest to the root wins, and the first declaration breaks ties.
+func (w *DependencyWalker) TraversePOM(ctx context.Context, pom *Artifact) (*Artifact, error) {
+	root, err := w.ResolveTree(ctx, pom)
 	if err != nil {
+		return nil, err
+	}
+	return root.Artifact, nil
+}
+
+// ResolveTree resolves the transitive dependencies of an artifact exactly like TraversePOM, but also keeps track of
+// the dependencies left out of the graph.
+func (w *DependencyWalker) ResolveTree(ctx context.Context, pom *Artifact) (*Node, error) {
+	remotePom, repository, err := w.fetchModel(ctx, pom)
+	if err != nil {
+		if w.Offline && ctx.Err() == nil {
+			logger.Debugf("Failed to fetch POM [%s] : %s", pom.GetMavenCoords(), err)
+			return nil, &MissingArtifactsError{POMs: []string{pom.GetMavenCoords()}}
+		}
 		return nil, errors.Wrapf(err,
 			"Failed to traverse POM [%s] with configured search repositories",
 			pom.GetMavenCoords())
@@ -30,67 +68,272 @@ func (w *DependencyWalker) TraversePOM(pom *Artifact) (*Artifact, error) {
 	pom = remotePom
 
 	// initialize cache
-	pom.Repository = repository
-	deps := make([]*Artifact, 0)
-	w.cache = map[string]string{pom.GetBazelRule(): repository}
+	published := w.publishedRepository(repository)
+	pom.Repository = published.String()
+	if err := w.checkJAR(ctx, pom, repository, published); err != nil {
+		return nil, errors.Wrapf(err,
+			"Failed to traverse POM [%s] with configured search repositories",
+			pom.GetMavenCoords())
+	}
+	w.cache = map[string]string{}
+	w.markDiscovered(pom, repository.String())
+	root := &Node{Artifact: pom, Requested: pom.Version, Selection: SelectedNearest}
+	w.root = pom
+	w.nodes = map[string]*Node{pom.GetVersionlessCoords(): root}
+
+	missing := &MissingArtifactsError{}
+	w.checkMissingJAR(pom, missing)
 
 	logger.Debug("Traversing dependencies...")
-	for _, dep := range pom.Dependencies {
-		// ignore test dependencies for now
-		if !dep.Optional && pom.Scope != "test" {
-			logger.Debugf("Traversing dependency : %s", dep.GetMavenCoords())
-			traversedDep, err := w.traverseArtifact(dep)
-			if err != nil {
-				return nil, errors.Wrapf(err,
+	for level := []*Node{root}; len(level) > 0; {
+		requests := w.nextRequests(level)
+		w.fetchAll(ctx, requests)
+
+		level = make([]*Node, 0, len(requests))
+		for _, req := range requests {
+			if req.err != nil && w.Offline && ctx.Err() == nil {
+				logger.Debugf("Failed to fetch POM [%s] : %s", req.artifact.GetMavenCoords(), req.err)
+				missing.POMs = append(missing.POMs, req.artifact.GetMavenCoords())
+				continue
+			}
+			if req.err != nil {
+				return nil, errors.Wrapf(req.err,
 					"Failed to fetch POM [%s] from configured search repositories",
-					dep.GetMavenCoords())
+					req.artifact.GetMavenCoords())
+			}
+			w.checkMissingJAR(req.fetched, missing)
+			req.dependent.Dependencies = append(req.dependent.Dependencies, req.fetched)
+			req.node.Artifact = req.fetched
+			if req.node.Selection == SelectedOverride {
+				req.fetched.Forced, req.fetched.ForcedFrom = true, req.node.Requested
+			}
+			level = append(level, req.node)
+		}
+	}
+
+	if !missing.isEmpty() {
+		missing.sort()
+		return nil, missing
+	}
+	return root, nil
+}
+
+// checkMissingJAR records the JAR of an artifact packaged as one which could not be found offline.
+func (w *DependencyWalker) checkMissingJAR(artifact *Artifact, missing *MissingArtifactsError) {
+	if w.Offline && artifact.SHA == "" && artifact.IsJAR() {
+		missing.JARs = append(missing.JARs, artifact.GetMavenCoords())
+	}
+}
+
+// nextRequests collects the dependencies declared by a level of the graph which haven't been discovered yet, and
+// clears the declared dependencies so they can be replaced with the fetched ones. Every dependency which is part of
+// the graph gets a node, whether it is going to be fetched or was omitted.
+func (w *DependencyWalker) nextRequests(level []*Node) []*dependencyRequest {
+	requests := make([]*dependencyRequest, 0)
+	for _, node := range level {
+		artifact := node.Artifact
+		declared := artifact.Dependencies
+		artifact.Dependencies = make([]*Artifact, 0)
+
+		for _, dep := range declared {
+			scope := w.scope(node, dep)
+			if scope == "" {
+				continue
+			}
+			if node.excludes(dep) {
+				logger.Debugf("Artifact excluded : %s", dep.GetMavenCoords())
+				node.Children = append(node.Children, &Node{Artifact: dep, Scope: scope, Omitted: OmittedExcluded,
+					Requested: dep.Version})
+				continue
 			}
-			if traversedDep != nil {
-				deps = append(deps, traversedDep)
+			if dep.Optional {
+				node.Children = append(node.Children, &Node{Artifact: dep, Scope: scope, Omitted: OmittedOptional,
+					Requested: dep.Version})
+				continue
 			}
+			requested := dep.Version
+			selection := w.selectVersion(node, dep)
+			// check cache to avoid unnecessary traversal
+			if !w.markDiscovered(dep, "") {
+				logger.Debugf("Artifact already discovered : %s", dep.GetMavenCoords())
+				omitted := w.omitted(dep, scope)
+				omitted.Requested = requested
+				node.Children = append(node.Children, omitted)
+				continue
+			}
+
+			child := &Node{Artifact: dep, Scope: scope, Requested: requested, Selection: selection}
+			child.exclusions = append(append(child.exclusions, node.exclusions...), dep.Exclusions...)
+			node.Children = append(node.Children, child)
+			w.nodes[dep.GetVersionlessCoords()] = child
+			requests = append(requests, &dependencyRequest{dependent: artifact, artifact: dep, scope: scope, node: child})
+		}
+	}
+	return requests
+}
+
+// scope returns the scope a dependency is resolved with, keeping the provided and test dependencies of the root when
+// asked to.
+func (w *DependencyWalker) scope(dependent *Node, dep *Artifact) string {
+	if w.RootProvidedAndTest && dependent.Artifact == w.root && (dep.Scope == ScopeProvided || dep.Scope == ScopeTest) {
+		return dep.Scope
+	}
+	return TransitiveScope(dependent.Artifact.Scope, dep.Scope)
+}
+
+// selectVersion applies overrides to any dependency, then the dependencyManagement of the root to transitive
+// dependencies, the versions of which it overrides, unlike those the root declares itself.
+func (w *DependencyWalker) selectVersion(dependent *Node, dep *Artifact) Selection {
+	if version, isOverridden := w.Overrides[dep.GetVersionlessCoords()]; isOverridden {
+		if version != dep.Version {
+			logger.Debugf("Artifact version forced by override : %s -> %s", dep.GetMavenCoords(), version)
 		}
+		dep.Version = version
+		return SelectedOverride
 	}
-	pom.Dependencies = deps
+	if dependent.Artifact == w.root {
+		return SelectedNearest
+	}
+	managed := w.root.ManagedVersion(dep)
+	if managed == nil || managed.Version == dep.Version {
+		return SelectedNearest
+	}
+	logger.Debugf("Artifact version managed by root : %s -> %s", dep.GetMavenCoords(), managed.Version)
+	dep.Version = managed.Version
+	return SelectedManaged
+}
 
-	return pom, nil
+// omitted records a dependency on an artifact discovered before, which lost to the version nearest to the root.
+func (w *DependencyWalker) omitted(dep *Artifact, scope string) *Node {
+	omitted := &Node{Artifact: dep, Scope: scope, Omitted: OmittedDuplicate}
+	if winner, isResolved := w.nodes[dep.GetVersionlessCoords()]; isResolved {
+		omitted.Winner = winner
+		if winner.Artifact.Version != dep.Version {
+			omitted.Omitted = OmittedConflict
+		}
+	}
+	return omitted
 }
 
-func (w *DependencyWalker) traverseArtifact(artifact *Artifact) (*Artifact, error) {
-	// check cache to avoid unnecessary traversal
-	if _, isCached := w.cache[artifact.GetBazelRule()]; isCached {
-		logger.Debugf("Artifact already discovered : %s", artifact.GetMavenCoords())
-		// TODO: sufficient to return nil and not append this to list of dependencies for caller?
-		return nil, nil
+// fetchAll fetches every request with a bounded pool of workers, storing the outcome on the request itself.
+func (w *DependencyWalker) fetchAll(ctx context.Context, requests []*dependencyRequest) {
+	jobs := w.Jobs
+	if jobs < 1 {
+		jobs = 1
 	}
 
-	repository := w.Repositories[0]
+	queue := make(chan *dependencyRequest)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < jobs && i < len(requests); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range queue {
+				req.fetched, req.err = w.traverseArtifact(ctx, req.artifact, req.scope)
+			}
+		}()
+	}
+	for _, req := range requests {
+		queue <- req
+	}
+	close(queue)
+	wg.Wait()
+}
 
-	logger.Infof("Searching for artifact [%s] in repository : %s", artifact.GetMavenCoords(), repository)
-	remoteArtifact, err := w.RemoteRepository.FetchRemoteModel(artifact, repository)
+func (w *DependencyWalker) traverseArtifact(ctx context.Context, artifact *Artifact, scope string) (*Artifact, error) {
+	remoteArtifact, repository, err := w.fetchModel(ctx, artifact)
 	if err != nil {
 		return nil, err
 	}
 	artifact = remoteArtifact
 
-	// can safely add this artifact to result slice
-	w.cache[artifact.GetBazelRule()] = repository
-	artifact.Repository = repository
-	deps := make([]*Artifact, 0)
-
-	for _, dep := range artifact.Dependencies {
-		// ignore test dependencies for now
-		if !dep.Optional && artifact.Scope != "test" {
-			traversedDep, err := w.traverseArtifact(dep)
-			if err != nil {
-				return nil, err
-			}
-			// only append to list of remote dependencies if it hasn't been discovered already
-			if traversedDep != nil {
-				deps = append(deps, traversedDep)
-			}
-		}
+	w.markDiscovered(artifact, repository.String())
+	published := w.publishedRepository(repository)
+	artifact.Repository = published.String()
+	artifact.Scope = scope
+	if err := w.checkJAR(ctx, artifact, repository, published); err != nil {
+		return nil, err
 	}
-	artifact.Dependencies = deps
 
 	return artifact, nil
 }
+
+// fetchModel searches the configured repositories in order, returning the model from the first one which has it.
+func (w *DependencyWalker) fetchModel(ctx context.Context, artifact *Artifact) (*Artifact, *Repository, error) {
+	var lastErr error
+	for _, repository := range w.Repositories {
+		logger.Debugf("Searching for artifact [%s] in repository : %s", artifact.GetMavenCoords(), repository)
+		// fetching may fill in a missing version, so keep the declared one intact for the next repository
+		declared := *artifact
+		remoteArtifact, err := w.RemoteRepository.FetchRemoteModel(ctx, &declared, repository)
+		if err == nil {
+			return remoteArtifact, repository, nil
+		}
+		// there is no point asking other repositories once cancelled
+		if ctx.Err() != nil {
+			return nil, nil, err
+		}
+		// other repositories may well have what this one doesn't, but a repository failing to serve what it has
+		// would otherwise silently resolve the artifact elsewhere
+		if IsUnauthorized(err) || IsTransport(err) || IsParse(err) || IsIntegrity(err) {
+			return nil, nil, err
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no repositories configured")
+	}
+	return nil, nil, lastErr
+}
+
+// publishedRepository is the repository written to the generated workspace for an artifact found in `repository`.
+// Bazel can't download from a local repository, so those fall back to the first remote repository configured.
+func (w *DependencyWalker) publishedRepository(repository *Repository) *Repository {
+	if !repository.IsLocal() {
+		return repository
+	}
+	for _, r := range w.Repositories {
+		if !r.IsLocal() {
+			return r
+		}
+	}
+	return repository
+}
+
+// markDiscovered records the repository an artifact was found in, returning false if it had been discovered before.
+func (w *DependencyWalker) markDiscovered(artifact *Artifact, repository string) bool {
+	w.cacheLock.Lock()
+	defer w.cacheLock.Unlock()
+
+	key := artifact.GetVersionlessCoords()
+	previous, isCached := w.cache[key]
+	if !isCached || previous == "" {
+		w.cache[key] = repository
+	}
+	return !isCached
+}
+
+// checkJAR records the checksum of the JAR of an artifact. JARs which fail verification are an error, but missing
+// ones are not.
+func (w *DependencyWalker) checkJAR(ctx context.Context, artifact *Artifact, repository *Repository,
+	published *Repository) error {
+	sha, err := w.RemoteRepository.CheckRemoteJAR(ctx, artifact, repository)
+	// a local repository may only hold the POM of an artifact
+	if err != nil && !IsIntegrity(err) && published != repository {
+		sha, err = w.RemoteRepository.CheckRemoteJAR(ctx, artifact, published)
+	}
+	if IsIntegrity(err) {
+		return err
+	}
+	if err != nil {
+		// not every artifact is packaged as a JAR, so this alone is not a reason to fail
+		if !IsNotFound(err) && !IsOffline(err) {
+			logger.Warnf("Could not check JAR checksum for artifact [%s] : %s", artifact.GetMavenCoords(), err)
+			return nil
+		}
+		logger.Debugf("Could not find JAR checksum for artifact [%s] : %s", artifact.GetMavenCoords(), err)
+		return nil
+	}
+	artifact.SHA = sha
+	return nil
+}
@@ -5,19 +5,26 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
+	"context"
+	"fmt"
 	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
 	"github.com/jspawar/generate-bazel-workspace-gradle/maven/mavenfakes"
 	"github.com/pkg/errors"
+	"strings"
 )
 
 var _ = Describe("DependencyWalker", func() {
 	var (
 		err              error
-		repositories     []string
+		repositories     []*Repository
 		remoteRepository *mavenfakes.FakeRemoteRepository
 		walker           *DependencyWalker
 		pom              *Artifact
 		returnedPom      *Artifact
+		jobs             int
+		offline          bool
+		overrides        map[string]string
+		providedAndTest  bool
 	)
 
 	BeforeEach(func() {
@@ -38,8 +45,15 @@ var _ = Describe("DependencyWalker", func() {
 	})
 
 	JustBeforeEach(func() {
-		walker = &DependencyWalker{Repositories: repositories, RemoteRepository: remoteRepository}
-		returnedPom, err = walker.TraversePOM(pom)
+		walker = &DependencyWalker{
+			Repositories:        repositories,
+			Jobs:                jobs,
+			Offline:             offline,
+			Overrides:           overrides,
+			RootProvidedAndTest: providedAndTest,
+			RemoteRepository:    remoteRepository,
+		}
+		returnedPom, err = walker.TraversePOM(context.Background(), pom)
 	})
 
 	Context("Given a single repository search", func() {
@@ -47,7 +61,7 @@ var _ = Describe("DependencyWalker", func() {
 			remoteRepository.FetchRemoteModelReturnsOnCall(0, pom, nil)
 			remoteRepository.FetchRemoteModelReturnsOnCall(1, pom.Dependencies[0], nil)
 
-			repositories = []string{"http://localhost:8080/"}
+			repositories = []*Repository{newRepository("http://localhost:8080/")}
 		})
 
 		Context("where all dependencies are available in the one repository", func() {
@@ -60,18 +74,44 @@ var _ = Describe("DependencyWalker", func() {
 					"ArtifactID": Equal("junit"),
 					"Version":    Equal("4.9"),
 					"Scope":      BeEmpty(),
-					"Repository": Equal(repositories[0]),
+					"Repository": Equal("http://localhost:8080"),
 				})))
 				Expect(returnedPom.Dependencies).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
 					"GroupID":    Equal("org.hamcrest"),
 					"ArtifactID": Equal("hamcrest-core"),
 					"Version":    Equal("1.1"),
 					"Scope":      Equal("compile"),
-					"Repository": Equal(repositories[0]),
+					"Repository": Equal("http://localhost:8080"),
 				}))))
 			})
 		})
 
+		Context("where the JARs of all dependencies are available", func() {
+			BeforeEach(func() {
+				remoteRepository.CheckRemoteJARReturns("some-sha1", nil)
+			})
+
+			It("should record the checksum of every JAR", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(returnedPom.SHA).To(Equal("some-sha1"))
+				Expect(returnedPom.Dependencies[0].SHA).To(Equal("some-sha1"))
+			})
+		})
+
+		Context("where the JAR of a dependency is NOT available", func() {
+			BeforeEach(func() {
+				remoteRepository.CheckRemoteJARReturnsOnCall(1, "", errors.New("oh no"))
+			})
+
+			It("should still return all transitive dependencies without error", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(returnedPom.Dependencies).To(HaveLen(1))
+				Expect(returnedPom.Dependencies[0].SHA).To(BeEmpty())
+			})
+		})
+
 		Context("where all dependencies are NOT available in the one repository", func() {
 			BeforeEach(func() {
 				remoteRepository.FetchRemoteModelReturnsOnCall(0, nil, errors.New("oh no"))
@@ -109,7 +149,7 @@ var _ = Describe("DependencyWalker", func() {
 					"ArtifactID": Equal("junit"),
 					"Version":    Equal("4.9"),
 					"Scope":      BeEmpty(),
-					"Repository": Equal(repositories[0]),
+					"Repository": Equal("http://localhost:8080"),
 				})))
 				Expect(returnedPom.Dependencies).To(BeEmpty())
 			})
@@ -129,16 +169,491 @@ var _ = Describe("DependencyWalker", func() {
 					"ArtifactID": Equal("junit"),
 					"Version":    Equal("4.9"),
 					"Scope":      BeEmpty(),
-					"Repository": Equal(repositories[0]),
+					"Repository": Equal("http://localhost:8080"),
 				})))
 				Expect(returnedPom.Dependencies).To(BeEmpty())
 			})
 		})
+
+		Context("where dependencies are declared with different scopes", func() {
+			var (
+				runtimeDep *Artifact
+			)
+
+			BeforeEach(func() {
+				runtimeDep = &Artifact{
+					GroupID:    "org.fake",
+					ArtifactID: "runtime-only",
+					Version:    "2.0",
+					Scope:      "runtime",
+					Dependencies: []*Artifact{
+						{GroupID: "org.fake", ArtifactID: "compiled", Version: "1.0"},
+						{GroupID: "org.fake", ArtifactID: "tested", Version: "1.0", Scope: "test"},
+					},
+				}
+				pom.Dependencies = append(pom.Dependencies, runtimeDep)
+
+				remoteRepository.FetchRemoteModelReturnsOnCall(2, runtimeDep, nil)
+				remoteRepository.FetchRemoteModelReturnsOnCall(3, runtimeDep.Dependencies[0], nil)
+			})
+
+			It("should resolve the effective scope of every transitive dependency", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(returnedPom.Dependencies).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"ArtifactID": Equal("hamcrest-core"),
+						"Scope":      Equal("compile"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"ArtifactID": Equal("runtime-only"),
+						"Scope":      Equal("runtime"),
+						"Dependencies": ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+							"ArtifactID": Equal("compiled"),
+							"Scope":      Equal("runtime"),
+						}))),
+					})),
+				))
+				Expect(remoteRepository.FetchRemoteModelCallCount()).To(Equal(4))
+			})
+		})
+
+		Context("where the root declares provided and test dependencies", func() {
+			var (
+				testDep *Artifact
+			)
+
+			BeforeEach(func() {
+				testDep = &Artifact{GroupID: "org.fake", ArtifactID: "tested", Version: "1.0", Scope: "test"}
+				pom.Dependencies = append(pom.Dependencies,
+					&Artifact{GroupID: "org.fake", ArtifactID: "provided", Version: "1.0", Scope: "provided"},
+					testDep)
+
+				remoteRepository.FetchRemoteModelReturnsOnCall(2, testDep, nil)
+			})
+
+			AfterEach(func() {
+				providedAndTest = false
+			})
+
+			It("should leave them out of the graph", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(returnedPom.Dependencies).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"ArtifactID": Equal("hamcrest-core"),
+				}))))
+				Expect(remoteRepository.FetchRemoteModelCallCount()).To(Equal(2))
+			})
+
+			Context("and they are asked for", func() {
+				BeforeEach(func() {
+					providedAndTest = true
+					remoteRepository.FetchRemoteModelReturnsOnCall(2, pom.Dependencies[1], nil)
+					remoteRepository.FetchRemoteModelReturnsOnCall(3, testDep, nil)
+				})
+
+				It("should keep them with their declared scope", func() {
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(returnedPom.Dependencies).To(ConsistOf(
+						PointTo(MatchFields(IgnoreExtras, Fields{
+							"ArtifactID": Equal("hamcrest-core"),
+							"Scope":      Equal("compile"),
+						})),
+						PointTo(MatchFields(IgnoreExtras, Fields{
+							"ArtifactID": Equal("provided"),
+							"Scope":      Equal("provided"),
+						})),
+						PointTo(MatchFields(IgnoreExtras, Fields{
+							"ArtifactID": Equal("tested"),
+							"Scope":      Equal("test"),
+						})),
+					))
+				})
+			})
+		})
 	})
 
-	PContext("Given a multiple repository search", func() {
-		Context("where all dependencies are available in any of the provided repositories", func() {})
+	Context("Given a graph fetched concurrently", func() {
+		var (
+			poms map[string]*Artifact
+		)
+
+		BeforeEach(func() {
+			repositories = []*Repository{newRepository("http://localhost:8080/")}
+			jobs = 4
+
+			// root -> a -> c:1 -> e
+			//      -> b -> c:2
+			//      -> d
+			//      -> f -> d
+			poms = map[string]*Artifact{}
+			for _, a := range []*Artifact{
+				{GroupID: "org.fake", ArtifactID: "root", Version: "1", Dependencies: []*Artifact{
+					{GroupID: "org.fake", ArtifactID: "a", Version: "1"},
+					{GroupID: "org.fake", ArtifactID: "b", Version: "1"},
+					{GroupID: "org.fake", ArtifactID: "f", Version: "1"},
+					{GroupID: "org.fake", ArtifactID: "d", Version: "1"},
+				}},
+				{GroupID: "org.fake", ArtifactID: "a", Version: "1", Dependencies: []*Artifact{
+					{GroupID: "org.fake", ArtifactID: "c", Version: "1"},
+				}},
+				{GroupID: "org.fake", ArtifactID: "b", Version: "1", Dependencies: []*Artifact{
+					{GroupID: "org.fake", ArtifactID: "c", Version: "2"},
+				}},
+				{GroupID: "org.fake", ArtifactID: "c", Version: "1", Dependencies: []*Artifact{
+					{GroupID: "org.fake", ArtifactID: "e", Version: "1"},
+				}},
+				{GroupID: "org.fake", ArtifactID: "c", Version: "2"},
+				{GroupID: "org.fake", ArtifactID: "d", Version: "1"},
+				{GroupID: "org.fake", ArtifactID: "e", Version: "1"},
+				{GroupID: "org.fake", ArtifactID: "f", Version: "1", Dependencies: []*Artifact{
+					{GroupID: "org.fake", ArtifactID: "d", Version: "1"},
+				}},
+			} {
+				poms[a.GetMavenCoords()] = a
+			}
+			remoteRepository.FetchRemoteModelStub = func(_ context.Context, artifact *Artifact, _ *Repository) (*Artifact, error) {
+				pom, ok := poms[artifact.GetMavenCoords()]
+				if !ok {
+					return nil, errors.New("not found")
+				}
+				// hand out copies, like a real repository would
+				fetched := *pom
+				fetched.Dependencies = make([]*Artifact, len(pom.Dependencies))
+				for i, dep := range pom.Dependencies {
+					d := *dep
+					fetched.Dependencies[i] = &d
+				}
+				return &fetched, nil
+			}
+			pom = &Artifact{GroupID: "org.fake", ArtifactID: "root", Version: "1"}
+		})
 
-		Context("where all dependencies are available in only one of the provided repositories", func() {})
+		AfterEach(func() {
+			jobs = 0
+		})
+
+		coordsOf := func(artifacts []*Artifact) []string {
+			coords := make([]string, 0, len(artifacts))
+			for _, a := range artifacts {
+				coords = append(coords, a.GetMavenCoords())
+			}
+			return coords
+		}
+
+		It("should pick the nearest artifact, breaking ties by declaration order", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(coordsOf(returnedPom.Dependencies)).To(Equal([]string{
+				"org.fake:a:1", "org.fake:b:1", "org.fake:f:1", "org.fake:d:1",
+			}))
+			Expect(coordsOf(returnedPom.Dependencies[0].Dependencies)).To(Equal([]string{"org.fake:c:1"}))
+			Expect(returnedPom.Dependencies[1].Dependencies).To(BeEmpty())
+			Expect(returnedPom.Dependencies[2].Dependencies).To(BeEmpty())
+			Expect(coordsOf(returnedPom.Dependencies[0].Dependencies[0].Dependencies)).To(Equal([]string{"org.fake:e:1"}))
+
+			// every artifact is only fetched once
+			Expect(remoteRepository.FetchRemoteModelCallCount()).To(Equal(7))
+		})
+
+		Context("when resolving the tree of the graph", func() {
+			var (
+				root *Node
+			)
+
+			JustBeforeEach(func() {
+				root, err = walker.ResolveTree(context.Background(), &Artifact{GroupID: "org.fake", ArtifactID: "root", Version: "1"})
+			})
+
+			It("should keep the dependencies omitted from the graph", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				lines := make([]string, 0)
+				root.Walk(func(node *Node, depth int) bool {
+					line := fmt.Sprintf("%d %s %s", depth, node.Artifact.GetMavenCoords(), node.Omitted)
+					if node.Winner != nil {
+						line += " " + node.Winner.Artifact.GetMavenCoords()
+					}
+					lines = append(lines, strings.TrimSpace(line))
+					return true
+				})
+				Expect(lines).To(Equal([]string{
+					"0 org.fake:root:1",
+					"1 org.fake:a:1",
+					"2 org.fake:c:1",
+					"3 org.fake:e:1",
+					"1 org.fake:b:1",
+					"2 org.fake:c:2 conflict org.fake:c:1",
+					"1 org.fake:f:1",
+					"2 org.fake:d:1 duplicate org.fake:d:1",
+					"1 org.fake:d:1",
+				}))
+				Expect(root.Children[0].Scope).To(Equal("compile"))
+				Expect(root.Children[0].Artifact).To(BeIdenticalTo(root.Artifact.Dependencies[0]))
+			})
+
+			Context("where a dependency is optional", func() {
+				BeforeEach(func() {
+					poms["org.fake:d:1"].Dependencies = []*Artifact{
+						{GroupID: "org.fake", ArtifactID: "g", Version: "1", Optional: true},
+					}
+				})
+
+				It("should keep it as omitted without resolving it", func() {
+					Expect(err).ToNot(HaveOccurred())
+
+					optional := root.Children[3].Children[0]
+					Expect(optional.Artifact.GetMavenCoords()).To(Equal("org.fake:g:1"))
+					Expect(optional.Omitted).To(Equal(OmittedOptional))
+					Expect(optional.Scope).To(Equal("compile"))
+					Expect(root.Children[3].Artifact.Dependencies).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("where the root manages the versions of dependencies", func() {
+			BeforeEach(func() {
+				poms["org.fake:root:1"].DependencyManagement = []*Artifact{
+					{GroupID: "org.fake", ArtifactID: "c", Version: "2"},
+					{GroupID: "org.fake", ArtifactID: "d", Version: "9"},
+				}
+			})
+
+			AfterEach(func() {
+				poms["org.fake:root:1"].DependencyManagement = nil
+			})
+
+			It("should override the versions of transitive dependencies only", func() {
+				root, err := walker.ResolveTree(context.Background(), &Artifact{GroupID: "org.fake", ArtifactID: "root", Version: "1"})
+				Expect(err).ToNot(HaveOccurred())
+
+				c := root.Children[0].Children[0]
+				Expect(c.Artifact.GetMavenCoords()).To(Equal("org.fake:c:2"))
+				Expect(c.Requested).To(Equal("1"))
+				Expect(c.Selection).To(Equal(SelectedManaged))
+				Expect(c.Children).To(BeEmpty())
+				Expect(root.Children[1].Children[0].Omitted).To(Equal(OmittedDuplicate))
+
+				d := root.Children[3]
+				Expect(d.Artifact.GetMavenCoords()).To(Equal("org.fake:d:1"))
+				Expect(d.Selection).To(Equal(SelectedNearest))
+				Expect(root.Children[2].Children[0].Omitted).To(Equal(OmittedConflict))
+			})
+		})
+
+		Context("where versions are overridden", func() {
+			BeforeEach(func() {
+				poms["org.fake:root:1"].DependencyManagement = []*Artifact{
+					{GroupID: "org.fake", ArtifactID: "c", Version: "1"},
+				}
+				overrides = map[string]string{"org.fake:c": "2", "org.fake:d": "1"}
+			})
+
+			AfterEach(func() {
+				poms["org.fake:root:1"].DependencyManagement = nil
+				overrides = nil
+			})
+
+			It("should force them wherever the artifacts are declared, over dependencyManagement", func() {
+				Expect(err).ToNot(HaveOccurred())
+				root, err := walker.ResolveTree(context.Background(), &Artifact{GroupID: "org.fake", ArtifactID: "root", Version: "1"})
+				Expect(err).ToNot(HaveOccurred())
+
+				c := root.Children[0].Children[0]
+				Expect(c.Artifact.GetMavenCoords()).To(Equal("org.fake:c:2"))
+				Expect(c.Selection).To(Equal(SelectedOverride))
+				Expect(c.Artifact.Forced).To(BeTrue())
+				Expect(c.Artifact.ForcedFrom).To(Equal("1"))
+				Expect(root.Children[1].Children[0].Omitted).To(Equal(OmittedDuplicate))
+
+				d := root.Children[3]
+				Expect(d.Selection).To(Equal(SelectedOverride))
+				Expect(d.Artifact.Forced).To(BeTrue())
+				Expect(d.Artifact.ForcedFrom).To(Equal("1"))
+				Expect(root.Children[0].Artifact.Forced).To(BeFalse())
+			})
+		})
+
+		Context("where dependencies are excluded", func() {
+			BeforeEach(func() {
+				poms["org.fake:root:1"].Dependencies[0].Exclusions = []Artifact{{GroupID: "org.fake", ArtifactID: "e"}}
+				poms["org.fake:root:1"].Dependencies[2].Exclusions = []Artifact{{GroupID: "*", ArtifactID: "*"}}
+			})
+
+			AfterEach(func() {
+				poms["org.fake:root:1"].Dependencies[0].Exclusions = nil
+				poms["org.fake:root:1"].Dependencies[2].Exclusions = nil
+			})
+
+			It("should leave them out of the whole subtree", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(coordsOf(returnedPom.Dependencies[0].Dependencies)).To(Equal([]string{"org.fake:c:1"}))
+				Expect(returnedPom.Dependencies[0].Dependencies[0].Dependencies).To(BeEmpty())
+				Expect(coordsOf(returnedPom.Dependencies)).To(Equal([]string{
+					"org.fake:a:1", "org.fake:b:1", "org.fake:f:1", "org.fake:d:1",
+				}))
+				Expect(remoteRepository.FetchRemoteModelCallCount()).To(Equal(6))
+			})
+
+			It("should mark them as excluded in the tree", func() {
+				root, err := walker.ResolveTree(context.Background(), &Artifact{GroupID: "org.fake", ArtifactID: "root", Version: "1"})
+				Expect(err).ToNot(HaveOccurred())
+
+				e := root.Children[0].Children[0].Children[0]
+				Expect(e.Artifact.GetMavenCoords()).To(Equal("org.fake:e:1"))
+				Expect(e.Omitted).To(Equal(OmittedExcluded))
+				Expect(e.Children).To(BeEmpty())
+				Expect(root.Children[2].Children[0].Omitted).To(Equal(OmittedExcluded))
+			})
+		})
+
+		Context("where a dependency can not be fetched", func() {
+			BeforeEach(func() {
+				delete(poms, "org.fake:e:1")
+			})
+
+			It("should return a meaningful error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(
+					"Failed to fetch POM [org.fake:e:1] from configured search repositories: not found"))
+			})
+
+			Context("while offline", func() {
+				BeforeEach(func() {
+					offline = true
+					delete(poms, "org.fake:b:1")
+					remoteRepository.CheckRemoteJARStub = func(_ context.Context, artifact *Artifact, _ *Repository) (string, error) {
+						if artifact.ArtifactID == "c" {
+							return "", &OfflineError{URL: "http://localhost:8080/c.jar.sha1"}
+						}
+						return "some-sha", nil
+					}
+				})
+
+				AfterEach(func() {
+					offline = false
+				})
+
+				It("should report every missing POM and JAR at once", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(err).To(Equal(&MissingArtifactsError{
+						POMs: []string{"org.fake:b:1", "org.fake:e:1"},
+						JARs: []string{"org.fake:c:1"},
+					}))
+					Expect(err.Error()).To(Equal(`3 file(s) not available in the cache or local repositories :
+  POM [org.fake:b:1]
+  POM [org.fake:e:1]
+  JAR [org.fake:c:1]`))
+				})
+			})
+		})
+	})
+
+	Context("Given a multiple repository search", func() {
+		var (
+			available map[string][]*Repository
+		)
+
+		BeforeEach(func() {
+			repositories = []*Repository{
+				newRepository("file:///home/fake/.m2/repository"),
+				newRepository("http://localhost:8080"),
+				newRepository("http://localhost:8081"),
+			}
+			available = map[string][]*Repository{}
+			remoteRepository.FetchRemoteModelStub = func(_ context.Context, artifact *Artifact, repository *Repository) (*Artifact, error) {
+				for _, r := range available[artifact.GetMavenCoords()] {
+					if r == repository {
+						fetched := *artifact
+						if artifact.GetMavenCoords() == pom.GetMavenCoords() {
+							fetched.Dependencies = pom.Dependencies
+						}
+						return &fetched, nil
+					}
+				}
+				return nil, errors.Errorf("not found in %s", repository)
+			}
+			remoteRepository.CheckRemoteJARStub = func(_ context.Context, artifact *Artifact, repository *Repository) (string, error) {
+				if repository.IsLocal() {
+					return "", errors.New("no JAR")
+				}
+				return "remote-sha", nil
+			}
+		})
+
+		Context("where all dependencies are available in any of the provided repositories", func() {
+			BeforeEach(func() {
+				available["junit:junit:4.9"] = repositories
+				available["org.hamcrest:hamcrest-core:1.1"] = repositories
+			})
+
+			It("should use the first repository, publishing the first remote one", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(remoteRepository.FetchRemoteModelCallCount()).To(Equal(2))
+				_, _, repository := remoteRepository.FetchRemoteModelArgsForCall(0)
+				Expect(repository.String()).To(Equal("file:///home/fake/.m2/repository"))
+
+				Expect(returnedPom.Repository).To(Equal("http://localhost:8080"))
+				Expect(returnedPom.SHA).To(Equal("remote-sha"))
+				Expect(returnedPom.Dependencies).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"ArtifactID": Equal("hamcrest-core"),
+					"Repository": Equal("http://localhost:8080"),
+				}))))
+			})
+		})
+
+		Context("where all dependencies are available in only one of the provided repositories", func() {
+			BeforeEach(func() {
+				available["junit:junit:4.9"] = repositories[2:]
+				available["org.hamcrest:hamcrest-core:1.1"] = repositories[2:]
+			})
+
+			It("should fall back to the next repository", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(remoteRepository.FetchRemoteModelCallCount()).To(Equal(6))
+				Expect(returnedPom.Repository).To(Equal("http://localhost:8081"))
+				Expect(returnedPom.Dependencies).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+					"ArtifactID": Equal("hamcrest-core"),
+					"Repository": Equal("http://localhost:8081"),
+				}))))
+			})
+		})
+
+		Context("where a dependency is NOT available in any of the provided repositories", func() {
+			BeforeEach(func() {
+				available["junit:junit:4.9"] = repositories
+			})
+
+			It("should return the error of the last repository", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(
+					"Failed to fetch POM [org.hamcrest:hamcrest-core:1.1] from configured search repositories: not found in http://localhost:8081"))
+			})
+
+			Context("where a repository refuses to serve a dependency", func() {
+				BeforeEach(func() {
+					available["junit:junit:4.9"] = repositories
+					available["org.hamcrest:hamcrest-core:1.1"] = repositories
+					fetch := remoteRepository.FetchRemoteModelStub
+					remoteRepository.FetchRemoteModelStub = func(ctx context.Context, artifact *Artifact, repository *Repository) (*Artifact, error) {
+						if artifact.ArtifactID == "hamcrest-core" {
+							return nil, &FetchError{Kind: ErrUnauthorized, Resource: "POM", Coordinates: artifact.GetMavenCoords(),
+								Repository: repository.String(), URL: repository.URL(artifact.PathToPOM()), Status: 401}
+						}
+						return fetch(ctx, artifact, repository)
+					}
+				})
+
+				It("should not fall back to the next repository", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(IsUnauthorized(err)).To(BeTrue())
+					Expect(remoteRepository.FetchRemoteModelCallCount()).To(Equal(2))
+				})
+			})
+		})
 	})
 })
@@ -0,0 +1,142 @@
+package maven
+
+import (
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/redact"
+	"github.com/pkg/errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Kinds of FetchError, telling why a file could not be fetched from a repository.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrTransport    = errors.New("transport error")
+	ErrParse        = errors.New("parse error")
+	ErrIntegrity    = errors.New("integrity error")
+)
+
+// FetchError is returned when a file could not be fetched from a repository, or could not be parsed.
+type FetchError struct {
+	Kind error
+	// Resource is what was fetched, like a POM or JAR, for the artifact with the given coordinates.
+	Resource    string
+	Coordinates string
+	Repository  string
+	URL         string
+	// Status is the HTTP status the repository responded with, if it responded at all.
+	Status int
+	Err    error
+}
+
+func (e *FetchError) Error() string {
+	url := redact.String(e.URL)
+	switch e.Kind {
+	case ErrNotFound:
+		if e.Status != 0 {
+			return fmt.Sprintf("failed to find %s [%s] at [%s] : status %d", e.Resource, e.Coordinates, url, e.Status)
+		}
+		return fmt.Sprintf("failed to find %s [%s] at [%s]", e.Resource, e.Coordinates, url)
+	case ErrUnauthorized:
+		return fmt.Sprintf("not authorized to fetch %s [%s] from [%s] : status %d", e.Resource, e.Coordinates, url, e.Status)
+	case ErrIntegrity:
+		return fmt.Sprintf("failed to verify %s [%s] from [%s] : %s", e.Resource, e.Coordinates, url, e.Err)
+	case ErrParse:
+		return fmt.Sprintf("error parsing %s [%s] from [%s] : %s", e.Resource, e.Coordinates, url, errors.Cause(e.Err))
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("failed to fetch %s [%s] from [%s] : %s", e.Resource, e.Coordinates, url, e.Err)
+	}
+	return fmt.Sprintf("failed to fetch %s [%s] from [%s] : status %d", e.Resource, e.Coordinates, url, e.Status)
+}
+
+func IsNotFound(err error) bool {
+	return isKind(err, ErrNotFound)
+}
+
+func IsUnauthorized(err error) bool {
+	return isKind(err, ErrUnauthorized)
+}
+
+func IsTransport(err error) bool {
+	return isKind(err, ErrTransport)
+}
+
+func IsParse(err error) bool {
+	return isKind(err, ErrParse)
+}
+
+func IsIntegrity(err error) bool {
+	return isKind(err, ErrIntegrity)
+}
+
+func isKind(err error, kind error) bool {
+	e, isFetchError := errors.Cause(err).(*FetchError)
+	return isFetchError && e.Kind == kind
+}
+
+// statusError tells what an HTTP status other than 200 means for the file requested.
+func statusError(url string, status int) *FetchError {
+	e := &FetchError{Kind: ErrTransport, URL: url, Status: status}
+	switch status {
+	case http.StatusNotFound, http.StatusGone:
+		e.Kind = ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		e.Kind = ErrUnauthorized
+	}
+	return e
+}
+
+// describe fills in what was being fetched, from where, in errors returned while fetching it.
+func describe(err error, resource string, artifact *Artifact, repository *Repository) error {
+	if e, isFetchError := err.(*FetchError); isFetchError {
+		e.Resource = resource
+		e.Coordinates = artifact.GetMavenCoords()
+		e.Repository = repository.String()
+		return e
+	}
+	return errors.Wrapf(err, "failed to fetch %s [%s] from [%s]", resource, artifact.GetMavenCoords(), repository)
+}
+
+// OfflineError is returned instead of downloading a file which is neither cached nor in a local repository.
+type OfflineError struct {
+	URL string
+}
+
+func (e *OfflineError) Error() string {
+	return fmt.Sprintf("[%s] is not available offline", redact.String(e.URL))
+}
+
+func IsOffline(err error) bool {
+	_, isOffline := errors.Cause(err).(*OfflineError)
+	return isOffline
+}
+
+// MissingArtifactsError lists every POM and JAR an offline resolution could not find.
+type MissingArtifactsError struct {
+	POMs []string
+	JARs []string
+}
+
+func (e *MissingArtifactsError) Error() string {
+	lines := make([]string, 0, len(e.POMs)+len(e.JARs))
+	for _, coords := range e.POMs {
+		lines = append(lines, fmt.Sprintf("  POM [%s]", coords))
+	}
+	for _, coords := range e.JARs {
+		lines = append(lines, fmt.Sprintf("  JAR [%s]", coords))
+	}
+	return fmt.Sprintf("%d file(s) not available in the cache or local repositories :\n%s",
+		len(lines), strings.Join(lines, "\n"))
+}
+
+func (e *MissingArtifactsError) isEmpty() bool {
+	return len(e.POMs) == 0 && len(e.JARs) == 0
+}
+
+func (e *MissingArtifactsError) sort() {
+	sort.Strings(e.POMs)
+	sort.Strings(e.JARs)
+}
@@ -0,0 +1,115 @@
+package maven
+
+import (
+	"sort"
+)
+
+// Omission tells why a declared dependency was left out of the resolved graph.
+type Omission string
+
+const (
+	// OmittedDuplicate is a dependency already resolved with the same version elsewhere in the graph.
+	OmittedDuplicate Omission = "duplicate"
+	// OmittedConflict is a dependency which lost to another version of the same artifact nearer to the root.
+	OmittedConflict Omission = "conflict"
+	// OmittedExcluded is a dependency excluded by a dependency on the path from the root.
+	OmittedExcluded Omission = "excluded"
+	// OmittedOptional is an optional dependency, which is never resolved transitively.
+	OmittedOptional Omission = "optional"
+)
+
+// Selection tells which rule selected the version of an artifact of the resolved graph.
+type Selection string
+
+const (
+	// SelectedNearest is a version declared by the dependency nearest to the root, the first declaration breaking ties.
+	SelectedNearest Selection = "nearest wins"
+	// SelectedManaged is a version managed by the dependencyManagement of the root.
+	SelectedManaged Selection = "dependencyManagement"
+	// SelectedOverride is a version forced by an override, wherever the artifact is declared.
+	SelectedOverride Selection = "override"
+)
+
+// Node is an artifact of the resolved graph along with every dependency it declared, the way
+// `mvn dependency:tree -Dverbose` shows them: resolved dependencies have nodes of their own, while omitted ones are
+// marked with the reason they were left out.
+type Node struct {
+	Artifact *Artifact
+	// Scope is the scope the artifact was resolved with, empty for the root.
+	Scope   string
+	Omitted Omission
+	// Requested is the version declared by the dependent, before dependencyManagement or overrides applied.
+	Requested string
+	// Selection is the rule which selected the version of a resolved node.
+	Selection Selection
+	// Winner is the node kept instead of an omitted duplicate or conflict loser.
+	Winner   *Node
+	Children []*Node
+	// exclusions apply to the whole subtree of the node, accumulated from the root.
+	exclusions []Artifact
+}
+
+func (n *Node) IsOmitted() bool {
+	return n.Omitted != ""
+}
+
+// Walk visits the nodes of the graph depth first, in declaration order. Children of a node are skipped if `visit`
+// returns false.
+func (n *Node) Walk(visit func(node *Node, depth int) bool) {
+	n.walk(visit, 0)
+}
+
+func (n *Node) walk(visit func(node *Node, depth int) bool, depth int) {
+	if !visit(n, depth) {
+		return
+	}
+	for _, child := range n.Children {
+		child.walk(visit, depth+1)
+	}
+}
+
+// excludes tells whether a dependency matches one of the exclusions of the node, `*` matching any group or artifact.
+func (n *Node) excludes(dep *Artifact) bool {
+	for _, exclusion := range n.exclusions {
+		if matchesExclusion(exclusion.GroupID, dep.GroupID) && matchesExclusion(exclusion.ArtifactID, dep.ArtifactID) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesExclusion(pattern string, value string) bool {
+	return pattern == "*" || pattern == value
+}
+
+// PathsTo finds every path from the node to an artifact, whatever its version, including the paths to the
+// declarations which were omitted.
+func (n *Node) PathsTo(groupID string, artifactID string) [][]*Node {
+	paths := make([][]*Node, 0)
+	var visit func(node *Node, path []*Node)
+	visit = func(node *Node, path []*Node) {
+		path = append(path[:len(path):len(path)], node)
+		if node.Artifact.GroupID == groupID && node.Artifact.ArtifactID == artifactID {
+			paths = append(paths, path)
+			return
+		}
+		for _, child := range node.Children {
+			visit(child, path)
+		}
+	}
+	visit(n, nil)
+	return paths
+}
+
+// ResolvedPathsTo finds every path from the node to a resolved node, ending either with the node itself or with a
+// dependency omitted as a duplicate or conflict loser in its favor, shortest first.
+func (n *Node) ResolvedPathsTo(target *Node) [][]*Node {
+	paths := make([][]*Node, 0)
+	for _, path := range n.PathsTo(target.Artifact.GroupID, target.Artifact.ArtifactID) {
+		if last := path[len(path)-1]; last == target || last.Winner == target {
+			paths = append(paths, path)
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool { return len(paths[i]) < len(paths[j]) })
+	return paths
+}
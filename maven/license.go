@@ -0,0 +1,145 @@
+package maven
+
+import (
+	"regexp"
+	"strings"
+)
+
+// License is a license declared in the `<licenses>` of a POM, or inherited from its parents.
+type License struct {
+	Name string `xml:"name"`
+	URL  string `xml:"url"`
+}
+
+var nonAlphanumericRegex = regexp.MustCompile(`[^a-z0-9+]`)
+var urlSuffixRegex = regexp.MustCompile(`(\.txt|\.html?|\.php)?/?$`)
+
+// spdxLicense lists the names and URLs POMs commonly use for a license, both reduced to their normalized forms.
+type spdxLicense struct {
+	id    string
+	names []string
+	urls  []string
+}
+
+// spdxLicenses only holds the licenses found across Maven Central often enough to be worth recognizing, names too
+// ambiguous to tell versions apart, like a bare "BSD" or "LGPL", being left out.
+var spdxLicenses = []spdxLicense{
+	{id: "Apache-2.0",
+		names: []string{"apache2", "apache20", "apachev2", "asl2", "asl20", "apachelicense2", "apachelicense20",
+			"apachelicensev2", "apachelicensev20", "apachelicenseversion2", "apachelicenseversion20",
+			"apachesoftwarelicense20", "apachesoftwarelicenseversion20", "apachepubliclicense20"},
+		urls: []string{"apache.org/licenses/license-2.0", "opensource.org/licenses/apache-2.0",
+			"opensource.org/licenses/apache2.0"}},
+	{id: "Apache-1.1",
+		names: []string{"apache11", "apachelicense11", "apachelicenseversion11", "apachesoftwarelicenseversion11"},
+		urls:  []string{"apache.org/licenses/license-1.1"}},
+	{id: "MIT",
+		names: []string{"mit", "mitlicense", "expat"},
+		urls:  []string{"opensource.org/licenses/mit", "opensource.org/licenses/mit-license"}},
+	{id: "BSD-2-Clause",
+		names: []string{"bsd2clause", "bsd2clauselicense", "simplifiedbsd", "simplifiedbsdlicense", "freebsd"},
+		urls:  []string{"opensource.org/licenses/bsd-2-clause"}},
+	{id: "BSD-3-Clause",
+		names: []string{"bsd3clause", "bsd3clauselicense", "newbsd", "newbsdlicense", "revisedbsd",
+			"modifiedbsd", "eclipsedistributionlicense10", "eclipsedistributionlicensev10", "edl10"},
+		urls: []string{"opensource.org/licenses/bsd-3-clause", "eclipse.org/org/documents/edl-v10"}},
+	{id: "ISC",
+		names: []string{"isc", "isclicense"},
+		urls:  []string{"opensource.org/licenses/isc", "opensource.org/licenses/isc-license"}},
+	{id: "EPL-1.0",
+		names: []string{"epl10", "eplv10", "eclipsepubliclicense10", "eclipsepubliclicensev10",
+			"eclipsepubliclicenseversion10"},
+		urls: []string{"eclipse.org/legal/epl-v10", "opensource.org/licenses/epl-1.0"}},
+	{id: "EPL-2.0",
+		names: []string{"epl20", "eplv20", "eclipsepubliclicense20", "eclipsepubliclicensev20",
+			"eclipsepubliclicenseversion20"},
+		urls: []string{"eclipse.org/legal/epl-2.0", "eclipse.org/legal/epl-v20", "opensource.org/licenses/epl-2.0"}},
+	{id: "MPL-1.1",
+		names: []string{"mpl11", "mozillapubliclicense11", "mozillapubliclicenseversion11"},
+		urls:  []string{"mozilla.org/mpl/1.1", "mozilla.org/mpl/mpl-1.1"}},
+	{id: "MPL-2.0",
+		names: []string{"mpl20", "mozillapubliclicense20", "mozillapubliclicenseversion20"},
+		urls:  []string{"mozilla.org/mpl/2.0", "mozilla.org/en-us/mpl/2.0", "opensource.org/licenses/mpl-2.0"}},
+	{id: "CDDL-1.0",
+		names: []string{"cddl10", "cddlv10", "commondevelopmentanddistributionlicense10",
+			"commondevelopmentanddistributionlicensecddlversion10", "commondevelopmentanddistributionlicensecddlv10"},
+		urls: []string{"opensource.org/licenses/cddl-1.0", "opensource.org/licenses/cddl1"}},
+	{id: "CDDL-1.1",
+		names: []string{"cddl11", "cddlv11", "commondevelopmentanddistributionlicense11",
+			"commondevelopmentanddistributionlicensecddlversion11", "commondevelopmentanddistributionlicensecddlv11"},
+		urls: []string{"glassfish.java.net/public/cddl+gpl_1_1", "oss.oracle.com/licenses/cddl+gpl-1.1"}},
+	{id: "LGPL-2.1-only",
+		names: []string{"lgpl21", "lgplv21", "gnulessergeneralpubliclicense21", "gnulessergeneralpubliclicensev21",
+			"gnulessergeneralpubliclicenseversion21"},
+		urls: []string{"gnu.org/licenses/old-licenses/lgpl-2.1", "opensource.org/licenses/lgpl-2.1"}},
+	{id: "LGPL-3.0-only",
+		names: []string{"lgpl3", "lgpl30", "lgplv3", "gnulessergeneralpubliclicense3",
+			"gnulessergeneralpubliclicensev3", "gnulessergeneralpubliclicenseversion3",
+			"gnulessergeneralpubliclicenseversion30"},
+		urls: []string{"gnu.org/licenses/lgpl-3.0", "opensource.org/licenses/lgpl-3.0"}},
+	{id: "GPL-2.0-only",
+		names: []string{"gpl2", "gpl20", "gplv2", "gplv20", "gnugeneralpubliclicense2", "gnugeneralpubliclicensev2",
+			"gnugeneralpubliclicenseversion2", "gnugeneralpubliclicenseversion20"},
+		urls: []string{"gnu.org/licenses/old-licenses/gpl-2.0", "opensource.org/licenses/gpl-2.0"}},
+	{id: "GPL-2.0-with-classpath-exception",
+		names: []string{"gplv2+ce", "gpl2withclasspathexception", "gplv2withclasspathexception",
+			"gnugeneralpubliclicenseversion2withtheclasspathexception"},
+		urls: []string{"openjdk.java.net/legal/gplv2+ce", "openjdk.org/legal/gplv2+ce"}},
+	{id: "GPL-3.0-only",
+		names: []string{"gpl3", "gpl30", "gplv3", "gplv30", "gnugeneralpubliclicense3", "gnugeneralpubliclicensev3",
+			"gnugeneralpubliclicenseversion3", "gnugeneralpubliclicenseversion30"},
+		urls: []string{"gnu.org/licenses/gpl-3.0", "opensource.org/licenses/gpl-3.0"}},
+	{id: "CC0-1.0",
+		names: []string{"cc0", "cc010", "cc0v10", "creativecommonszero", "cc0publicdomaindedication"},
+		urls:  []string{"creativecommons.org/publicdomain/zero/1.0", "creativecommons.org/publicdomain/zero/1.0/legalcode"}},
+	{id: "Unlicense",
+		names: []string{"unlicense"},
+		urls:  []string{"unlicense.org"}},
+}
+
+// SPDXID normalizes the license to its SPDX identifier, recognizing either the identifier itself, one of the usual
+// names of the license or its URL. It is empty if the license isn't recognized.
+func (l License) SPDXID() string {
+	name := normalizeLicenseName(l.Name)
+	url := normalizeLicenseURL(l.URL)
+	for _, license := range spdxLicenses {
+		if strings.EqualFold(strings.TrimSpace(l.Name), license.id) {
+			return license.id
+		}
+		for _, n := range license.names {
+			if name == n {
+				return license.id
+			}
+		}
+		for _, u := range license.urls {
+			if url == u {
+				return license.id
+			}
+		}
+	}
+	return ""
+}
+
+// String is the SPDX identifier of the license if it's known, and whatever the POM declared otherwise.
+func (l License) String() string {
+	if id := l.SPDXID(); id != "" {
+		return id
+	}
+	if l.Name != "" {
+		return l.Name
+	}
+	return l.URL
+}
+
+func normalizeLicenseName(name string) string {
+	name = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(name)), "the ")
+	return nonAlphanumericRegex.ReplaceAllString(name, "")
+}
+
+func normalizeLicenseURL(url string) string {
+	url = strings.ToLower(strings.TrimSpace(url))
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		url = strings.TrimPrefix(url, prefix)
+	}
+	return urlSuffixRegex.ReplaceAllString(url, "")
+}
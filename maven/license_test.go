@@ -0,0 +1,42 @@
+package maven_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
+)
+
+var _ = Describe("License", func() {
+	It("should recognize SPDX identifiers", func() {
+		Expect(License{Name: "apache-2.0"}.SPDXID()).To(Equal("Apache-2.0"))
+		Expect(License{Name: "EPL-2.0"}.SPDXID()).To(Equal("EPL-2.0"))
+	})
+
+	It("should recognize the usual names of licenses", func() {
+		Expect(License{Name: "The Apache Software License, Version 2.0"}.SPDXID()).To(Equal("Apache-2.0"))
+		Expect(License{Name: "Eclipse Public License - v 1.0"}.SPDXID()).To(Equal("EPL-1.0"))
+		Expect(License{Name: "GNU Lesser General Public License, Version 2.1"}.SPDXID()).To(Equal("LGPL-2.1-only"))
+		Expect(License{Name: "The MIT License"}.SPDXID()).To(Equal("MIT"))
+		Expect(License{Name: "GPLv2+CE"}.SPDXID()).To(Equal("GPL-2.0-with-classpath-exception"))
+	})
+
+	It("should recognize the URLs of licenses", func() {
+		Expect(License{Name: "Apache", URL: "http://www.apache.org/licenses/LICENSE-2.0.txt"}.SPDXID()).To(Equal("Apache-2.0"))
+		Expect(License{URL: "https://opensource.org/licenses/BSD-3-Clause"}.SPDXID()).To(Equal("BSD-3-Clause"))
+		Expect(License{URL: "http://www.eclipse.org/legal/epl-v10.html"}.SPDXID()).To(Equal("EPL-1.0"))
+		Expect(License{URL: "https://www.mozilla.org/MPL/2.0/"}.SPDXID()).To(Equal("MPL-2.0"))
+	})
+
+	It("should not guess ambiguous or unknown licenses", func() {
+		Expect(License{Name: "BSD"}.SPDXID()).To(BeEmpty())
+		Expect(License{Name: "GPL 2.0+"}.SPDXID()).To(BeEmpty())
+		Expect(License{Name: "Some Company License", URL: "https://example.com/license"}.SPDXID()).To(BeEmpty())
+	})
+
+	It("should describe licenses by their SPDX identifier when known", func() {
+		Expect(License{Name: "Apache License 2.0"}.String()).To(Equal("Apache-2.0"))
+		Expect(License{Name: "Some Company License"}.String()).To(Equal("Some Company License"))
+		Expect(License{URL: "https://example.com/license"}.String()).To(Equal("https://example.com/license"))
+	})
+})
@@ -0,0 +1,137 @@
+package maven
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const remoteRepositoriesMarker = `_remote.repositories`
+const localMetadataFile = `maven-metadata-local.xml`
+const sha1Extension = `.sha1`
+
+// IsLocalRepository tells whether a repository is a directory on disk, like `~/.m2/repository`, rather than a remote
+// one. Local repositories are given as `file://` URLs or as paths.
+func IsLocalRepository(repository string) bool {
+	return strings.HasPrefix(repository, "file://") ||
+		strings.HasPrefix(repository, "/") ||
+		strings.HasPrefix(repository, "~") ||
+		strings.HasPrefix(repository, ".")
+}
+
+// localPath turns the location of a file in a local repository into a path on disk.
+func localPath(location string) string {
+	if strings.HasPrefix(location, "file://") {
+		location = strings.TrimPrefix(location, "file://")
+		if unescaped, err := url.PathUnescape(location); err == nil {
+			location = unescaped
+		}
+	}
+	if strings.HasPrefix(location, "~") {
+		location = filepath.Join(os.Getenv("HOME"), strings.TrimPrefix(location, "~"))
+	}
+	return filepath.Clean(location)
+}
+
+// getLocal reads a file out of a local repository, returning no contents if the repository does not have it.
+func getLocal(location string) ([]byte, error) {
+	path := localPath(location)
+
+	switch {
+	case strings.HasSuffix(path, metadataFile):
+		return getLocalMetadata(path)
+	case strings.HasSuffix(path, sha1Extension):
+		return getLocalSHA1(path)
+	}
+	return readLocalFile(path)
+}
+
+func readLocalFile(path string) ([]byte, error) {
+	available, err := isAvailableLocally(path)
+	if err != nil || !available {
+		return nil, err
+	}
+	bs, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return bs, err
+}
+
+// getLocalMetadata reads the metadata of an artifact. Local repositories don't keep a plain `maven-metadata.xml`,
+// but one for what was installed locally and one per remote repository metadata was downloaded from.
+func getLocalMetadata(path string) ([]byte, error) {
+	dir := filepath.Dir(path)
+	candidates := []string{path, filepath.Join(dir, localMetadataFile)}
+
+	others, err := filepath.Glob(filepath.Join(dir, "maven-metadata-*.xml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(others)
+	candidates = append(candidates, others...)
+
+	for _, candidate := range candidates {
+		bs, err := ioutil.ReadFile(candidate)
+		if err == nil {
+			return bs, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// getLocalSHA1 reads a checksum file, computing the checksum of the file itself if the checksum file is missing.
+func getLocalSHA1(path string) ([]byte, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err == nil {
+		return bs, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	contents, err := readLocalFile(strings.TrimSuffix(path, sha1Extension))
+	if err != nil || contents == nil {
+		return nil, err
+	}
+	sum := sha1.Sum(contents)
+	return []byte(hex.EncodeToString(sum[:])), nil
+}
+
+// isAvailableLocally checks the `_remote.repositories` marker Maven keeps next to the files it downloads or installs.
+// Files missing from the marker are leftovers of failed downloads and must not be used. Directories without a
+// marker are taken as is, as they weren't populated by Maven.
+func isAvailableLocally(path string) (bool, error) {
+	marker, err := os.Open(filepath.Join(filepath.Dir(path), remoteRepositoriesMarker))
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer marker.Close()
+
+	// entries look like `junit-4.9.pom>central=`, with an empty repository ID for locally installed files
+	prefix := []byte(filepath.Base(path) + ">")
+	scanner := bufio.NewScanner(marker)
+	for scanner.Scan() {
+		if bytes.HasPrefix(bytes.TrimSpace(scanner.Bytes()), prefix) {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+	logger.Debugf("Ignoring file missing from [%s] : %s", remoteRepositoriesMarker, path)
+	return false, nil
+}
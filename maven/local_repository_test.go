@@ -0,0 +1,155 @@
+package maven_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("Local repository", func() {
+	var (
+		err            error
+		dir            string
+		repo           RemoteRepository
+		toLookup       *Artifact
+		remoteArtifact *Artifact
+	)
+
+	install := func(path string, contents []byte) {
+		path = filepath.Join(dir, path)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, contents, 0644)).To(Succeed())
+	}
+
+	installPOM := func(a Artifact) {
+		bs, err := xml.Marshal(a)
+		Expect(err).ToNot(HaveOccurred())
+		install(a.PathToPOM(), bs)
+	}
+
+	BeforeEach(func() {
+		dir, err = ioutil.TempDir("", "local-repository")
+		Expect(err).ToNot(HaveOccurred())
+
+		repo = NewRemoteRepository()
+		toLookup = &Artifact{GroupID: "org.fake", ArtifactID: "some-artifact", Version: "1.0"}
+		installPOM(Artifact{GroupID: "org.fake", ArtifactID: "some-artifact", Version: "1.0"})
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should be recognized from its location", func() {
+		Expect(IsLocalRepository("file:///home/fake/.m2/repository")).To(BeTrue())
+		Expect(IsLocalRepository("/home/fake/.m2/repository")).To(BeTrue())
+		Expect(IsLocalRepository("~/.m2/repository")).To(BeTrue())
+		Expect(IsLocalRepository("https://repo.maven.apache.org/maven2")).To(BeFalse())
+	})
+
+	Context("given a POM installed in the repository", func() {
+		JustBeforeEach(func() {
+			remoteArtifact, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository("file://"+dir))
+		})
+
+		It("should read it from disk", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(remoteArtifact).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"GroupID":    Equal("org.fake"),
+				"ArtifactID": Equal("some-artifact"),
+				"Version":    Equal("1.0"),
+			})))
+		})
+
+		Context("and listed in a `_remote.repositories` marker", func() {
+			BeforeEach(func() {
+				install("org/fake/some-artifact/1.0/_remote.repositories", []byte(`#NOTE: internal file
+some-artifact-1.0.pom>central=
+`))
+			})
+
+			It("should read it from disk", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(remoteArtifact.Version).To(Equal("1.0"))
+			})
+		})
+
+		Context("but missing from a `_remote.repositories` marker", func() {
+			BeforeEach(func() {
+				install("org/fake/some-artifact/1.0/_remote.repositories", []byte(`some-artifact-1.0.jar>central=
+`))
+			})
+
+			It("should not use it", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(
+					"failed to find POM [org.fake:some-artifact:1.0] at [file://" + dir + "/org/fake/some-artifact/1.0/some-artifact-1.0.pom]"))
+			})
+		})
+
+		Context("and looked up without a version", func() {
+			BeforeEach(func() {
+				toLookup = &Artifact{GroupID: "org.fake", ArtifactID: "some-artifact"}
+				bs, err := xml.Marshal(Metadata{GroupID: "org.fake", ArtifactID: "some-artifact", Release: "1.0"})
+				Expect(err).ToNot(HaveOccurred())
+				install("org/fake/some-artifact/maven-metadata-central.xml", bs)
+			})
+
+			It("should read the version from the metadata of a remote repository", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(remoteArtifact.Version).To(Equal("1.0"))
+			})
+		})
+	})
+
+	Context("given a JAR installed in the repository", func() {
+		var (
+			sha string
+		)
+
+		JustBeforeEach(func() {
+			sha, err = repo.CheckRemoteJAR(context.Background(), toLookup, newRepository(dir))
+		})
+
+		Context("with its checksum", func() {
+			BeforeEach(func() {
+				install("org/fake/some-artifact/1.0/some-artifact-1.0.jar.sha1", []byte("abc123"))
+			})
+
+			It("should read the checksum", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sha).To(Equal("abc123"))
+			})
+		})
+
+		Context("without its checksum", func() {
+			BeforeEach(func() {
+				install("org/fake/some-artifact/1.0/some-artifact-1.0.jar", []byte("not really a JAR"))
+			})
+
+			It("should compute the checksum", func() {
+				sum := sha1.Sum([]byte("not really a JAR"))
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sha).To(Equal(hex.EncodeToString(sum[:])))
+			})
+		})
+
+		Context("which is missing", func() {
+			It("should return a meaningful error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(
+					"failed to find JAR [org.fake:some-artifact:1.0] at [" + dir + "/org/fake/some-artifact/1.0/some-artifact-1.0.jar.sha1]"))
+			})
+		})
+	})
+})
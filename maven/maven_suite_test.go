@@ -10,6 +10,7 @@ import (
 	"github.com/onsi/gomega/format"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 )
 
 func TestMaven(t *testing.T) {
@@ -30,8 +31,9 @@ func initMockServer(mocks []maven.Artifact, metadata ...maven.Metadata) *httptes
 
 		// serve all input mock responses
 		for _, a := range mocks {
-			pomPath := a.PathToPOM()
-			sha1Path := a.PathToJarSHA1()
+			// artifacts without a group have paths starting with "/", which repositories don't double
+			pomPath := strings.TrimPrefix(a.PathToPOM(), "/")
+			sha1Path := strings.TrimPrefix(a.PathToJarSHA1(), "/")
 			if "/"+pomPath == r.URL.Path {
 				// serialize POM object and return
 				bs, err := xml.Marshal(a)
@@ -77,3 +79,9 @@ func initMockServer(mocks []maven.Artifact, metadata ...maven.Metadata) *httptes
 		w.WriteHeader(404)
 	}))
 }
+
+func newRepository(location string) *maven.Repository {
+	repository, err := maven.NewRepository(location)
+	Expect(err).ToNot(HaveOccurred())
+	return repository
+}
@@ -2,17 +2,19 @@
 package mavenfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
 )
 
 type FakeRemoteRepository struct {
-	FetchRemoteModelStub        func(artifact *maven.Artifact, remoteRepository string) (*maven.Artifact, error)
+	FetchRemoteModelStub        func(ctx context.Context, artifact *maven.Artifact, remoteRepository *maven.Repository) (*maven.Artifact, error)
 	fetchRemoteModelMutex       sync.RWMutex
 	fetchRemoteModelArgsForCall []struct {
+		ctx              context.Context
 		artifact         *maven.Artifact
-		remoteRepository string
+		remoteRepository *maven.Repository
 	}
 	fetchRemoteModelReturns struct {
 		result1 *maven.Artifact
@@ -22,11 +24,12 @@ type FakeRemoteRepository struct {
 		result1 *maven.Artifact
 		result2 error
 	}
-	CheckRemoteJARStub        func(artifact *maven.Artifact, remoteRepository string) (string, error)
+	CheckRemoteJARStub        func(ctx context.Context, artifact *maven.Artifact, remoteRepository *maven.Repository) (string, error)
 	checkRemoteJARMutex       sync.RWMutex
 	checkRemoteJARArgsForCall []struct {
+		ctx              context.Context
 		artifact         *maven.Artifact
-		remoteRepository string
+		remoteRepository *maven.Repository
 	}
 	checkRemoteJARReturns struct {
 		result1 string
@@ -36,21 +39,37 @@ type FakeRemoteRepository struct {
 		result1 string
 		result2 error
 	}
+	FetchMetadataStub        func(ctx context.Context, artifact *maven.Artifact, remoteRepository *maven.Repository) (*maven.Metadata, error)
+	fetchMetadataMutex       sync.RWMutex
+	fetchMetadataArgsForCall []struct {
+		ctx              context.Context
+		artifact         *maven.Artifact
+		remoteRepository *maven.Repository
+	}
+	fetchMetadataReturns struct {
+		result1 *maven.Metadata
+		result2 error
+	}
+	fetchMetadataReturnsOnCall map[int]struct {
+		result1 *maven.Metadata
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeRemoteRepository) FetchRemoteModel(artifact *maven.Artifact, remoteRepository string) (*maven.Artifact, error) {
+func (fake *FakeRemoteRepository) FetchRemoteModel(ctx context.Context, artifact *maven.Artifact, remoteRepository *maven.Repository) (*maven.Artifact, error) {
 	fake.fetchRemoteModelMutex.Lock()
 	ret, specificReturn := fake.fetchRemoteModelReturnsOnCall[len(fake.fetchRemoteModelArgsForCall)]
 	fake.fetchRemoteModelArgsForCall = append(fake.fetchRemoteModelArgsForCall, struct {
+		ctx              context.Context
 		artifact         *maven.Artifact
-		remoteRepository string
-	}{artifact, remoteRepository})
-	fake.recordInvocation("FetchRemoteModel", []interface{}{artifact, remoteRepository})
+		remoteRepository *maven.Repository
+	}{ctx, artifact, remoteRepository})
+	fake.recordInvocation("FetchRemoteModel", []interface{}{ctx, artifact, remoteRepository})
 	fake.fetchRemoteModelMutex.Unlock()
 	if fake.FetchRemoteModelStub != nil {
-		return fake.FetchRemoteModelStub(artifact, remoteRepository)
+		return fake.FetchRemoteModelStub(ctx, artifact, remoteRepository)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -64,10 +83,10 @@ func (fake *FakeRemoteRepository) FetchRemoteModelCallCount() int {
 	return len(fake.fetchRemoteModelArgsForCall)
 }
 
-func (fake *FakeRemoteRepository) FetchRemoteModelArgsForCall(i int) (*maven.Artifact, string) {
+func (fake *FakeRemoteRepository) FetchRemoteModelArgsForCall(i int) (context.Context, *maven.Artifact, *maven.Repository) {
 	fake.fetchRemoteModelMutex.RLock()
 	defer fake.fetchRemoteModelMutex.RUnlock()
-	return fake.fetchRemoteModelArgsForCall[i].artifact, fake.fetchRemoteModelArgsForCall[i].remoteRepository
+	return fake.fetchRemoteModelArgsForCall[i].ctx, fake.fetchRemoteModelArgsForCall[i].artifact, fake.fetchRemoteModelArgsForCall[i].remoteRepository
 }
 
 func (fake *FakeRemoteRepository) FetchRemoteModelReturns(result1 *maven.Artifact, result2 error) {
@@ -92,17 +111,18 @@ func (fake *FakeRemoteRepository) FetchRemoteModelReturnsOnCall(i int, result1 *
 	}{result1, result2}
 }
 
-func (fake *FakeRemoteRepository) CheckRemoteJAR(artifact *maven.Artifact, remoteRepository string) (string, error) {
+func (fake *FakeRemoteRepository) CheckRemoteJAR(ctx context.Context, artifact *maven.Artifact, remoteRepository *maven.Repository) (string, error) {
 	fake.checkRemoteJARMutex.Lock()
 	ret, specificReturn := fake.checkRemoteJARReturnsOnCall[len(fake.checkRemoteJARArgsForCall)]
 	fake.checkRemoteJARArgsForCall = append(fake.checkRemoteJARArgsForCall, struct {
+		ctx              context.Context
 		artifact         *maven.Artifact
-		remoteRepository string
-	}{artifact, remoteRepository})
-	fake.recordInvocation("CheckRemoteJAR", []interface{}{artifact, remoteRepository})
+		remoteRepository *maven.Repository
+	}{ctx, artifact, remoteRepository})
+	fake.recordInvocation("CheckRemoteJAR", []interface{}{ctx, artifact, remoteRepository})
 	fake.checkRemoteJARMutex.Unlock()
 	if fake.CheckRemoteJARStub != nil {
-		return fake.CheckRemoteJARStub(artifact, remoteRepository)
+		return fake.CheckRemoteJARStub(ctx, artifact, remoteRepository)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -116,10 +136,10 @@ func (fake *FakeRemoteRepository) CheckRemoteJARCallCount() int {
 	return len(fake.checkRemoteJARArgsForCall)
 }
 
-func (fake *FakeRemoteRepository) CheckRemoteJARArgsForCall(i int) (*maven.Artifact, string) {
+func (fake *FakeRemoteRepository) CheckRemoteJARArgsForCall(i int) (context.Context, *maven.Artifact, *maven.Repository) {
 	fake.checkRemoteJARMutex.RLock()
 	defer fake.checkRemoteJARMutex.RUnlock()
-	return fake.checkRemoteJARArgsForCall[i].artifact, fake.checkRemoteJARArgsForCall[i].remoteRepository
+	return fake.checkRemoteJARArgsForCall[i].ctx, fake.checkRemoteJARArgsForCall[i].artifact, fake.checkRemoteJARArgsForCall[i].remoteRepository
 }
 
 func (fake *FakeRemoteRepository) CheckRemoteJARReturns(result1 string, result2 error) {
@@ -144,6 +164,59 @@ func (fake *FakeRemoteRepository) CheckRemoteJARReturnsOnCall(i int, result1 str
 	}{result1, result2}
 }
 
+func (fake *FakeRemoteRepository) FetchMetadata(ctx context.Context, artifact *maven.Artifact, remoteRepository *maven.Repository) (*maven.Metadata, error) {
+	fake.fetchMetadataMutex.Lock()
+	ret, specificReturn := fake.fetchMetadataReturnsOnCall[len(fake.fetchMetadataArgsForCall)]
+	fake.fetchMetadataArgsForCall = append(fake.fetchMetadataArgsForCall, struct {
+		ctx              context.Context
+		artifact         *maven.Artifact
+		remoteRepository *maven.Repository
+	}{ctx, artifact, remoteRepository})
+	fake.recordInvocation("FetchMetadata", []interface{}{ctx, artifact, remoteRepository})
+	fake.fetchMetadataMutex.Unlock()
+	if fake.FetchMetadataStub != nil {
+		return fake.FetchMetadataStub(ctx, artifact, remoteRepository)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.fetchMetadataReturns.result1, fake.fetchMetadataReturns.result2
+}
+
+func (fake *FakeRemoteRepository) FetchMetadataCallCount() int {
+	fake.fetchMetadataMutex.RLock()
+	defer fake.fetchMetadataMutex.RUnlock()
+	return len(fake.fetchMetadataArgsForCall)
+}
+
+func (fake *FakeRemoteRepository) FetchMetadataArgsForCall(i int) (context.Context, *maven.Artifact, *maven.Repository) {
+	fake.fetchMetadataMutex.RLock()
+	defer fake.fetchMetadataMutex.RUnlock()
+	return fake.fetchMetadataArgsForCall[i].ctx, fake.fetchMetadataArgsForCall[i].artifact, fake.fetchMetadataArgsForCall[i].remoteRepository
+}
+
+func (fake *FakeRemoteRepository) FetchMetadataReturns(result1 *maven.Metadata, result2 error) {
+	fake.FetchMetadataStub = nil
+	fake.fetchMetadataReturns = struct {
+		result1 *maven.Metadata
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRemoteRepository) FetchMetadataReturnsOnCall(i int, result1 *maven.Metadata, result2 error) {
+	fake.FetchMetadataStub = nil
+	if fake.fetchMetadataReturnsOnCall == nil {
+		fake.fetchMetadataReturnsOnCall = make(map[int]struct {
+			result1 *maven.Metadata
+			result2 error
+		})
+	}
+	fake.fetchMetadataReturnsOnCall[i] = struct {
+		result1 *maven.Metadata
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRemoteRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -151,6 +224,8 @@ func (fake *FakeRemoteRepository) Invocations() map[string][][]interface{} {
 	defer fake.fetchRemoteModelMutex.RUnlock()
 	fake.checkRemoteJARMutex.RLock()
 	defer fake.checkRemoteJARMutex.RUnlock()
+	fake.fetchMetadataMutex.RLock()
+	defer fake.fetchMetadataMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -9,11 +9,12 @@ import (
 
 type Metadata struct {
 	xml.Name
-	GroupID    string `xml:"groupId"`
-	ArtifactID string `xml:"artifactId"`
-	Latest     string `xml:"versioning>latest"`
-	Release    string `xml:"versioning>release"`
-	Version    string `xml:"version"`
+	GroupID    string   `xml:"groupId"`
+	ArtifactID string   `xml:"artifactId"`
+	Latest     string   `xml:"versioning>latest"`
+	Release    string   `xml:"versioning>release"`
+	Versions   []string `xml:"versioning>versions>version"`
+	Version    string   `xml:"version"`
 }
 
 func UnmarshalMetadata(contents []byte) (*Metadata, error) {
@@ -29,6 +29,11 @@ var _ = Describe("Metadata", func() {
 	<versioning>
 		<latest>1.0.2-SNAPSHOT</latest>
 		<release>1.0.1</release>
+		<versions>
+			<version>1.0</version>
+			<version>1.0.1</version>
+			<version>1.0.2-SNAPSHOT</version>
+		</versions>
 	</versioning>
 </metadata>
 `
@@ -43,6 +48,7 @@ var _ = Describe("Metadata", func() {
 				Expect(metadata.Latest).To(Equal("1.0.2-SNAPSHOT"))
 				Expect(metadata.Release).To(Equal("1.0.1"))
 				Expect(metadata.Version).To(Equal("1.0"))
+				Expect(metadata.Versions).To(Equal([]string{"1.0", "1.0.1", "1.0.2-SNAPSHOT"}))
 			})
 		})
 
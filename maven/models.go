@@ -10,25 +10,37 @@ import (
 	"strings"
 )
 
+const metadataFile = `maven-metadata.xml`
+const snapshotQualifier = `-SNAPSHOT`
+const jarExtension = `.jar`
+
 var propertyRegex = regexp.MustCompile(`^\${(.*)}$`)
 var artifactRegex = regexp.MustCompile(`^(.+):(.+):(.+)$`)
 var pomPropertiesRegex = regexp.MustCompile(`^(project\.|pom\.)?(groupId|artifactId|version)$`)
 
 // TODO: do any assertions about Maven model version?
 type Artifact struct {
-	XMLName      xml.Name
-	GroupID      string      `xml:"groupId"`
-	ArtifactID   string      `xml:"artifactId"`
-	Version      string      `xml:"version"`
-	Scope        string      `xml:"scope,omitempty"`
-	Optional     bool        `xml:"optional"`
-	Repository   string      `xml:"-"`
-	SHA          string      `xml:"-"`
+	XMLName    xml.Name
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Packaging  string `xml:"packaging,omitempty"`
+	Scope      string `xml:"scope,omitempty"`
+	Optional   bool   `xml:"optional"`
+	Repository string `xml:"-"`
+	SHA        string `xml:"-"`
+	// Forced is set when an override forced the version, ForcedFrom being the version declared nearest to the root.
+	Forced       bool        `xml:"-"`
+	ForcedFrom   string      `xml:"-"`
 	Parent       *Artifact   `xml:"parent,omitempty"`
 	ModelVersion string      `xml:"modelVersion,omitempty"`
 	Properties   Properties  `xml:"properties,omitempty"`
 	Dependencies []*Artifact `xml:"dependencies>dependency,omitempty"`
 	Exclusions   []Artifact  `xml:"exclusions>exclusion,omitempty"`
+	// DependencyManagement holds the versions managed by the POM and its parents, nearest first.
+	DependencyManagement []*Artifact `xml:"dependencyManagement>dependencies>dependency,omitempty"`
+	// Licenses are those declared by the POM, or by its nearest parent declaring any.
+	Licenses []License `xml:"licenses>license,omitempty"`
 }
 
 type Properties struct {
@@ -57,6 +69,26 @@ func (a *Artifact) GetMavenCoords() string {
 	return fmt.Sprintf("%s:%s:%s", a.GroupID, a.ArtifactID, a.Version)
 }
 
+func (a *Artifact) GetVersionlessCoords() string {
+	return fmt.Sprintf("%s:%s", a.GroupID, a.ArtifactID)
+}
+
+// ManagedVersion finds the dependencyManagement entry of the POM for an artifact, nil if its version isn't managed.
+func (a *Artifact) ManagedVersion(artifact *Artifact) *Artifact {
+	for _, managed := range a.DependencyManagement {
+		if managed.GroupID == artifact.GroupID && managed.ArtifactID == artifact.ArtifactID && managed.Version != "" &&
+			managed.Scope != "import" {
+			return managed
+		}
+	}
+	return nil
+}
+
+// IsJAR tells whether the artifact is packaged as a JAR, which is the default packaging.
+func (a *Artifact) IsJAR() bool {
+	return a.Packaging == "" || a.Packaging == "jar" || a.Packaging == "bundle"
+}
+
 func (a *Artifact) IsValid() bool {
 	// TODO: use regex to check IDs and version syntax correctly
 	return a.GroupID != "" && a.ArtifactID != "" && a.Version != ""
@@ -68,6 +100,11 @@ func (a *Artifact) PathToPOM() string {
 		strings.Replace(a.GroupID, ".", "/", -1), a.ArtifactID, a.Version, a.ArtifactID, a.Version)
 }
 
+func (a *Artifact) PathToJar() string {
+	return fmt.Sprintf("%s/%s/%s/%s-%s%s",
+		strings.Replace(a.GroupID, ".", "/", -1), a.ArtifactID, a.Version, a.ArtifactID, a.Version, jarExtension)
+}
+
 func (a *Artifact) PathToJarSHA1() string {
 	return fmt.Sprintf("%s/%s/%s/%s-%s.jar.sha1",
 		strings.Replace(a.GroupID, ".", "/", -1), a.ArtifactID, a.Version, a.ArtifactID, a.Version)
@@ -136,7 +173,7 @@ func (a *Artifact) getPomPropertyValue(property string) string {
 
 func (a *Artifact) MetadataPath() string {
 	// TODO: return with leading forward slash?
-	return fmt.Sprintf("%s/%s/maven-metadata.xml", strings.Replace(a.GroupID, ".", "/", -1), a.ArtifactID)
+	return fmt.Sprintf("%s/%s/%s", strings.Replace(a.GroupID, ".", "/", -1), a.ArtifactID, metadataFile)
 }
 
 func (a *Artifact) InterpolatePropertiesFromProperties() {
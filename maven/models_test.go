@@ -71,6 +71,13 @@ var _ = Describe("Models", func() {
 						<artifactId>commons-text</artifactId>
 						<version>1.4</version>
 						<name>Apache Commons Text</name>
+						<licenses>
+							<license>
+								<name>Apache License, Version 2.0</name>
+								<url>https://www.apache.org/licenses/LICENSE-2.0.txt</url>
+								<distribution>repo</distribution>
+							</license>
+						</licenses>
 						<dependencies>
 							<dependency>
 								<groupId>org.apache.commons</groupId>
@@ -109,6 +116,10 @@ var _ = Describe("Models", func() {
 					Expect(pom.GroupID).To(Equal("org.apache.commons"))
 					Expect(pom.ArtifactID).To(Equal("commons-text"))
 					Expect(pom.Version).To(Equal("1.4"))
+					Expect(pom.Licenses).To(ConsistOf(License{
+						Name: "Apache License, Version 2.0",
+						URL:  "https://www.apache.org/licenses/LICENSE-2.0.txt",
+					}))
 
 					Expect(pom.Dependencies).ToNot(BeNil())
 					Expect(pom.Dependencies).To(HaveLen(2))
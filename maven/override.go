@@ -0,0 +1,20 @@
+package maven
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// ParseOverrides reads versions to force, given as `groupId:artifactId:version`, later entries winning over earlier
+// ones. The overrides are keyed by `groupId:artifactId`.
+func ParseOverrides(entries []string) (map[string]string, error) {
+	overrides := map[string]string{}
+	for _, entry := range entries {
+		coords := strings.Split(strings.TrimSpace(entry), ":")
+		if len(coords) != 3 || coords[0] == "" || coords[1] == "" || coords[2] == "" {
+			return nil, errors.Errorf("invalid override [%s], expected groupId:artifactId:version", entry)
+		}
+		overrides[coords[0]+":"+coords[1]] = coords[2]
+	}
+	return overrides, nil
+}
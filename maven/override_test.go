@@ -0,0 +1,28 @@
+package maven_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
+)
+
+var _ = Describe("ParseOverrides", func() {
+	It("should key versions by artifact, later entries winning", func() {
+		overrides, err := ParseOverrides([]string{
+			"com.fasterxml.jackson.core:jackson-databind:2.9.10.7",
+			" com.fasterxml.jackson.core:jackson-databind:2.9.10.8 ",
+			"log4j:log4j:1.2.17",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(overrides).To(Equal(map[string]string{
+			"com.fasterxml.jackson.core:jackson-databind": "2.9.10.8",
+			"log4j:log4j": "1.2.17",
+		}))
+	})
+
+	It("should return a meaningful error for entries without a version", func() {
+		_, err := ParseOverrides([]string{"log4j:log4j"})
+		Expect(err).To(MatchError("invalid override [log4j:log4j], expected groupId:artifactId:version"))
+	})
+})
@@ -1,37 +1,167 @@
 package maven
 
 import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/xml"
-	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/cache"
+	"github.com/jspawar/generate-bazel-workspace-gradle/pgp"
+	"github.com/jspawar/generate-bazel-workspace-gradle/redact"
 	"github.com/pkg/errors"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 )
 
 //go:generate counterfeiter . RemoteRepository
+
+// RemoteRepository fetches models from a Maven repository, either over HTTP or from a local repository on disk, like
+// `~/.m2/repository`, given as a `file://` URL or a path.
 type RemoteRepository interface {
-	FetchRemoteModel(artifact *Artifact, remoteRepository string) (*Artifact, error)
-	CheckRemoteJAR(artifact *Artifact, remoteRepository string) (string, error)
+	FetchRemoteModel(ctx context.Context, artifact *Artifact, remoteRepository *Repository) (*Artifact, error)
+	CheckRemoteJAR(ctx context.Context, artifact *Artifact, remoteRepository *Repository) (string, error)
+	FetchMetadata(ctx context.Context, artifact *Artifact, remoteRepository *Repository) (*Metadata, error)
+}
+
+type remoteRepository struct {
+	// POMs are kept in memory as parent POMs tend to be shared by many artifacts
+	poms     map[string][]byte
+	pomsLock sync.Mutex
+	cache    *cache.Cache
+	offline  bool
+	// credentials are keyed by the URL of the repository they are sent to
+	credentials map[string]*Credentials
+	client      *http.Client
+	// checksumPolicy and signaturePolicy decide what happens to files failing verification
+	checksumPolicy  VerificationPolicy
+	signaturePolicy VerificationPolicy
+	keyring         *pgp.Keyring
+}
+
+// Credentials authenticate requests to a repository, with basic auth, a bearer token or custom headers like
+// `X-JFrog-Art-Api` or GitLab's `Private-Token`.
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+	Headers  map[string]string
+}
+
+func (c *Credentials) apply(req *http.Request) {
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+}
+
+// checkRedirect keeps credentials from leaking to the hosts a repository redirects downloads to, like S3 or a CDN, as
+// Go only drops the `Authorization` header of redirects to other domains, and none of the custom headers.
+func (r *remoteRepository) checkRedirect(next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != via[0].URL.Host {
+			req.Header.Del("Authorization")
+			for _, c := range r.credentials {
+				for name := range c.Headers {
+					req.Header.Del(name)
+				}
+			}
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		// the default policy of Go's client
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+}
+
+func (c *Credentials) secrets() []string {
+	secrets := []string{c.Password, c.Token}
+	for _, value := range c.Headers {
+		secrets = append(secrets, value)
+	}
+	return secrets
+}
+
+type RemoteRepositoryOption func(r *remoteRepository)
+
+// WithCache makes downloaded files be stored in, and read back from, a cache on disk.
+func WithCache(c *cache.Cache) RemoteRepositoryOption {
+	return func(r *remoteRepository) {
+		r.cache = c
+	}
+}
+
+// WithOffline forbids any network access, files are only read from the cache and local repositories.
+func WithOffline() RemoteRepositoryOption {
+	return func(r *remoteRepository) {
+		r.offline = true
+	}
+}
+
+// WithHTTPClient downloads files with a client configured for timeouts, retries and proxies, instead of the default
+// client.
+func WithHTTPClient(c *http.Client) RemoteRepositoryOption {
+	return func(r *remoteRepository) {
+		r.client = c
+	}
 }
 
-type remoteRepository struct{}
+// WithCredentials authenticates every request to a repository.
+func WithCredentials(repository string, c *Credentials) RemoteRepositoryOption {
+	for _, secret := range c.secrets() {
+		redact.Register(secret)
+	}
+	// match the URLs of files, which are built from normalized repositories
+	if normalized, err := NewRepository(repository); err == nil {
+		repository = normalized.URL("")
+	}
+	return func(r *remoteRepository) {
+		r.credentials[strings.TrimSuffix(repository, "/")] = c
+	}
+}
 
-func NewRemoteRepository() RemoteRepository {
-	return &remoteRepository{}
+func NewRemoteRepository(options ...RemoteRepositoryOption) RemoteRepository {
+	r := &remoteRepository{
+		poms:            map[string][]byte{},
+		credentials:     map[string]*Credentials{},
+		client:          http.DefaultClient,
+		checksumPolicy:  IgnorePolicy,
+		signaturePolicy: IgnorePolicy,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	client := *r.client
+	client.CheckRedirect = r.checkRedirect(client.CheckRedirect)
+	r.client = &client
+	return r
 }
 
-// TODO: fix assumption of no trailing "/" on repo URL
-func (r *remoteRepository) FetchRemoteModel(artifact *Artifact, remoteRepository string) (*Artifact, error) {
+func (r *remoteRepository) FetchRemoteModel(ctx context.Context, artifact *Artifact, remoteRepository *Repository) (*Artifact, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	// get latest version if needed
 	if artifact.Version == "" {
-		latestVersion, err := r.fetchLatestVersion(artifact, remoteRepository)
+		latestVersion, err := r.fetchLatestVersion(ctx, artifact, remoteRepository)
 		if err != nil {
 			return nil, err
 		}
 		artifact.Version = latestVersion
 	}
 
-	remoteArtifact, err := r.doFetch(artifact, remoteRepository)
+	remoteArtifact, err := r.doFetch(ctx, artifact, remoteRepository)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +171,7 @@ func (r *remoteRepository) FetchRemoteModel(artifact *Artifact, remoteRepository
 
 	// parent resolution
 	if remoteArtifact.Parent != nil {
-		remoteParent, err := r.FetchRemoteModel(remoteArtifact.Parent, remoteRepository)
+		remoteParent, err := r.FetchRemoteModel(ctx, remoteArtifact.Parent, remoteRepository)
 		if err != nil {
 			return nil, err
 		}
@@ -61,49 +191,59 @@ func (r *remoteRepository) FetchRemoteModel(artifact *Artifact, remoteRepository
 	return remoteArtifact, nil
 }
 
-func (r *remoteRepository) CheckRemoteJAR(artifact *Artifact, remoteRepository string) (string, error) {
-	res, err := http.Get(fmt.Sprintf("%s/%s", remoteRepository, artifact.PathToJarSHA1()))
+func (r *remoteRepository) CheckRemoteJAR(ctx context.Context, artifact *Artifact, remoteRepository *Repository) (string, error) {
+	if r.verifiesJARs() && !remoteRepository.IsLocal() {
+		return r.verifyJAR(ctx, artifact, remoteRepository)
+	}
+	bs, err := r.fetch(ctx, "JAR", artifact, remoteRepository, artifact.PathToJarSHA1())
 	if err != nil {
-		return "", errors.Wrapf(err,
-			"failed to find JAR [%s] in configured search repositories",
-			artifact.GetMavenCoords())
+		return "", err
 	}
-	if res.StatusCode != 200 {
-		return "", errors.New(fmt.Sprintf(
-			"failed to find JAR [%s] in configured search repositories",
-			artifact.GetMavenCoords()))
+	// checksum files may be suffixed with the name of the file they were generated for
+	fields := strings.Fields(string(bs))
+	if len(fields) < 1 {
+		return "", errors.Errorf("empty checksum for JAR [%s]", artifact.GetMavenCoords())
 	}
-	defer res.Body.Close()
+	return fields[0], nil
+}
 
-	bs, err := ioutil.ReadAll(res.Body)
+// verifyJAR downloads a JAR to verify it, returning its checksum.
+func (r *remoteRepository) verifyJAR(ctx context.Context, artifact *Artifact, remoteRepository *Repository) (string, error) {
+	bs, err := r.fetch(ctx, "JAR", artifact, remoteRepository, artifact.PathToJar())
 	if err != nil {
 		return "", err
 	}
-	return string(bs), nil
+	if err := r.verify(ctx, "JAR", artifact, remoteRepository, artifact.PathToJar(), bs); err != nil {
+		return "", err
+	}
+	sum := sha1.Sum(bs)
+	return hex.EncodeToString(sum[:]), nil
 }
 
-func (r *remoteRepository) doFetch(artifact *Artifact, remoteRepository string) (*Artifact, error) {
-	res, err := http.Get(fmt.Sprintf("%s/%s", remoteRepository, artifact.PathToPOM()))
-	if err != nil {
-		return nil, errors.Wrapf(err,
-			"failed to find POM [%s] in configured search repositories",
-			artifact.GetMavenCoords())
-	}
-	if res.StatusCode != 200 {
-		return nil, errors.New(fmt.Sprintf(
-			"failed to find POM [%s] in configured search repositories",
-			artifact.GetMavenCoords()))
+func (r *remoteRepository) doFetch(ctx context.Context, artifact *Artifact, remoteRepository *Repository) (*Artifact, error) {
+	url := remoteRepository.URL(artifact.PathToPOM())
+	r.pomsLock.Lock()
+	cached, isFetched := r.poms[url]
+	r.pomsLock.Unlock()
+	if isFetched {
+		return UnmarshalPOM(cached)
 	}
-	defer res.Body.Close()
 
-	bs, err := ioutil.ReadAll(res.Body)
+	bs, err := r.fetch(ctx, "POM", artifact, remoteRepository, artifact.PathToPOM())
 	if err != nil {
 		return nil, err
 	}
+	if err := r.verify(ctx, "POM", artifact, remoteRepository, artifact.PathToPOM(), bs); err != nil {
+		return nil, err
+	}
 	remoteArtifact, err := UnmarshalPOM(bs)
 	if err != nil {
-		return nil, err
+		return nil, &FetchError{Kind: ErrParse, Resource: "POM", Coordinates: artifact.GetMavenCoords(),
+			Repository: remoteRepository.String(), URL: url, Err: err}
 	}
+	r.pomsLock.Lock()
+	r.poms[url] = bs
+	r.pomsLock.Unlock()
 	return remoteArtifact, nil
 }
 
@@ -124,6 +264,10 @@ func (r *remoteRepository) doInterpolation(artifact *Artifact) error {
 			artifact.Properties.Values = append(artifact.Properties.Values, artifact.Parent.Properties.Values...)
 		}
 	}
+	// licenses are only inherited as a whole, a POM declaring any replacing those of its parents
+	if len(artifact.Licenses) == 0 && artifact.Parent != nil {
+		artifact.Licenses = artifact.Parent.Licenses
+	}
 	// ensure properties themselves have been interpolated
 	artifact.InterpolatePropertiesFromProperties()
 	// interpolate
@@ -140,28 +284,63 @@ func (r *remoteRepository) doInterpolation(artifact *Artifact) error {
 		}
 		dep.Version = interpolatedVersion
 	}
+	r.doManagement(artifact)
 	return nil
 }
 
-func (r *remoteRepository) fetchLatestVersion(artifact *Artifact, remoteRepository string) (string, error) {
-	res, err := http.Get(fmt.Sprintf("%s/%s", remoteRepository, artifact.MetadataPath()))
-	if err != nil {
-		return "", errors.Wrapf(err,
-			"failed to find metadata for POM [%s] in configured search repositories",
-			artifact.GetMavenCoords())
+// doManagement inherits the dependencyManagement of the parent, and fills in the versions and scopes of the
+// dependencies of the POM which are managed.
+func (r *remoteRepository) doManagement(artifact *Artifact) {
+	managed := make([]*Artifact, 0, len(artifact.DependencyManagement))
+	for _, dep := range artifact.DependencyManagement {
+		groupID, err := artifact.InterpolateFromProperties(dep.GroupID)
+		if err == nil {
+			dep.GroupID = groupID
+			dep.Version, err = artifact.InterpolateFromProperties(dep.Version)
+		}
+		if err != nil {
+			// unused entries of big BOMs shouldn't break resolution
+			logger.Debugf("Ignoring managed dependency [%s] of POM [%s] : %s",
+				dep.GetVersionlessCoords(), artifact.GetMavenCoords(), err)
+			continue
+		}
+		managed = append(managed, dep)
 	}
-	if res.StatusCode != 200 {
-		return "", errors.New(fmt.Sprintf(
-			"failed to find metadata for POM [%s] in configured search repositories",
-			artifact.GetMavenCoords()))
+	if artifact.Parent != nil {
+		managed = append(managed, artifact.Parent.DependencyManagement...)
 	}
-	defer res.Body.Close()
+	artifact.DependencyManagement = managed
 
-	bs, err := ioutil.ReadAll(res.Body)
+	for _, dep := range artifact.Dependencies {
+		m := artifact.ManagedVersion(dep)
+		if m == nil {
+			continue
+		}
+		if dep.Version == "" {
+			dep.Version = m.Version
+		}
+		if dep.Scope == "" {
+			dep.Scope = m.Scope
+		}
+	}
+}
+
+// FetchMetadata reads the `maven-metadata.xml` of an artifact, which lists every version published to the repository.
+func (r *remoteRepository) FetchMetadata(ctx context.Context, artifact *Artifact, remoteRepository *Repository) (*Metadata, error) {
+	bs, err := r.fetch(ctx, "metadata for POM", artifact, remoteRepository, artifact.MetadataPath())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	metadata, err := UnmarshalMetadata(bs)
+	if err != nil {
+		return nil, &FetchError{Kind: ErrParse, Resource: "metadata for POM", Coordinates: artifact.GetMavenCoords(),
+			Repository: remoteRepository.String(), URL: remoteRepository.URL(artifact.MetadataPath()), Err: err}
+	}
+	return metadata, nil
+}
+
+func (r *remoteRepository) fetchLatestVersion(ctx context.Context, artifact *Artifact, remoteRepository *Repository) (string, error) {
+	metadata, err := r.FetchMetadata(ctx, artifact, remoteRepository)
 	if err != nil {
 		return "", err
 	}
@@ -176,3 +355,112 @@ func (r *remoteRepository) fetchLatestVersion(artifact *Artifact, remoteReposito
 	}
 	return metadata.Release, nil
 }
+
+// fetch downloads a file of an artifact from a repository, describing what was fetched in errors.
+func (r *remoteRepository) fetch(ctx context.Context, resource string, artifact *Artifact, remoteRepository *Repository,
+	path string) ([]byte, error) {
+	url := remoteRepository.URL(path)
+	bs, err := r.get(ctx, url)
+	if err == nil && bs == nil {
+		err = &FetchError{Kind: ErrNotFound, URL: url}
+	}
+	if err != nil {
+		return nil, describe(err, resource, artifact, remoteRepository)
+	}
+	return bs, nil
+}
+
+// get downloads a file from a repository, returning no contents if a local repository does not have it, and a
+// FetchError telling why if a remote one doesn't respond with it.
+func (r *remoteRepository) get(ctx context.Context, url string) ([]byte, error) {
+	// local files are as fast to read as cached ones, so there is no point caching them
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if IsLocalRepository(url) {
+		return getLocal(url)
+	}
+	key := cacheKey(url)
+	isCached := r.cache != nil && isCacheable(key)
+	if r.offline {
+		// there is no way to refresh expired files, so they are better than nothing
+		if isCached {
+			if bs, isCached := r.cache.GetStale(key); isCached {
+				logger.Debugf("Using cached file : %s", url)
+				return bs, nil
+			}
+		}
+		return nil, &OfflineError{URL: url}
+	}
+	if isCached {
+		if bs, isCached := r.cache.Get(key); isCached {
+			logger.Debugf("Using cached file : %s", url)
+			return bs, nil
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c := r.credentialsFor(url); c != nil {
+		c.apply(req)
+	}
+	res, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		// transport errors quote the URL, which may carry a password
+		if ctx.Err() != nil {
+			return nil, redact.Error(err)
+		}
+		return nil, &FetchError{Kind: ErrTransport, URL: url, Err: redact.Error(err)}
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, statusError(url, res.StatusCode)
+	}
+
+	bs, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, &FetchError{Kind: ErrTransport, URL: url, Status: res.StatusCode, Err: redact.Error(err)}
+	}
+	if isCached {
+		// releases never change once published, unlike metadata and snapshots
+		mutable := strings.HasSuffix(url, metadataFile) || strings.Contains(url, snapshotQualifier)
+		if err := r.cache.Put(key, bs, mutable); err != nil {
+			logger.Warnf("Failed to cache file [%s] : %s", url, err)
+		}
+	}
+	return bs, nil
+}
+
+// credentialsFor finds the credentials of the repository a file is downloaded from, preferring the most specific
+// repository when their URLs overlap.
+func (r *remoteRepository) credentialsFor(url string) *Credentials {
+	var found *Credentials
+	longest := -1
+	for repository, c := range r.credentials {
+		if strings.HasPrefix(url, repository+"/") && len(repository) > longest {
+			found, longest = c, len(repository)
+		}
+	}
+	return found
+}
+
+// isCacheable tells whether a file is worth caching. Only POMs, metadata, checksums and signatures are, JARs being
+// downloaded only to be verified, and too large to keep a second copy of.
+func isCacheable(url string) bool {
+	return !strings.HasSuffix(url, jarExtension)
+}
+
+// cacheKey drops credentials and query strings, which may carry tokens, from the URL a file is cached under, so they
+// never end up on disk.
+func cacheKey(location string) string {
+	u, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	return u.String()
+}
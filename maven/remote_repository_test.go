@@ -5,9 +5,17 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
+	"context"
 	"encoding/xml"
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/cache"
 	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
 )
 
 var _ = Describe("RemoteRepository", func() {
@@ -47,12 +55,13 @@ var _ = Describe("RemoteRepository", func() {
 		})
 
 		JustBeforeEach(func() {
-			remoteArtifact, err = repo.FetchRemoteModel(toLookup, mockServer.URL)
+			remoteArtifact, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(mockServer.URL))
 		})
 
 		It("should return a meaningful error", func() {
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(Equal("failed to find POM [org.fake::1.0] in configured search repositories"))
+			Expect(err.Error()).To(Equal(fmt.Sprintf(
+				"failed to find POM [org.fake::1.0] at [%s/org/fake//1.0/-1.0.pom] : status 404", mockServer.URL)))
 		})
 	})
 
@@ -71,7 +80,7 @@ var _ = Describe("RemoteRepository", func() {
 		})
 
 		JustBeforeEach(func() {
-			remoteArtifact, err = repo.FetchRemoteModel(toLookup, mockServer.URL)
+			remoteArtifact, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(mockServer.URL))
 		})
 
 		Context("to fetch from remote", func() {
@@ -86,7 +95,8 @@ var _ = Describe("RemoteRepository", func() {
 
 				It("should return a meaningful error", func() {
 					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(Equal("failed to find POM [foo:bar:1] in configured search repositories"))
+					Expect(err.Error()).To(Equal(fmt.Sprintf(
+						"failed to find POM [foo:bar:1] at [%s/foo/bar/1/bar-1.pom] : status 404", mockServer.URL)))
 				})
 			})
 
@@ -385,6 +395,82 @@ var _ = Describe("RemoteRepository", func() {
 					})
 				})
 
+				Context("when remote POM declares licenses", func() {
+					BeforeEach(func() {
+						mockResponses[0].Parent = &Artifact{GroupID: "foo", ArtifactID: "bar", Version: "5.0"}
+						mockResponses = append(mockResponses, Artifact{
+							GroupID:    "foo",
+							ArtifactID: "bar",
+							Version:    "5.0",
+							Parent:     &Artifact{GroupID: "baz", ArtifactID: "thing", Version: "6.1"},
+						}, Artifact{
+							GroupID:    "baz",
+							ArtifactID: "thing",
+							Version:    "6.1",
+							Licenses:   []License{{Name: "MIT License"}},
+						})
+					})
+
+					It("should inherit the licenses of the nearest parent declaring any", func() {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(remoteArtifact.Licenses).To(ConsistOf(License{Name: "MIT License"}))
+					})
+
+					Context("and the POM declares its own", func() {
+						BeforeEach(func() {
+							mockResponses[0].Licenses = []License{{Name: "Apache-2.0"}}
+						})
+
+						It("should not inherit any license", func() {
+							Expect(err).ToNot(HaveOccurred())
+							Expect(remoteArtifact.Licenses).To(ConsistOf(License{Name: "Apache-2.0"}))
+						})
+					})
+				})
+
+				Context("when remote POM manages the versions of dependencies", func() {
+					BeforeEach(func() {
+						mockResponses[0].Parent = &Artifact{
+							GroupID:    "org.fake",
+							ArtifactID: "parent",
+							Version:    "2.0",
+						}
+						mockResponses[0].Dependencies = []*Artifact{
+							{GroupID: "org.fake", ArtifactID: "managed"},
+							{GroupID: "org.fake", ArtifactID: "inherited"},
+							{GroupID: "org.fake", ArtifactID: "declared", Version: "1.0"},
+						}
+						mockResponses[0].DependencyManagement = []*Artifact{
+							{GroupID: "org.fake", ArtifactID: "managed", Version: "${project.version}", Scope: "test"},
+							{GroupID: "org.fake", ArtifactID: "declared", Version: "2.0"},
+						}
+						mockResponses = append(mockResponses, Artifact{
+							GroupID:    "org.fake",
+							ArtifactID: "parent",
+							Version:    "2.0",
+							DependencyManagement: []*Artifact{
+								{GroupID: "org.fake", ArtifactID: "managed", Version: "3.0"},
+								{GroupID: "org.fake", ArtifactID: "inherited", Version: "${project.version}"},
+							},
+						})
+					})
+
+					It("should fill in missing versions, nearest POM first", func() {
+						Expect(err).ToNot(HaveOccurred())
+
+						Expect(remoteArtifact.Dependencies).To(ConsistOf(
+							PointTo(MatchFields(IgnoreExtras, Fields{
+								"ArtifactID": Equal("managed"), "Version": Equal("1.0.1"), "Scope": Equal("test")})),
+							PointTo(MatchFields(IgnoreExtras, Fields{
+								"ArtifactID": Equal("inherited"), "Version": Equal("2.0"), "Scope": BeEmpty()})),
+							PointTo(MatchFields(IgnoreExtras, Fields{
+								"ArtifactID": Equal("declared"), "Version": Equal("1.0")})),
+						))
+						Expect(remoteArtifact.ManagedVersion(&Artifact{GroupID: "org.fake", ArtifactID: "managed"}).Version).To(Equal("1.0.1"))
+						Expect(remoteArtifact.DependencyManagement).To(HaveLen(4))
+					})
+				})
+
 				Context("without any special context", func() {
 					It("should return expected artifact without error", func() {
 						Expect(err).ToNot(HaveOccurred())
@@ -420,13 +506,224 @@ var _ = Describe("RemoteRepository", func() {
 			})
 		})
 
+		Context("to fetch with a cache", func() {
+			var (
+				cacheDir string
+			)
+
+			BeforeEach(func() {
+				cacheDir, err = ioutil.TempDir("", "remote_repository_test")
+				Expect(err).ToNot(HaveOccurred())
+
+				repo = NewRemoteRepository(WithCache(cache.New(cacheDir, time.Hour)))
+			})
+
+			AfterEach(func() {
+				Expect(os.RemoveAll(cacheDir)).To(Succeed())
+			})
+
+			It("should not need the remote for files it fetched before", func() {
+				Expect(err).ToNot(HaveOccurred())
+				mockServer.Close()
+
+				// a fresh instance has nothing in memory, so has to use the cache on disk
+				repo = NewRemoteRepository(WithCache(cache.New(cacheDir, time.Hour)))
+				remoteArtifact, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(mockServer.URL))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(remoteArtifact.GetMavenCoords()).To(Equal(toLookup.GetMavenCoords()))
+			})
+
+			It("should only use the cache when offline", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				repo = NewRemoteRepository(WithCache(cache.New(cacheDir, -time.Hour)), WithOffline())
+				remoteArtifact, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(mockServer.URL))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(remoteArtifact.GetMavenCoords()).To(Equal(toLookup.GetMavenCoords()))
+
+				_, err = repo.CheckRemoteJAR(context.Background(), toLookup, newRepository(mockServer.URL))
+				Expect(err).To(HaveOccurred())
+				Expect(IsOffline(err)).To(BeTrue())
+				Expect(err.Error()).To(HaveSuffix("/org/fake/some-artifact/1.0.1/some-artifact-1.0.1.jar.sha1] is not available offline"))
+			})
+
+			It("should leave query strings out of the cache", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(mockServer.URL+"/?token=secret"))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, isCached := cache.New(cacheDir, time.Hour).Get(mockServer.URL + "/" + toLookup.PathToPOM())
+				Expect(isCached).To(BeTrue())
+				Expect(filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
+					if err != nil || info.IsDir() {
+						return err
+					}
+					contents, err := ioutil.ReadFile(path)
+					Expect(string(contents)).ToNot(ContainSubstring("secret"), path)
+					return err
+				})).To(Succeed())
+			})
+		})
+
+		Context("to fetch once cancelled", func() {
+			It("should not download anything", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err = repo.FetchRemoteModel(ctx, toLookup, newRepository(mockServer.URL))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HaveSuffix(context.Canceled.Error()))
+			})
+		})
+
+		Context("to fetch from a failing repository", func() {
+			var (
+				failingServer *httptest.Server
+				status        int
+			)
+
+			BeforeEach(func() {
+				status = http.StatusServiceUnavailable
+			})
+
+			JustBeforeEach(func() {
+				failingServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(status)
+				}))
+				remoteArtifact, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(failingServer.URL))
+			})
+
+			AfterEach(func() {
+				failingServer.Close()
+			})
+
+			It("should return a transport error with the status", func() {
+				Expect(IsTransport(err)).To(BeTrue())
+				Expect(err.Error()).To(Equal(fmt.Sprintf(
+					"failed to fetch POM [org.fake:some-artifact:1.0.1] from [%s/org/fake/some-artifact/1.0.1/some-artifact-1.0.1.pom] : status 503",
+					failingServer.URL)))
+			})
+
+			Context("which no longer serves the POM", func() {
+				BeforeEach(func() {
+					status = http.StatusGone
+				})
+
+				It("should return a not found error", func() {
+					Expect(IsNotFound(err)).To(BeTrue())
+					Expect(err.Error()).To(Equal(fmt.Sprintf(
+						"failed to find POM [org.fake:some-artifact:1.0.1] at [%s/org/fake/some-artifact/1.0.1/some-artifact-1.0.1.pom] : status 410",
+						failingServer.URL)))
+				})
+			})
+		})
+
+		Context("to fetch with credentials", func() {
+			var (
+				authServer *httptest.Server
+			)
+
+			BeforeEach(func() {
+				repo = NewRemoteRepository(WithCredentials("http://ignored.example.com", &Credentials{Username: "wrong"}))
+			})
+
+			JustBeforeEach(func() {
+				authServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					username, password, _ := r.BasicAuth()
+					isBasic := username == "admin" && password == "hunter2"
+					isBearer := r.Header.Get("Authorization") == "Bearer t0k3n"
+					isHeader := r.Header.Get("Private-Token") == "t0k3n"
+					if !isBasic && !isBearer && !isHeader {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					mockServer.Config.Handler.ServeHTTP(w, r)
+				}))
+			})
+
+			AfterEach(func() {
+				authServer.Close()
+			})
+
+			It("should send them to the repository they are configured for", func() {
+				_, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(authServer.URL))
+				Expect(err).To(HaveOccurred())
+
+				repo = NewRemoteRepository(WithCredentials(authServer.URL+"/", &Credentials{Username: "admin", Password: "hunter2"}))
+				remoteArtifact, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(authServer.URL))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(remoteArtifact.GetMavenCoords()).To(Equal(toLookup.GetMavenCoords()))
+			})
+
+			It("should tell the repository refused them apart from a missing POM", func() {
+				_, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(authServer.URL))
+				Expect(IsUnauthorized(err)).To(BeTrue())
+				Expect(IsNotFound(err)).To(BeFalse())
+				Expect(err).To(MatchError(HavePrefix("not authorized to fetch POM [org.fake:some-artifact:1.0.1] from [" + authServer.URL)))
+				Expect(err.(*FetchError)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Repository": Equal(authServer.URL),
+					"Status":     Equal(http.StatusUnauthorized),
+				})))
+			})
+
+			It("should send bearer tokens and custom headers", func() {
+				repo = NewRemoteRepository(WithCredentials(authServer.URL, &Credentials{Token: "t0k3n"}))
+				_, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(authServer.URL))
+				Expect(err).ToNot(HaveOccurred())
+
+				repo = NewRemoteRepository(WithCredentials(authServer.URL, &Credentials{
+					Headers: map[string]string{"Private-Token": "t0k3n"},
+				}))
+				_, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(authServer.URL))
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("which redirects to another host", func() {
+				var (
+					cdnServer  *httptest.Server
+					cdnHeaders http.Header
+				)
+
+				BeforeEach(func() {
+					cdnServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						cdnHeaders = r.Header
+						mockServer.Config.Handler.ServeHTTP(w, r)
+					}))
+				})
+
+				JustBeforeEach(func() {
+					authServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						http.Redirect(w, r, cdnServer.URL+r.URL.Path, http.StatusFound)
+					})
+				})
+
+				AfterEach(func() {
+					cdnServer.Close()
+				})
+
+				It("should not send the credentials to the other host", func() {
+					repo = NewRemoteRepository(WithCredentials(authServer.URL, &Credentials{
+						Token:   "t0k3n",
+						Headers: map[string]string{"Private-Token": "t0k3n", "X-JFrog-Art-Api": "k3y"},
+					}))
+					_, err = repo.FetchRemoteModel(context.Background(), toLookup, newRepository(authServer.URL))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(cdnHeaders).ToNot(BeNil())
+					Expect(cdnHeaders).ToNot(HaveKey("Authorization"))
+					Expect(cdnHeaders).ToNot(HaveKey("Private-Token"))
+					Expect(cdnHeaders).ToNot(HaveKey("X-Jfrog-Art-Api"))
+				})
+			})
+		})
+
 		Context("to check Maven JAR for", func() {
 			var (
 				sha1 string
 			)
 
 			JustBeforeEach(func() {
-				sha1, err = repo.CheckRemoteJAR(toLookup, mockServer.URL)
+				sha1, err = repo.CheckRemoteJAR(context.Background(), toLookup, newRepository(mockServer.URL))
 			})
 
 			Context("for an artifact that is NOT present in one of the desired repositories", func() {
@@ -440,7 +737,8 @@ var _ = Describe("RemoteRepository", func() {
 
 				It("should return a meaningful error", func() {
 					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(Equal("failed to find JAR [foo:bar:1] in configured search repositories"))
+					Expect(err.Error()).To(Equal(fmt.Sprintf(
+						"failed to find JAR [foo:bar:1] at [%s/foo/bar/1/bar-1.jar.sha1] : status 404", mockServer.URL)))
 				})
 			})
 
@@ -453,6 +751,17 @@ var _ = Describe("RemoteRepository", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(sha1).To(Equal("some-sha1"))
 				})
+
+				Context("when the checksum is suffixed with the file name", func() {
+					BeforeEach(func() {
+						mockResponses[0].SHA = "some-sha1  some-artifact-1.0.1.jar\n"
+					})
+
+					It("should return just the checksum", func() {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(sha1).To(Equal("some-sha1"))
+					})
+				})
 			})
 		})
 	})
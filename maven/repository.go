@@ -0,0 +1,94 @@
+package maven
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/redact"
+	"github.com/pkg/errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var repeatedSlashes = regexp.MustCompile(`/{2,}`)
+
+// Repository is the location of a Maven repository, parsed and normalized once so that the URLs of its files can be
+// built safely, whatever the path, query string or credentials of the repository.
+type Repository struct {
+	// location is the normalized location of a local repository, as given
+	location string
+	// url is the normalized URL of a remote repository, nil for local ones
+	url *url.URL
+}
+
+// NewRepository parses the location of a repository, either the URL of a remote repository or a local one, like
+// `~/.m2/repository`, given as a `file://` URL or a path.
+func NewRepository(location string) (*Repository, error) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return nil, errors.New("invalid repository, empty location")
+	}
+	if IsLocalRepository(location) {
+		return &Repository{location: trimTrailingSlashes(location)}, nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		// parse errors quote the URL, which may carry a password
+		return nil, errors.Errorf("invalid repository [%s]", redact.String(location))
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("invalid repository [%s], expected an http(s) URL or a local repository",
+			redact.String(location))
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("invalid repository [%s], missing host", redact.String(location))
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	// some servers refuse paths with empty segments, which repository URLs ending with a "/" used to produce
+	u.Path = strings.TrimRight(repeatedSlashes.ReplaceAllString(u.Path, "/"), "/")
+	u.RawPath = strings.TrimRight(repeatedSlashes.ReplaceAllString(u.RawPath, "/"), "/")
+	return &Repository{url: u}, nil
+}
+
+func (r *Repository) IsLocal() bool {
+	return r.url == nil
+}
+
+// URL is the location of a file of the repository, given its path relative to the root of the repository. Query
+// strings and credentials of the repository are kept, so that requests for the file are authorized like requests for
+// the repository would be.
+func (r *Repository) URL(path string) string {
+	path = strings.TrimLeft(path, "/")
+	if r.IsLocal() {
+		return strings.TrimRight(r.location, "/") + "/" + path
+	}
+	u := *r.url
+	u.Path = r.url.Path + "/" + path
+	if r.url.RawPath != "" {
+		u.RawPath = r.url.RawPath + "/" + (&url.URL{Path: path}).EscapedPath()
+	}
+	return u.String()
+}
+
+// String is the normalized location of the repository, as written to generated workspaces. Credentials and query
+// strings are left out, as they may carry tokens, and Bazel appends the paths of files to the location as it is.
+func (r *Repository) String() string {
+	if r.IsLocal() {
+		return r.location
+	}
+	u := *r.url
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	return u.String()
+}
+
+// trimTrailingSlashes keeps the slashes of root directories, like `/` or `file:///`.
+func trimTrailingSlashes(path string) string {
+	trimmed := strings.TrimRight(path, "/")
+	if trimmed == "" || strings.HasSuffix(trimmed, ":") {
+		return path
+	}
+	return trimmed
+}
@@ -0,0 +1,43 @@
+package maven
+
+const (
+	ScopeCompile  = "compile"
+	ScopeProvided = "provided"
+	ScopeRuntime  = "runtime"
+	ScopeTest     = "test"
+	ScopeSystem   = "system"
+	ScopeImport   = "import"
+)
+
+// TransitiveScope returns the scope a dependency declared with `declared` ends up with when reached through an
+// artifact resolved with scope `parent`, following Maven's scope mediation table. An empty result means the
+// dependency is not part of the graph.
+// See: https://maven.apache.org/guides/introduction/introduction-to-dependency-mechanism.html#Dependency_Scope
+func TransitiveScope(parent, declared string) string {
+	if declared == "" {
+		declared = ScopeCompile
+	}
+	// neither of these ever contribute a dependency to a classpath
+	if declared == ScopeSystem || declared == ScopeImport {
+		return ""
+	}
+	// provided and test dependencies are never transitive, not even those of the root artifact which its consumers
+	// never get either
+	if declared == ScopeProvided || declared == ScopeTest {
+		return ""
+	}
+	// other direct dependencies of the root artifact keep whatever scope they were declared with
+	if parent == "" {
+		return declared
+	}
+
+	switch parent {
+	case ScopeCompile:
+		return declared
+	case ScopeRuntime:
+		return ScopeRuntime
+	case ScopeProvided, ScopeTest:
+		return parent
+	}
+	return ""
+}
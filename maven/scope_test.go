@@ -0,0 +1,48 @@
+package maven_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
+)
+
+var _ = Describe("Scope", func() {
+	Context("Given a direct dependency of the root artifact", func() {
+		It("should keep the declared scope", func() {
+			Expect(TransitiveScope("", "")).To(Equal(ScopeCompile))
+			Expect(TransitiveScope("", ScopeCompile)).To(Equal(ScopeCompile))
+			Expect(TransitiveScope("", ScopeRuntime)).To(Equal(ScopeRuntime))
+		})
+
+		It("should drop provided and test scoped dependencies", func() {
+			Expect(TransitiveScope("", ScopeProvided)).To(BeEmpty())
+			Expect(TransitiveScope("", ScopeTest)).To(BeEmpty())
+		})
+
+		It("should drop system and import scoped dependencies", func() {
+			Expect(TransitiveScope("", ScopeSystem)).To(BeEmpty())
+			Expect(TransitiveScope("", ScopeImport)).To(BeEmpty())
+		})
+	})
+
+	Context("Given a transitive dependency", func() {
+		It("should follow Maven's scope mediation table", func() {
+			Expect(TransitiveScope(ScopeCompile, ScopeCompile)).To(Equal(ScopeCompile))
+			Expect(TransitiveScope(ScopeCompile, ScopeRuntime)).To(Equal(ScopeRuntime))
+			Expect(TransitiveScope(ScopeRuntime, ScopeCompile)).To(Equal(ScopeRuntime))
+			Expect(TransitiveScope(ScopeRuntime, ScopeRuntime)).To(Equal(ScopeRuntime))
+			Expect(TransitiveScope(ScopeProvided, ScopeCompile)).To(Equal(ScopeProvided))
+			Expect(TransitiveScope(ScopeProvided, ScopeRuntime)).To(Equal(ScopeProvided))
+			Expect(TransitiveScope(ScopeTest, ScopeCompile)).To(Equal(ScopeTest))
+			Expect(TransitiveScope(ScopeTest, ScopeRuntime)).To(Equal(ScopeTest))
+		})
+
+		It("should never include provided or test scoped dependencies", func() {
+			for _, parent := range []string{ScopeCompile, ScopeRuntime, ScopeProvided, ScopeTest} {
+				Expect(TransitiveScope(parent, ScopeProvided)).To(BeEmpty())
+				Expect(TransitiveScope(parent, ScopeTest)).To(BeEmpty())
+			}
+		})
+	})
+})
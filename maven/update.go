@@ -0,0 +1,161 @@
+package maven
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// Updates are the newest versions an artifact could be upgraded to, by how much of its version they change. Each one
+// is empty if there is no such update.
+type Updates struct {
+	Artifact *Artifact
+	// Patch keeps the major and minor versions, like `1.2.3` to `1.2.5`.
+	Patch string
+	// Minor keeps the major version, like `1.2.3` to `1.4.0`.
+	Minor string
+	// Major is any newer major version, like `1.2.3` to `3.0.0`.
+	Major string
+}
+
+// IsOutdated tells whether any newer version is available.
+func (u *Updates) IsOutdated() bool {
+	return u.Patch != "" || u.Minor != "" || u.Major != ""
+}
+
+// FindUpdates sorts the versions newer than that of an artifact into patch, minor and major updates, keeping the
+// newest of each. Pre-releases are left out unless asked for.
+func FindUpdates(artifact *Artifact, versions []string, preReleases bool) *Updates {
+	updates := &Updates{Artifact: artifact}
+	current := parseVersion(artifact.Version)
+	for _, version := range versions {
+		if CompareVersions(version, artifact.Version) <= 0 || (!preReleases && IsPreRelease(version)) {
+			continue
+		}
+		candidate := parseVersion(version)
+		update := &updates.Patch
+		switch {
+		case versionNumber(candidate, 0).Cmp(versionNumber(current, 0)) != 0:
+			update = &updates.Major
+		case versionNumber(candidate, 1).Cmp(versionNumber(current, 1)) != 0:
+			update = &updates.Minor
+		}
+		if *update == "" || CompareVersions(version, *update) > 0 {
+			*update = version
+		}
+	}
+	return updates
+}
+
+// versionNumber returns the i-th leading number of a version, 0 if the version has fewer numbers, so that `1.0` and
+// `1` have the same minor version.
+func versionNumber(version listItem, i int) *big.Int {
+	for j, item := range version {
+		number, isNumber := item.(intItem)
+		if !isNumber {
+			break
+		}
+		if j == i {
+			return number.value
+		}
+	}
+	return big.NewInt(0)
+}
+
+// FetchVersions lists the versions of an artifact published to any of the repositories, as read from their
+// `maven-metadata.xml`, oldest first.
+func (w *DependencyWalker) FetchVersions(ctx context.Context, artifact *Artifact) ([]string, error) {
+	versions := make([]string, 0)
+	seen := map[string]bool{}
+	isFound := false
+	var lastErr error
+	for _, repository := range w.Repositories {
+		metadata, err := w.RemoteRepository.FetchMetadata(ctx, artifact, repository)
+		if err != nil {
+			if ctx.Err() != nil || !(IsNotFound(err) || IsOffline(err)) {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+		isFound = true
+		for _, version := range metadata.Versions {
+			if !seen[version] {
+				seen[version] = true
+				versions = append(versions, version)
+			}
+		}
+	}
+	if !isFound {
+		if lastErr == nil {
+			lastErr = errors.New("no repositories configured")
+		}
+		return nil, lastErr
+	}
+	sort.SliceStable(versions, func(i, j int) bool { return CompareVersions(versions[i], versions[j]) < 0 })
+	return versions, nil
+}
+
+// CheckUpdates finds the updates of every artifact of a resolved graph, sorted by coordinates. Artifacts no repository
+// has metadata for are left out.
+func (w *DependencyWalker) CheckUpdates(ctx context.Context, root *Artifact, preReleases bool) ([]*Updates, error) {
+	artifacts := make([]*Artifact, 0)
+	seen := map[string]bool{}
+	var visit func(artifact *Artifact)
+	visit = func(artifact *Artifact) {
+		if seen[artifact.GetMavenCoords()] {
+			return
+		}
+		seen[artifact.GetMavenCoords()] = true
+		artifacts = append(artifacts, artifact)
+		for _, dep := range artifact.Dependencies {
+			visit(dep)
+		}
+	}
+	visit(root)
+
+	jobs := w.Jobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	results := make([]*Updates, len(artifacts))
+	errs := make([]error, len(artifacts))
+	queue := make(chan int)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < jobs && i < len(artifacts); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				var versions []string
+				versions, errs[j] = w.FetchVersions(ctx, artifacts[j])
+				if errs[j] == nil {
+					results[j] = FindUpdates(artifacts[j], versions, preReleases)
+				}
+			}
+		}()
+	}
+	for i := range artifacts {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	updates := make([]*Updates, 0, len(artifacts))
+	for i, err := range errs {
+		if err != nil {
+			if ctx.Err() != nil || !(IsNotFound(err) || IsOffline(err)) {
+				return nil, errors.Wrapf(err, "failed to check updates of artifact [%s]", artifacts[i].GetMavenCoords())
+			}
+			logger.Warnf("Could not find versions of artifact [%s] : %s", artifacts[i].GetMavenCoords(), err)
+			continue
+		}
+		updates = append(updates, results[i])
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].Artifact.GetMavenCoords() < updates[j].Artifact.GetMavenCoords()
+	})
+	return updates, nil
+}
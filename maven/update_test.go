@@ -0,0 +1,170 @@
+package maven_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"context"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven/mavenfakes"
+)
+
+var _ = Describe("IsPreRelease", func() {
+	It("should recognize pre-release qualifiers", func() {
+		for _, version := range []string{"1.0-alpha", "1.0-alpha-1", "1.0.0-beta2", "2.0.0.Beta1", "1.0-M1", "1.0-RC1",
+			"1.0-rc.2", "1.0.CR1", "1.0-SNAPSHOT", "1.0a1", "9-ea", "1.0-preview"} {
+			Expect(IsPreRelease(version)).To(BeTrue(), version)
+		}
+	})
+
+	It("should not mistake releases for pre-releases", func() {
+		for _, version := range []string{"1.0", "1.0.0-GA", "1.0.Final", "1.0-RELEASE", "31.1-jre", "31.1-android",
+			"1.0-sp1", "20040616", "1.1-1"} {
+			Expect(IsPreRelease(version)).To(BeFalse(), version)
+		}
+	})
+})
+
+var _ = Describe("FindUpdates", func() {
+	var (
+		artifact    *Artifact
+		versions    []string
+		preReleases bool
+		updates     *Updates
+	)
+
+	BeforeEach(func() {
+		artifact = &Artifact{GroupID: "foo", ArtifactID: "bar", Version: "1.2.3"}
+		versions = []string{"1.0", "1.2.3", "1.2.4", "1.2.10", "1.2.11-RC1", "1.3.0", "1.10.0", "2.0", "3.0.0-M1"}
+		preReleases = false
+	})
+
+	JustBeforeEach(func() {
+		updates = FindUpdates(artifact, versions, preReleases)
+	})
+
+	It("should keep the newest patch, minor and major versions", func() {
+		Expect(updates.Artifact).To(Equal(artifact))
+		Expect(updates.Patch).To(Equal("1.2.10"))
+		Expect(updates.Minor).To(Equal("1.10.0"))
+		Expect(updates.Major).To(Equal("2.0"))
+		Expect(updates.IsOutdated()).To(BeTrue())
+	})
+
+	Context("when pre-releases are asked for", func() {
+		BeforeEach(func() {
+			preReleases = true
+		})
+
+		It("should consider them", func() {
+			Expect(updates.Patch).To(Equal("1.2.11-RC1"))
+			Expect(updates.Major).To(Equal("3.0.0-M1"))
+		})
+	})
+
+	Context("when the version has fewer numbers than the others", func() {
+		BeforeEach(func() {
+			artifact.Version = "1"
+			versions = []string{"1.0.1", "1.1", "2"}
+		})
+
+		It("should consider the missing numbers zero", func() {
+			Expect(updates.Patch).To(Equal("1.0.1"))
+			Expect(updates.Minor).To(Equal("1.1"))
+			Expect(updates.Major).To(Equal("2"))
+		})
+	})
+
+	Context("when the artifact is up to date", func() {
+		BeforeEach(func() {
+			artifact.Version = "2.0.0"
+		})
+
+		It("should not report any update", func() {
+			Expect(updates.IsOutdated()).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("DependencyWalker updates", func() {
+	var (
+		err              error
+		remoteRepository *mavenfakes.FakeRemoteRepository
+		walker           *DependencyWalker
+		root             *Artifact
+		metadata         map[string]map[string]*Metadata
+		updates          []*Updates
+	)
+
+	BeforeEach(func() {
+		hamcrest := &Artifact{GroupID: "org.hamcrest", ArtifactID: "hamcrest-core", Version: "1.1"}
+		root = &Artifact{GroupID: "junit", ArtifactID: "junit", Version: "4.9",
+			Dependencies: []*Artifact{hamcrest, {GroupID: "foo", ArtifactID: "bar", Version: "1.0",
+				Dependencies: []*Artifact{hamcrest}}}}
+		metadata = map[string]map[string]*Metadata{
+			"http://localhost:8080": {
+				"junit:junit":                {Versions: []string{"4.9", "4.10", "4.13.2", "5.0-M1"}},
+				"org.hamcrest:hamcrest-core": {Versions: []string{"1.1", "1.3"}},
+			},
+			"http://localhost:8081": {
+				"junit:junit": {Versions: []string{"4.12", "4.13.2"}},
+			},
+		}
+		remoteRepository = new(mavenfakes.FakeRemoteRepository)
+		remoteRepository.FetchMetadataStub = func(ctx context.Context, artifact *Artifact, repository *Repository) (*Metadata, error) {
+			if m, isFound := metadata[repository.String()][artifact.GetVersionlessCoords()]; isFound {
+				return m, nil
+			}
+			return nil, &FetchError{Kind: ErrNotFound, Resource: "metadata for POM",
+				Coordinates: artifact.GetMavenCoords(), Repository: repository.String(),
+				URL: repository.URL(artifact.MetadataPath()), Status: 404}
+		}
+		walker = &DependencyWalker{
+			Repositories:     []*Repository{newRepository("http://localhost:8080/"), newRepository("http://localhost:8081/")},
+			Jobs:             2,
+			RemoteRepository: remoteRepository,
+		}
+	})
+
+	JustBeforeEach(func() {
+		updates, err = walker.CheckUpdates(context.Background(), root, false)
+	})
+
+	It("should check each artifact once, merging the versions of every repository", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(remoteRepository.FetchMetadataCallCount()).To(Equal(6))
+		Expect(updates).To(HaveLen(2))
+		Expect(updates[0]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Artifact": Equal(root),
+			"Patch":    BeEmpty(),
+			"Minor":    Equal("4.13.2"),
+			"Major":    BeEmpty(),
+		})))
+		Expect(updates[1]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Artifact": Equal(root.Dependencies[0]),
+			"Minor":    Equal("1.3"),
+		})))
+	})
+
+	It("should list the versions in order", func() {
+		versions, err := walker.FetchVersions(context.Background(), root)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(versions).To(Equal([]string{"4.9", "4.10", "4.12", "4.13.2", "5.0-M1"}))
+	})
+
+	Context("when a repository refuses to serve metadata", func() {
+		BeforeEach(func() {
+			remoteRepository.FetchMetadataStub = func(ctx context.Context, artifact *Artifact, repository *Repository) (*Metadata, error) {
+				return nil, &FetchError{Kind: ErrUnauthorized, Resource: "metadata for POM",
+					Coordinates: artifact.GetMavenCoords(), Repository: repository.String(),
+					URL: repository.URL(artifact.MetadataPath()), Status: 401}
+			}
+		})
+
+		It("should fail", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(IsUnauthorized(err)).To(BeTrue())
+		})
+	})
+})
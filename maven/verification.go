@@ -0,0 +1,182 @@
+package maven
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"github.com/jspawar/generate-bazel-workspace-gradle/pgp"
+	"github.com/pkg/errors"
+	"hash"
+	"sort"
+	"strings"
+)
+
+// VerificationPolicy decides what happens to files which fail verification, like Maven's `checksumPolicy`.
+type VerificationPolicy string
+
+const (
+	FailPolicy   VerificationPolicy = "fail"
+	WarnPolicy   VerificationPolicy = "warn"
+	IgnorePolicy VerificationPolicy = "ignore"
+)
+
+const signatureExtension = `.asc`
+
+// checksumAlgorithms are tried strongest first, only the strongest checksum published is verified.
+var checksumAlgorithms = []struct {
+	extension string
+	new       func() hash.Hash
+}{
+	{".sha512", sha512.New},
+	{".sha256", sha256.New},
+	{sha1Extension, sha1.New},
+	{".md5", md5.New},
+}
+
+func NewVerificationPolicy(name string) (VerificationPolicy, error) {
+	for _, p := range VerificationPolicyNames() {
+		if name == p {
+			return VerificationPolicy(name), nil
+		}
+	}
+	return "", errors.Errorf("unknown verification policy [%s], expected one of : %s",
+		name, strings.Join(VerificationPolicyNames(), ", "))
+}
+
+func VerificationPolicyNames() []string {
+	names := []string{string(FailPolicy), string(WarnPolicy), string(IgnorePolicy)}
+	sort.Strings(names)
+	return names
+}
+
+// WithChecksums verifies downloaded POMs and JARs against the checksums published next to them. Verifying JARs means
+// downloading them, rather than only their checksum.
+func WithChecksums(policy VerificationPolicy) RemoteRepositoryOption {
+	return func(r *remoteRepository) {
+		r.checksumPolicy = policy
+	}
+}
+
+// WithSignatures verifies downloaded POMs and JARs against the PGP signatures published next to them, which have to be
+// made by trusted keys of the keyring.
+func WithSignatures(keyring *pgp.Keyring, policy VerificationPolicy) RemoteRepositoryOption {
+	return func(r *remoteRepository) {
+		r.keyring = keyring
+		r.signaturePolicy = policy
+	}
+}
+
+// verifiesJARs tells whether JARs have to be downloaded, rather than only their checksum.
+func (r *remoteRepository) verifiesJARs() bool {
+	return r.checksumPolicy != IgnorePolicy || r.keyring != nil && r.signaturePolicy != IgnorePolicy
+}
+
+// verify checks a file downloaded from a repository against its published checksum and signature, as configured.
+// Files of local repositories were verified when they were installed, if at all, so they are trusted as they are.
+func (r *remoteRepository) verify(ctx context.Context, resource string, artifact *Artifact, remoteRepository *Repository,
+	path string, contents []byte) error {
+	if remoteRepository.IsLocal() {
+		return nil
+	}
+	url := remoteRepository.URL(path)
+
+	if r.checksumPolicy != IgnorePolicy {
+		err := r.verifyChecksum(ctx, remoteRepository, path, contents)
+		if err := r.apply(r.checksumPolicy, err, resource, artifact, remoteRepository, url); err != nil {
+			return err
+		}
+	}
+	if r.keyring != nil && r.signaturePolicy != IgnorePolicy {
+		err := r.verifySignature(ctx, remoteRepository, path, contents)
+		if err := r.apply(r.signaturePolicy, err, resource, artifact, remoteRepository, url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verificationFailure is a file failing verification, as opposed to failing to download what verifying it needs.
+type verificationFailure struct {
+	error
+}
+
+// apply turns a verification failure into an error or a warning, depending on the policy. Failing to download what
+// verifying needs is not a verification failure, so is returned either way.
+func (r *remoteRepository) apply(policy VerificationPolicy, err error, resource string, artifact *Artifact,
+	remoteRepository *Repository, url string) error {
+	if err == nil {
+		return nil
+	}
+	failure, isFailure := err.(*verificationFailure)
+	if !isFailure {
+		return describe(err, resource, artifact, remoteRepository)
+	}
+	verificationErr := &FetchError{Kind: ErrIntegrity, Resource: resource, Coordinates: artifact.GetMavenCoords(),
+		Repository: remoteRepository.String(), URL: url, Err: failure.error}
+	if policy == WarnPolicy {
+		logger.Warn(verificationErr.Error())
+		return nil
+	}
+	return verificationErr
+}
+
+// verifyChecksum checks a file against the first checksum published next to it. Sidecar URLs are built from the path of
+// the file, so that the extension doesn't end up in the query string of the repository.
+func (r *remoteRepository) verifyChecksum(ctx context.Context, remoteRepository *Repository, path string,
+	contents []byte) error {
+	for _, algorithm := range checksumAlgorithms {
+		published, err := r.getSidecar(ctx, remoteRepository.URL(path+algorithm.extension))
+		if err != nil {
+			return err
+		}
+		if published == nil {
+			continue
+		}
+		// checksum files may be suffixed with the name of the file they were generated for
+		fields := strings.Fields(string(published))
+		if len(fields) < 1 {
+			return &verificationFailure{errors.Errorf("empty %s checksum", strings.TrimPrefix(algorithm.extension, "."))}
+		}
+		digest := algorithm.new()
+		digest.Write(contents)
+		actual := hex.EncodeToString(digest.Sum(nil))
+		if !strings.EqualFold(fields[0], actual) {
+			return &verificationFailure{errors.Errorf("%s checksum mismatch, published [%s] but was [%s]",
+				strings.TrimPrefix(algorithm.extension, "."), fields[0], actual)}
+		}
+		logger.Debugf("Verified %s checksum of : %s", strings.TrimPrefix(algorithm.extension, "."),
+			remoteRepository.URL(path))
+		return nil
+	}
+	return &verificationFailure{errors.New("no checksum published")}
+}
+
+func (r *remoteRepository) verifySignature(ctx context.Context, remoteRepository *Repository, path string,
+	contents []byte) error {
+	signature, err := r.getSidecar(ctx, remoteRepository.URL(path+signatureExtension))
+	if err != nil {
+		return err
+	}
+	if signature == nil {
+		return &verificationFailure{errors.New("no signature published")}
+	}
+	key, err := r.keyring.Verify(contents, signature)
+	if err != nil {
+		return &verificationFailure{err}
+	}
+	logger.Debugf("Verified signature of [%s] made by key : %s", remoteRepository.URL(path), key.Fingerprint)
+	return nil
+}
+
+// getSidecar downloads a file published next to another one, returning no contents when it isn't published, or isn't
+// available offline.
+func (r *remoteRepository) getSidecar(ctx context.Context, url string) ([]byte, error) {
+	bs, err := r.get(ctx, url)
+	if IsNotFound(err) || IsOffline(err) {
+		return nil, nil
+	}
+	return bs, err
+}
@@ -0,0 +1,271 @@
+package maven_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"github.com/jspawar/generate-bazel-workspace-gradle/cache"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/jspawar/generate-bazel-workspace-gradle/pgp"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+)
+
+// generated with `gpg --armor --export` and `gpg --armor --detach-sign` of the JAR below
+const signingKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mI0EatWElQEEALPP0zTqCykQncchyhU60vfLN1IALThXtoS4fgfr6eCdA7mowV5Y
+WhY1b90kwn3mSBjv2lcjhc7CcqgzWKmPcfifF5ChmnUTi1llc2yYEJvhJNUUtFrU
+K8mHyEEgCsZjFwBBTkbf5+fyvTrYIot2Z7iys+yxAsK5rCzNAACJO1IDABEBAAG0
+JVRydXN0ZWQgUmVsZWFzZSA8dHJ1c3RlZEBleGFtcGxlLmNvbT6IzgQTAQoAOBYh
+BEnloVDzU36CH9u9s/mcKxmXsKH5BQJq1YSVAhsDBQsJCAcCBhUKCQgLAgQWAgMB
+Ah4BAheAAAoJEPmcKxmXsKH5HWwD/iZNwJNmfTeWvdAcRxVs5kCiqiirMOp/Kqav
+YcaOJzt8MkdppLvpZjgDtSInfPH89QkoWL/OdItDi4GR7DaNDH+8AzzYTAWUqgCr
+WCFQN4gK9wVxcAvNB8rXJ+Dx2TlNrGrVTKRfFS0MBj7I3P7SYBrFjfV5joiMdtmO
+lK+TPwNW
+=zV/z
+-----END PGP PUBLIC KEY BLOCK-----`
+const jarSignature = `-----BEGIN PGP SIGNATURE-----
+
+iLMEAAEKAB0WIQRJ5aFQ81N+gh/bvbP5nCsZl7Ch+QUCatWEmQAKCRD5nCsZl7Ch
++ebJBACzyy35wojflbhxhqfzkJ/cQqHWyE9ayVonV4+B0skqmGkkukrPxw2wcfqP
+0G6eG7YO87ozJXcGc4LlIlcbPZRtoyM1xvJGnykEFEd4VDOmPibYOGiBM2bAsVXH
+OxfAKfZTh9J9Y076YOLeXw2co+YMN7TTIAuyUQAwN3kt1kAFWA==
+=K+M4
+-----END PGP SIGNATURE-----`
+
+var _ = Describe("Verification", func() {
+	var (
+		err      error
+		server   *httptest.Server
+		location string
+		token    string
+		files    map[string][]byte
+		options  []RemoteRepositoryOption
+		toLookup *Artifact
+		pom      []byte
+		jar      []byte
+	)
+
+	sum := func(contents []byte, digest func([]byte) []byte) []byte {
+		return []byte(hex.EncodeToString(digest(contents)) + "  some-artifact-1.0.jar")
+	}
+	sha1Of := func(bs []byte) []byte { s := sha1.Sum(bs); return s[:] }
+	sha256Of := func(bs []byte) []byte { s := sha256.Sum256(bs); return s[:] }
+	md5Of := func(bs []byte) []byte { s := md5.Sum(bs); return s[:] }
+
+	BeforeEach(func() {
+		toLookup = &Artifact{GroupID: "org.fake", ArtifactID: "some-artifact", Version: "1.0"}
+		pom, err = xml.Marshal(toLookup)
+		Expect(err).ToNot(HaveOccurred())
+		jar = []byte("not really a JAR")
+		files = map[string][]byte{
+			"/" + toLookup.PathToPOM():           pom,
+			"/" + toLookup.PathToPOM() + ".sha1": sum(pom, sha1Of),
+			"/" + toLookup.PathToJar():           jar,
+			"/" + toLookup.PathToJar() + ".sha1": sum(jar, sha1Of),
+			"/" + toLookup.PathToJar() + ".asc":  []byte(jarSignature),
+		}
+		options = []RemoteRepositoryOption{WithChecksums(FailPolicy)}
+		token = ""
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contents, isPublished := files[r.URL.Path]
+			if !isPublished || r.URL.Query().Get("token") != token {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(contents)
+		}))
+		location = server.URL
+	})
+
+	// authenticateByQuery makes the server require a token passed in the query string of the repository URL.
+	authenticateByQuery := func() {
+		token = "secret"
+		location = server.URL + "/?token=" + token
+	}
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("of POMs", func() {
+		JustBeforeEach(func() {
+			_, err = NewRemoteRepository(options...).FetchRemoteModel(context.Background(), toLookup, newRepository(location))
+		})
+
+		It("should accept POMs matching their checksum", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("given a repository authenticated by query string", func() {
+			BeforeEach(authenticateByQuery)
+
+			It("should fetch the checksum from the same repository", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("given checksums of several algorithms", func() {
+			BeforeEach(func() {
+				files["/"+toLookup.PathToPOM()+".sha1"] = sum([]byte("tampered"), sha1Of)
+				files["/"+toLookup.PathToPOM()+".sha256"] = sum(pom, sha256Of)
+			})
+
+			It("should only verify the strongest one", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("given a mismatching checksum", func() {
+			BeforeEach(func() {
+				delete(files, "/"+toLookup.PathToPOM()+".sha1")
+				files["/"+toLookup.PathToPOM()+".md5"] = sum([]byte("tampered"), md5Of)
+			})
+
+			It("should return a meaningful error", func() {
+				Expect(IsIntegrity(err)).To(BeTrue())
+				Expect(err.Error()).To(HavePrefix("failed to verify POM [org.fake:some-artifact:1.0] from [" + server.URL))
+				Expect(err.Error()).To(ContainSubstring("md5 checksum mismatch, published [" + hex.EncodeToString(md5Of([]byte("tampered")))))
+			})
+
+			Context("with the warn policy", func() {
+				BeforeEach(func() {
+					options = []RemoteRepositoryOption{WithChecksums(WarnPolicy)}
+				})
+
+				It("should still accept them", func() {
+					Expect(err).ToNot(HaveOccurred())
+				})
+			})
+		})
+
+		Context("given no checksum", func() {
+			BeforeEach(func() {
+				delete(files, "/"+toLookup.PathToPOM()+".sha1")
+			})
+
+			It("should return a meaningful error", func() {
+				Expect(IsIntegrity(err)).To(BeTrue())
+				Expect(err.Error()).To(HaveSuffix(".pom] : no checksum published"))
+			})
+
+			Context("with the ignore policy", func() {
+				BeforeEach(func() {
+					options = []RemoteRepositoryOption{WithChecksums(IgnorePolicy)}
+				})
+
+				It("should still accept them", func() {
+					Expect(err).ToNot(HaveOccurred())
+				})
+			})
+		})
+	})
+
+	Context("of JARs", func() {
+		var (
+			sha string
+		)
+
+		JustBeforeEach(func() {
+			sha, err = NewRemoteRepository(options...).CheckRemoteJAR(context.Background(), toLookup, newRepository(location))
+		})
+
+		It("should download them to compute their checksum", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sha).To(Equal(hex.EncodeToString(sha1Of(jar))))
+		})
+
+		Context("given a cache", func() {
+			var (
+				cacheDir string
+			)
+
+			BeforeEach(func() {
+				cacheDir, err = ioutil.TempDir("", "verification_test")
+				Expect(err).ToNot(HaveOccurred())
+				options = append(options, WithCache(cache.New(cacheDir, time.Hour)))
+			})
+
+			AfterEach(func() {
+				Expect(os.RemoveAll(cacheDir)).To(Succeed())
+			})
+
+			It("should only cache their checksum", func() {
+				Expect(err).ToNot(HaveOccurred())
+				c := cache.New(cacheDir, time.Hour)
+				_, isCached := c.Get(server.URL + "/" + toLookup.PathToJar())
+				Expect(isCached).To(BeFalse())
+				_, isCached = c.Get(server.URL + "/" + toLookup.PathToJar() + ".sha1")
+				Expect(isCached).To(BeTrue())
+			})
+
+			Context("from a repository authenticated by query string", func() {
+				BeforeEach(authenticateByQuery)
+
+				It("should still only cache their checksum", func() {
+					Expect(err).ToNot(HaveOccurred())
+					c := cache.New(cacheDir, time.Hour)
+					_, isCached := c.Get(server.URL + "/" + toLookup.PathToJar())
+					Expect(isCached).To(BeFalse())
+					_, isCached = c.Get(server.URL + "/" + toLookup.PathToJar() + ".sha1")
+					Expect(isCached).To(BeTrue())
+				})
+			})
+		})
+
+		Context("which were tampered with", func() {
+			BeforeEach(func() {
+				files["/"+toLookup.PathToJar()] = []byte("tampered")
+			})
+
+			It("should return a meaningful error", func() {
+				Expect(IsIntegrity(err)).To(BeTrue())
+				Expect(err.Error()).To(ContainSubstring("sha1 checksum mismatch"))
+			})
+		})
+
+		Context("given a keyring", func() {
+			JustBeforeEach(func() {
+				keyring, keyringErr := pgp.ReadKeyring([]byte(signingKey), nil)
+				Expect(keyringErr).ToNot(HaveOccurred())
+				options = append(options, WithSignatures(keyring, FailPolicy))
+				sha, err = NewRemoteRepository(options...).CheckRemoteJAR(context.Background(), toLookup, newRepository(location))
+			})
+
+			It("should verify their signature", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sha).To(Equal(hex.EncodeToString(sha1Of(jar))))
+			})
+
+			Context("from a repository authenticated by query string", func() {
+				BeforeEach(authenticateByQuery)
+
+				It("should fetch the signature from the same repository", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(sha).To(Equal(hex.EncodeToString(sha1Of(jar))))
+				})
+			})
+
+			Context("and an unsigned JAR", func() {
+				BeforeEach(func() {
+					delete(files, "/"+toLookup.PathToJar()+".asc")
+				})
+
+				It("should return a meaningful error", func() {
+					Expect(IsIntegrity(err)).To(BeTrue())
+					Expect(err.Error()).To(HaveSuffix(".jar] : no signature published"))
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,246 @@
+package maven
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// qualifiers are the well-known qualifiers of versions, in increasing order. The empty qualifier is the release.
+var qualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var qualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+var releaseQualifier = comparableQualifier("")
+
+// preReleaseQualifiers are qualifiers Maven doesn't know of, which projects commonly use for pre-releases anyway.
+var preReleaseQualifiers = map[string]bool{
+	"preview": true,
+	"pre":     true,
+	"ea":      true,
+	"dev":     true,
+}
+
+// CompareVersions orders versions the way Maven does, returning a negative number if `a` is older than `b`, a positive
+// one if it is newer, and 0 if both are equivalent, like `1.0` and `1.0.0-ga`. Numbers are compared numerically, and
+// known qualifiers come in the order alpha < beta < milestone < rc < snapshot < release < sp, unknown ones being
+// newer than all of them.
+func CompareVersions(a string, b string) int {
+	return parseVersion(a).compare(parseVersion(b))
+}
+
+// IsPreRelease tells whether a version has a qualifier ordered before releases, like `1.0-alpha`, `1.0-RC1`, `1.0-M1`
+// or `1.0-SNAPSHOT`, or one commonly used for previews.
+func IsPreRelease(version string) bool {
+	return parseVersion(version).isPreRelease()
+}
+
+// versionItem is a part of a version, either a number, a qualifier or a list of items.
+type versionItem interface {
+	// compare orders items, nil standing for a missing item
+	compare(other versionItem) int
+	isNull() bool
+}
+
+type intItem struct {
+	value *big.Int
+}
+
+type stringItem struct {
+	value string
+}
+
+type listItem []versionItem
+
+func (i intItem) isNull() bool {
+	return i.value.Sign() == 0
+}
+
+func (i intItem) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		return i.value.Sign()
+	case intItem:
+		return i.value.Cmp(o.value)
+	default:
+		// 1.1 > 1-sp > 1-1
+		return 1
+	}
+}
+
+func newStringItem(value string, followedByDigit bool) stringItem {
+	if followedByDigit && len(value) == 1 {
+		// e.g. 1.0a1, 1.0-b2, 1.0m3
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, isAlias := qualifierAliases[value]; isAlias {
+		value = alias
+	}
+	return stringItem{value: value}
+}
+
+func (i stringItem) isNull() bool {
+	return comparableQualifier(i.value) == releaseQualifier
+}
+
+func (i stringItem) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		// 1-rc < 1, 1-sp > 1
+		return strings.Compare(comparableQualifier(i.value), releaseQualifier)
+	case stringItem:
+		return strings.Compare(comparableQualifier(i.value), comparableQualifier(o.value))
+	default:
+		return -1
+	}
+}
+
+// comparableQualifier sorts known qualifiers in their order, and unknown ones after them, lexically.
+func comparableQualifier(qualifier string) string {
+	for i, q := range qualifiers {
+		if q == qualifier {
+			return strconv.Itoa(i)
+		}
+	}
+	return strconv.Itoa(len(qualifiers)) + "-" + qualifier
+}
+
+func (l listItem) isNull() bool {
+	return len(l) == 0
+}
+
+func (l listItem) isPreRelease() bool {
+	for _, item := range l {
+		switch i := item.(type) {
+		case stringItem:
+			if i.compare(nil) < 0 || preReleaseQualifiers[i.value] {
+				return true
+			}
+		case listItem:
+			if i.isPreRelease() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (l listItem) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		for _, item := range l {
+			if result := item.compare(nil); result != 0 {
+				return result
+			}
+		}
+		return 0
+	case intItem:
+		return -1
+	case stringItem:
+		return 1
+	case listItem:
+		for i := 0; i < len(l) || i < len(o); i++ {
+			var left, right versionItem
+			if i < len(l) {
+				left = l[i]
+			}
+			if i < len(o) {
+				right = o[i]
+			}
+			var result int
+			if left == nil {
+				result = -right.compare(nil)
+			} else {
+				result = left.compare(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+// normalize removes the trailing null items of the list, like the `.0` of `1.0`.
+func (l listItem) normalize() listItem {
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i].isNull() {
+			l = append(l[:i], l[i+1:]...)
+		} else if _, isList := l[i].(listItem); !isList {
+			break
+		}
+	}
+	return l
+}
+
+// parseVersion splits a version on `.` and `-`, and between digits and letters, a `-` or such a transition starting a
+// sub-list of items.
+func parseVersion(version string) listItem {
+	version = strings.ToLower(version)
+
+	// lists are built from the innermost, as each one ends where the version does
+	lists := []listItem{{}}
+	add := func(item versionItem) {
+		lists[len(lists)-1] = append(lists[len(lists)-1], item)
+	}
+	isDigit := false
+	start := 0
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				add(intItem{value: big.NewInt(0)})
+			} else {
+				add(parseVersionItem(isDigit, version[start:i]))
+			}
+			start = i + 1
+			if c == '-' {
+				lists = append(lists, listItem{})
+			}
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				add(newStringItem(version[start:i], true))
+				start = i
+				lists = append(lists, listItem{})
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				add(parseVersionItem(true, version[start:i]))
+				start = i
+				lists = append(lists, listItem{})
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > start {
+		add(parseVersionItem(isDigit, version[start:]))
+	}
+
+	for i := len(lists) - 1; i > 0; i-- {
+		lists[i-1] = append(lists[i-1], lists[i].normalize())
+	}
+	return lists[0].normalize()
+}
+
+func parseVersionItem(isDigit bool, value string) versionItem {
+	if isDigit {
+		i, _ := new(big.Int).SetString(value, 10)
+		return intItem{value: i}
+	}
+	return newStringItem(value, false)
+}
@@ -0,0 +1,91 @@
+package maven
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// VersionRange is a Maven version range, like `[1.0,2.0)`, `(,3.2.2)` or a union of ranges like `(,1.0],[1.2,)`. A
+// plain version only matches itself, or any equivalent version.
+type VersionRange []versionInterval
+
+type versionInterval struct {
+	// empty bounds are unbounded
+	lower          string
+	upper          string
+	lowerInclusive bool
+	upperInclusive bool
+}
+
+func ParseVersionRange(spec string) (VersionRange, error) {
+	spec = strings.Replace(spec, " ", "", -1)
+	if spec == "" {
+		return nil, errors.New("invalid version range, empty range")
+	}
+	if !strings.HasPrefix(spec, "[") && !strings.HasPrefix(spec, "(") {
+		if strings.ContainsAny(spec, "[](),") {
+			return nil, errors.Errorf("invalid version range [%s]", spec)
+		}
+		return VersionRange{{lower: spec, upper: spec, lowerInclusive: true, upperInclusive: true}}, nil
+	}
+
+	r := VersionRange{}
+	for rest := spec; rest != ""; {
+		end := strings.IndexAny(rest, "])")
+		if end < 0 || (rest[0] != '[' && rest[0] != '(') {
+			return nil, errors.Errorf("invalid version range [%s]", spec)
+		}
+		interval := versionInterval{lowerInclusive: rest[0] == '[', upperInclusive: rest[end] == ']'}
+		bounds := strings.Split(rest[1:end], ",")
+		switch {
+		case len(bounds) == 1 && bounds[0] != "" && interval.lowerInclusive && interval.upperInclusive:
+			// [1.0] is exactly 1.0
+			interval.lower, interval.upper = bounds[0], bounds[0]
+		case len(bounds) == 2:
+			interval.lower, interval.upper = bounds[0], bounds[1]
+		default:
+			return nil, errors.Errorf("invalid version range [%s]", spec)
+		}
+		if interval.lower != "" && interval.upper != "" && CompareVersions(interval.lower, interval.upper) > 0 {
+			return nil, errors.Errorf("invalid version range [%s], lower bound is greater than upper bound", spec)
+		}
+		r = append(r, interval)
+
+		rest = rest[end+1:]
+		if strings.HasPrefix(rest, ",") {
+			rest = rest[1:]
+			if rest == "" {
+				return nil, errors.Errorf("invalid version range [%s]", spec)
+			}
+		} else if rest != "" {
+			return nil, errors.Errorf("invalid version range [%s]", spec)
+		}
+	}
+	return r, nil
+}
+
+// Contains tells whether a version is within any of the ranges.
+func (r VersionRange) Contains(version string) bool {
+	for _, interval := range r {
+		if interval.contains(version) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i versionInterval) contains(version string) bool {
+	if i.lower != "" {
+		result := CompareVersions(version, i.lower)
+		if result < 0 || (result == 0 && !i.lowerInclusive) {
+			return false
+		}
+	}
+	if i.upper != "" {
+		result := CompareVersions(version, i.upper)
+		if result > 0 || (result == 0 && !i.upperInclusive) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package maven_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
+)
+
+var _ = Describe("VersionRange", func() {
+	expectRange := func(spec string, contained []string, excluded []string) {
+		r, err := ParseVersionRange(spec)
+		Expect(err).ToNot(HaveOccurred())
+		for _, version := range contained {
+			Expect(r.Contains(version)).To(BeTrue(), "%s in %s", version, spec)
+		}
+		for _, version := range excluded {
+			Expect(r.Contains(version)).To(BeFalse(), "%s not in %s", version, spec)
+		}
+	}
+
+	It("should match versions between bounds", func() {
+		expectRange("[1.0,2.0)", []string{"1.0", "1", "1.5", "2.0-rc1"}, []string{"0.9", "2.0", "2.0.0"})
+		expectRange("(1.0,2.0]", []string{"1.0.1", "2"}, []string{"1.0", "2.0.1"})
+	})
+
+	It("should match versions up to or from unbounded ends", func() {
+		expectRange("(,3.2.2)", []string{"1.0", "3.2.1", "3.2.2-SNAPSHOT"}, []string{"3.2.2", "3.3"})
+		expectRange("[2.17.1,)", []string{"2.17.1", "3.0"}, []string{"2.17.0", "2.17.1-rc1"})
+	})
+
+	It("should match exact versions", func() {
+		expectRange("[1.2.17]", []string{"1.2.17", "1.2.17.0"}, []string{"1.2.16", "1.2.18"})
+		expectRange("1.2.17", []string{"1.2.17"}, []string{"1.2.16"})
+	})
+
+	It("should match unions of ranges", func() {
+		expectRange("(,1.0], [1.2,)", []string{"0.1", "1.0", "1.2", "5"}, []string{"1.1"})
+	})
+
+	It("should return meaningful errors for invalid ranges", func() {
+		for _, spec := range []string{"[1.0,2.0", "[1.0", "(1.0)", "[2.0,1.0]", "[1.0,2.0),", "1.0,2.0", "[1.0,2.0]x"} {
+			_, err := ParseVersionRange(spec)
+			Expect(err).To(HaveOccurred(), spec)
+			Expect(err.Error()).To(HavePrefix("invalid version range [" + spec))
+		}
+	})
+})
@@ -0,0 +1,50 @@
+package maven_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/maven"
+)
+
+var _ = Describe("CompareVersions", func() {
+	expectIncreasing := func(versions ...string) {
+		for i := range versions {
+			for j := range versions {
+				result := CompareVersions(versions[i], versions[j])
+				switch {
+				case i < j:
+					Expect(result).To(BeNumerically("<", 0), "%s < %s", versions[i], versions[j])
+				case i > j:
+					Expect(result).To(BeNumerically(">", 0), "%s > %s", versions[i], versions[j])
+				default:
+					Expect(result).To(BeZero(), "%s == %s", versions[i], versions[j])
+				}
+			}
+		}
+	}
+
+	It("should order qualifiers like Maven", func() {
+		expectIncreasing("1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11",
+			"1-rc", "1-cr2", "1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1",
+			"1-1-snapshot", "1-1", "1-2", "1-123")
+	})
+
+	It("should order numbers like Maven", func() {
+		expectIncreasing("2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1",
+			"2.1.0.1", "2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b",
+			"11c", "11m")
+	})
+
+	It("should consider equivalent versions equal", func() {
+		for _, version := range []string{"1.0", "1.0.0", "1-ga", "1.0-final", "1-RELEASE", "1.0.0-0.0"} {
+			Expect(CompareVersions("1", version)).To(BeZero(), version)
+		}
+		Expect(CompareVersions("1-cr1", "1-rc1")).To(BeZero())
+		Expect(CompareVersions("1a1", "1-alpha-1")).To(BeZero())
+	})
+
+	It("should compare numbers of any size", func() {
+		Expect(CompareVersions("20240101120000123456789", "20240101120000123456790")).To(BeNumerically("<", 0))
+	})
+})
@@ -0,0 +1,92 @@
+package pgp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+const armorStart = `-----BEGIN PGP `
+const armorEnd = `-----END PGP `
+
+// dearmor decodes every ASCII armored block of some contents, in order. Contents which aren't armored are returned as
+// they are, as keyrings exported without `--armor` are binary.
+func dearmor(contents []byte) ([][]byte, error) {
+	if !bytes.Contains(contents, []byte(armorStart)) {
+		return [][]byte{contents}, nil
+	}
+
+	blocks := make([][]byte, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	var body *strings.Builder
+	var checksum string
+	isHeader := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, armorStart):
+			body, checksum, isHeader = &strings.Builder{}, "", true
+		case body == nil:
+		case strings.HasPrefix(line, armorEnd):
+			block, err := decodeBlock(body.String(), checksum)
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, block)
+			body = nil
+		case isHeader:
+			// headers like `Version: GnuPG v2` end with a blank line
+			isHeader = line != "" && strings.Contains(line, ":")
+			if !isHeader && line != "" {
+				body.WriteString(line)
+			}
+		case strings.HasPrefix(line, "=") && len(line) == 5:
+			checksum = line[1:]
+		default:
+			body.WriteString(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if body != nil {
+		return nil, errors.New("error parsing armor : missing end line")
+	}
+	return blocks, nil
+}
+
+func decodeBlock(body string, checksum string) ([]byte, error) {
+	block, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing armor")
+	}
+	if checksum == "" {
+		return block, nil
+	}
+	expected, err := base64.StdEncoding.DecodeString(checksum)
+	if err != nil || len(expected) != 3 {
+		return nil, errors.New("error parsing armor : invalid checksum")
+	}
+	crc := crc24(block)
+	if byte(crc>>16) != expected[0] || byte(crc>>8) != expected[1] || byte(crc) != expected[2] {
+		return nil, errors.New("error parsing armor : checksum mismatch")
+	}
+	return block, nil
+}
+
+// crc24 is the checksum of armored blocks, as specified by RFC 4880 section 6.1.
+func crc24(data []byte) uint32 {
+	crc := uint32(0xb704ce)
+	for _, b := range data {
+		crc ^= uint32(b) << 16
+		for i := 0; i < 8; i++ {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= 0x1864cfb
+			}
+		}
+	}
+	return crc & 0xffffff
+}
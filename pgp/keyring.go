@@ -0,0 +1,263 @@
+package pgp
+
+import (
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+const rsaAlgorithm = 1
+const rsaSignOnlyAlgorithm = 3
+
+// Key is a public key of a keyring, either a primary key or one of its subkeys.
+type Key struct {
+	Fingerprint string
+	KeyID       uint64
+	// Primary is the fingerprint of the primary key a subkey belongs to, or of the key itself.
+	Primary string
+	created time.Time
+	// expires is when a subkey stops being valid, as its binding signature tells. It is zero if it never does.
+	expires   time.Time
+	algorithm byte
+	rsa       *rsa.PublicKey
+}
+
+// keyPacket is a key of a keyring along with the signatures following it, which are about the key itself.
+type keyPacket struct {
+	key        *Key
+	body       []byte
+	signatures []*signature
+}
+
+// transferableKey is a primary key and its subkeys, as specified by RFC 4880 section 11.1. The primary key is nil
+// when it was skipped.
+type transferableKey struct {
+	primary *keyPacket
+	subkeys []*keyPacket
+}
+
+// Keyring holds the public keys signatures are verified against, only trusting signatures made with some of them.
+type Keyring struct {
+	keys    []*Key
+	trusted map[string]bool
+}
+
+// LoadKeyring reads public keys exported with `gpg --export`, armored or not. Signatures are trusted when made by one
+// of the `trusted` keys or their subkeys, given as fingerprints or long key IDs, or by any key if none are given.
+// Subkeys are only used when their primary key bound them for signing, and didn't revoke them.
+func LoadKeyring(path string, trusted []string) (*Keyring, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read keyring [%s]", path)
+	}
+	k, err := ReadKeyring(contents, trusted)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing keyring [%s]", path)
+	}
+	return k, nil
+}
+
+func ReadKeyring(contents []byte, trusted []string) (*Keyring, error) {
+	blocks, err := dearmor(contents)
+	if err != nil {
+		return nil, err
+	}
+	k := &Keyring{}
+	for _, block := range blocks {
+		packets, err := readPackets(block)
+		if err != nil {
+			return nil, err
+		}
+		transferableKeys, err := readTransferableKeys(packets)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range transferableKeys {
+			k.keys = append(k.keys, t.validKeys()...)
+		}
+	}
+	if len(k.keys) == 0 {
+		return nil, errors.New("no public keys found")
+	}
+	if err := k.trust(trusted); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// readTransferableKeys groups the keys of a keyring with their subkeys and the signatures about them. Signatures
+// following user IDs certify those rather than keys, so they are left out.
+func readTransferableKeys(packets []*packet) ([]*transferableKey, error) {
+	transferableKeys := make([]*transferableKey, 0)
+	var current *keyPacket
+	for _, p := range packets {
+		switch p.tag {
+		case publicKeyTag, subkeyTag:
+			key, err := parseKey(p.body)
+			if err != nil {
+				return nil, err
+			}
+			current = nil
+			if p.tag == publicKeyTag {
+				t := &transferableKey{}
+				if key != nil {
+					current = &keyPacket{key: key, body: p.body}
+					t.primary = current
+				}
+				transferableKeys = append(transferableKeys, t)
+				continue
+			}
+			if len(transferableKeys) == 0 {
+				return nil, errors.New("subkey has no primary key")
+			}
+			// subkeys of skipped primary keys are skipped along with them
+			t := transferableKeys[len(transferableKeys)-1]
+			if key != nil && t.primary != nil {
+				current = &keyPacket{key: key, body: p.body}
+				t.subkeys = append(t.subkeys, current)
+			}
+		case signatureTag:
+			if current == nil {
+				continue
+			}
+			// keyrings hold signatures of all kinds, the unsupported ones can't bind or revoke anything
+			if s, err := parseSignature(p.body); err == nil {
+				current.signatures = append(current.signatures, s)
+			}
+		default:
+			current = nil
+		}
+	}
+	return transferableKeys, nil
+}
+
+// validKeys returns the primary key and the subkeys it bound for signing, unless they were revoked.
+func (t *transferableKey) validKeys() []*Key {
+	if t.primary == nil {
+		return nil
+	}
+	primary := t.primary.key
+	primary.Primary = primary.Fingerprint
+	if primary.latestSignature(t.primary.signatures, keyRevocationSignature, t.primary.hashed()) != nil {
+		return nil
+	}
+
+	keys := []*Key{primary}
+	for _, subkey := range t.subkeys {
+		signed := [][]byte{t.primary.hashed(), subkey.hashed()}
+		if primary.latestSignature(subkey.signatures, subkeyRevocation, signed...) != nil {
+			continue
+		}
+		binding := primary.latestSignature(subkey.signatures, subkeyBindingSignature, signed...)
+		if binding == nil || binding.keyFlags != nil && (len(binding.keyFlags) < 1 || binding.keyFlags[0]&signFlag == 0) {
+			continue
+		}
+		key := subkey.key
+		key.Primary = primary.Fingerprint
+		if binding.keyExpiration > 0 {
+			key.expires = key.created.Add(binding.keyExpiration)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// latestSignature returns the most recent signature of a type the key made over some keys, nil if there is none.
+func (key *Key) latestSignature(signatures []*signature, signatureType byte, signed ...[]byte) *signature {
+	var latest *signature
+	for _, s := range signatures {
+		if s.signatureType != signatureType || key.check(s, signed...) != nil {
+			continue
+		}
+		if latest == nil || s.created.After(latest.created) {
+			latest = s
+		}
+	}
+	return latest
+}
+
+// hashed is how a key is hashed by the signatures about it.
+func (p *keyPacket) hashed() []byte {
+	return append([]byte{0x99, byte(len(p.body) >> 8), byte(len(p.body))}, p.body...)
+}
+
+// trust resolves trusted key IDs to the fingerprints of primary keys. Short key IDs are refused, as they are easy to
+// forge.
+func (k *Keyring) trust(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	k.trusted = map[string]bool{}
+	for _, id := range ids {
+		normalized := strings.ToUpper(strings.TrimPrefix(strings.Replace(strings.TrimSpace(id), " ", "", -1), "0x"))
+		if len(normalized) != 16 && len(normalized) != 40 {
+			return errors.Errorf("invalid trusted key [%s], expected a fingerprint or long key ID", id)
+		}
+		found := false
+		for _, key := range k.keys {
+			if key.Primary == key.Fingerprint && strings.HasSuffix(key.Fingerprint, normalized) {
+				k.trusted[key.Fingerprint] = true
+				found = true
+			}
+		}
+		if !found {
+			return errors.Errorf("trusted key [%s] is not in the keyring", id)
+		}
+	}
+	return nil
+}
+
+func (k *Keyring) isTrusted(key *Key) bool {
+	return k.trusted == nil || k.trusted[key.Primary]
+}
+
+func (k *Keyring) find(keyID uint64, fingerprint string) *Key {
+	for _, key := range k.keys {
+		if fingerprint != "" && key.Fingerprint == fingerprint || fingerprint == "" && key.KeyID == keyID {
+			return key
+		}
+	}
+	return nil
+}
+
+// parseKey reads a version 4 public key packet, as specified by RFC 4880 section 5.5.2. Keys of other versions are
+// skipped, as they can't have made the signatures of modern releases.
+func parseKey(body []byte) (*Key, error) {
+	if len(body) < 6 {
+		return nil, errTruncated
+	}
+	if body[0] != 4 {
+		return nil, nil
+	}
+
+	digest := sha1.New()
+	digest.Write((&keyPacket{body: body}).hashed())
+	fingerprint := digest.Sum(nil)
+	key := &Key{
+		Fingerprint: strings.ToUpper(hex.EncodeToString(fingerprint)),
+		KeyID:       binary.BigEndian.Uint64(fingerprint[12:]),
+		created:     time.Unix(int64(binary.BigEndian.Uint32(body[1:])), 0),
+		algorithm:   body[5],
+	}
+
+	if key.algorithm == rsaAlgorithm || key.algorithm == rsaSignOnlyAlgorithm {
+		n, rest, err := readMPI(body[6:])
+		if err != nil {
+			return nil, err
+		}
+		e, _, err := readMPI(rest)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.Errorf("key [%s] has an unsupported RSA exponent", key.Fingerprint)
+		}
+		key.rsa = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	}
+	return key, nil
+}
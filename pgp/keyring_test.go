@@ -0,0 +1,333 @@
+package pgp_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"encoding/base64"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/pgp"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// generated with `gpg --quick-gen-key` and `gpg --armor --detach-sign`
+const trustedFingerprint = `49E5A150F3537E821FDBBDB3F99C2B1997B0A1F9`
+const trustedKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mI0EatWElQEEALPP0zTqCykQncchyhU60vfLN1IALThXtoS4fgfr6eCdA7mowV5Y
+WhY1b90kwn3mSBjv2lcjhc7CcqgzWKmPcfifF5ChmnUTi1llc2yYEJvhJNUUtFrU
+K8mHyEEgCsZjFwBBTkbf5+fyvTrYIot2Z7iys+yxAsK5rCzNAACJO1IDABEBAAG0
+JVRydXN0ZWQgUmVsZWFzZSA8dHJ1c3RlZEBleGFtcGxlLmNvbT6IzgQTAQoAOBYh
+BEnloVDzU36CH9u9s/mcKxmXsKH5BQJq1YSVAhsDBQsJCAcCBhUKCQgLAgQWAgMB
+Ah4BAheAAAoJEPmcKxmXsKH5HWwD/iZNwJNmfTeWvdAcRxVs5kCiqiirMOp/Kqav
+YcaOJzt8MkdppLvpZjgDtSInfPH89QkoWL/OdItDi4GR7DaNDH+8AzzYTAWUqgCr
+WCFQN4gK9wVxcAvNB8rXJ+Dx2TlNrGrVTKRfFS0MBj7I3P7SYBrFjfV5joiMdtmO
+lK+TPwNW
+=zV/z
+-----END PGP PUBLIC KEY BLOCK-----`
+const otherKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mI0EatWElQEEANNbOaTzTbjxHBu1p02Qjb/7JZBITk1C3vN9F5mVBytOVvYv8V8s
+I723PSOqh1bRACAwWDY47BdvW0X2NHqTj9qmaQQB9sMKMTWtWA9U028vr/kb9bEq
+kn4sh6T1HTn6lLwqCCluXQaLRGhHSQTkmeCJ+86wQDkyYSjh2/2A5o/TABEBAAG0
+IU90aGVyIFJlbGVhc2UgPG90aGVyQGV4YW1wbGUuY29tPojOBBMBCgA4FiEEkZXX
+Wl30Y21Hm0r3N+wb12LgIg0FAmrVhJUCGwMFCwkIBwIGFQoJCAsCBBYCAwECHgEC
+F4AACgkQN+wb12LgIg23jwP8Dj8ttoAde+Gihi7v2bDk28S9uLDp9YlArUuGcH7g
+WgNhBVZMc8SZVam61t19aWd6HvlAGi4XhnYdK0uNzg6F784mpUptAwtaPT8B25uG
+5uVbcjNGKVgGWpwYARi3oqBr1otdaBnE0wKiX9rtCVtSGvBUsrjXl+2GL+Icv92B
+dnI=
+=oZaG
+-----END PGP PUBLIC KEY BLOCK-----`
+const trustedSignature = `-----BEGIN PGP SIGNATURE-----
+
+iLMEAAEKAB0WIQRJ5aFQ81N+gh/bvbP5nCsZl7Ch+QUCatWEmQAKCRD5nCsZl7Ch
++ebJBACzyy35wojflbhxhqfzkJ/cQqHWyE9ayVonV4+B0skqmGkkukrPxw2wcfqP
+0G6eG7YO87ozJXcGc4LlIlcbPZRtoyM1xvJGnykEFEd4VDOmPibYOGiBM2bAsVXH
+OxfAKfZTh9J9Y076YOLeXw2co+YMN7TTIAuyUQAwN3kt1kAFWA==
+=K+M4
+-----END PGP SIGNATURE-----`
+const otherSignature = `-----BEGIN PGP SIGNATURE-----
+
+iLMEAAEKAB0WIQSRlddaXfRjbUebSvc37BvXYuAiDQUCatWEmQAKCRA37BvXYuAi
+DdXqBAC2PYN2KrBoOM0tzQ8XQuuM/5ZiRjNj/IhOymQgnXTPdV/ikvROscdWOvW8
+lzro+CgCr7dtMU6HPTjSwPoMGAgEa03WmoAShL0TrGMF2laTt8zRe3WREgpw+Vcb
+C26b0sr+7sUMjLYJv50pzohnN8E5Jy9QeiiAbpMtbCJpyjoDRw==
+=3t6W
+-----END PGP SIGNATURE-----`
+
+// a primary key certifying three signing subkeys, the second one revoked and the third one expired, each of which
+// signed data once the third one had expired
+const subkeysFingerprint = `F739C181460C2CB2A6765025BBB221B1974141E7`
+const subkeysKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mI0EXgvhAAEEAMNBMsp037BSgRrprG6mZfV2YAiYRY22y6aWEKq+liSvUzrAPvQf
+DRV2gdfk6dtVG4nruPXWBuqwWLqYkvl7+/ELwkdQH5BtWYcVcdpWtSBnfaaGIvcB
++uLfLA5rb58q4LQbbgLhcxvcyU9ooclpT4zWHl0alV8JiPLYlnXLJddRABEBAAG0
+I1N1YmtleSBSZWxlYXNlIDxzdWJrZXlAZXhhbXBsZS5jb20+iM4EEwEKADgWIQT3
+OcGBRgwssqZ2UCW7siGxl0FB5wUCXgvhAAIbAQULCQgHAgYVCgkICwIEFgIDAQIe
+AQIXgAAKCRC7siGxl0FB5y4kA/9hswv1tdWwTMuf97DI712Jji+FtOV3OeblthD2
+2pIAOsgT3qz32463DaG89PhEgPPJhg2Afcqbm4Ui4R4Ylj7N3pwbyboSRXeMzOtG
+OwhmgAyQRJJr00pRb8xMsd0kK965uub1No9EF8S8v5sVMrzcxecdnbRV/zYFtzNg
+ijDflbiNBF4L4QABBADJ5nfhDXhkNEefBMHgV5XYh1YLvuZ/F74Os244KWRHyw4C
+IaCYOMNUdrTNKL3i2FjGUBuk6fVBzBLamq8ceyQuDr2AUatQXEx8TWQmFF/5y0tV
+e2p/hbq0dzG4yo8GqhYox0F9d6/zt9Sjy7x4Xz94/9SSBwjfTvGA/ZECoFpSlwAR
+AQABiQFrBBgBCgAgFiEE9znBgUYMLLKmdlAlu7IhsZdBQecFAl4L4QACGwIAvwkQ
+u7IhsZdBQee0IAQZAQoAHRYhBA5/zvRE/ot0Sks7wZNUsy9ICYiUBQJeC+EAAAoJ
+EJNUsy9ICYiU8CQD/jwPujhbYfeGyPFwuCO72H2WrnupuvB3FPOj6Wxq5MjnAy+D
+UUROiruvVT5lXPgyykjxk51INnqUdHRMTIIJWngUG9efh+Gjy5JcuHRzVttm9GN8
+bRzBZOzsAvdwQN+fpCL3VyvqHhyabQEGkhBrthcXzILqIMKRfo1Ix5P2FTXEAM4E
+AJGBIfE78Xxj2zEeefnxQVS4Zt47dVLohvqd3LwGnza885b/92JpavXGp1KJhtnC
+y4gS8tARtuIb3Ukn7dHLrZ9Chnf484AFB+SR9PrCflrGnYkZmiEBp2wUKw8Hev4r
+9HVolcU7nSUr5lUMKc7xO4/+qYoviQivHrz6Cc2EJnbXuI0EXgvhAAEEAMPW4Bbe
+mSdIcnLLjrp54y76sTPGxZDF60vIKW8ETW0NqClz4XJSNdoPkThXDyqfnEzPUlZy
+mmjbYmdF3QJlAx+FeqZ1Zwtc41NfkZEKtVu2yfZY+sUVLFcOXsDZb/i3KG3BpVV4
+AY9WLg5qowB2YllY12Jb+2t6Mr3bGyDersyLABEBAAGItgQoAQoAIBYhBPc5wYFG
+DCyypnZQJbuyIbGXQUHnBQJeWvsAAh0AAAoJELuyIbGXQUHnfKID/iVHmnDF+TF0
+LOxKhTbZH+FbrZLtmaQBdaz4l2OVSHPZAoC95sE1ftMyYYEfMGce/nO0MDE6J4WB
+1fmx9jfkwdchcePZyVB+MG8fXy6V2KHpVbTb5iNBbH01eUC7RXseEAPogTGHCUfo
+875Cvjm11HqJOlnFa5uT8dEqKJ9DDiGjiQFrBBgBCgAgFiEE9znBgUYMLLKmdlAl
+u7IhsZdBQecFAl4L4QACGwIAvwkQu7IhsZdBQee0IAQZAQoAHRYhBNE7DXKb6oc4
+yLziVmYtoMWodzQiBQJeC+EAAAoJEGYtoMWodzQimJ8D/1XR6P2WXYIR+N/wCRd+
+UCadrBJq0EDjSJyrKmFgRM+LQRZX1+gv1vv/926plcVxiRpvNaeCKC5M9Hljq7Mq
+7hhC6BF9DlKvW1UqYkCMwqSdgvdrK3A/4Vq4y/ZT2hEA30wea17BKJAzyJYBEFB7
+uhh3UXOcgLUC5HNeE5RADMqTGnYD/1KLostH+gNRlWUdiikkjvobP7zfMz+yuMMd
+/M9B9DJLfwV84vGkjIOjaTPwmig6JHt13Wpoh3zXQ3wN5p91DCPcPuUq73LClEdu
+Jlk2kwgdM1jBXiEh753JTHqstTWtDDTKNVKPt69UIS6IszFeyYIDEPsYjlb+V+bo
+swNQbcovuI0EXgvhAAEEANtZi717LHyJGZLN6orrkAEM32GfgrNvSD6WoPkXc+Ol
+NuSKfTQh14AT/ULDg+e9J33hRjHpKsuZr1OaaKNENE4k5O06RU56iTnpPqdxQ1O/
+cRjdzShKBUA9sVnBxrlC/+J+rIH+UQplz25aRhJBn2nIC9W/UcBTr+AYEc63HwQd
+ABEBAAGJAXEEGAEKACYCGwIWIQT3OcGBRgwssqZ2UCW7siGxl0FB5wUCXjS/gAUJ
+ACowAAC/tCAEGQEKAB0WIQSv37G08DR3ZFPLa+X8O3hhtVYvnQUCXgvhAAAKCRD8
+O3hhtVYvnTJdBADLI7sUJKBQuOm5cr0nLGll0pbwS4dA70mtyBCqhbAS7eXg4Q0H
+uqRxlqRy/rOTFiRVONvXawMe+z0kvmZAp2F+8LhPWiG6HHtZUAEaZ8Q5TBbZHgQZ
+H/gcUgLJLWqnRavADrovPrR3e5qP8+89AY2FpICEBpyObe+cHR6hq2Kx6QkQu7Ih
+sZdBQecr/AQAuKBQ9XcNIMO/3cE4YWgG99j6U8nKufTzSbZFOe+d4qNHkzYIfzHe
+L+7mYNKQq5DqN4f/U4Ni4Z/OAnoPfret1EaNIooBCheGYrWiXXo2lmhoJCMqJqmh
+uPrwWSAB3PWGt2zO0yZB+/NdVHA1+IFrK+/ktBIbKBeEUtBAKcVjhIo=
+=islL
+-----END PGP PUBLIC KEY BLOCK-----`
+const subkeySignature = `-----BEGIN PGP SIGNATURE-----
+
+iLMEAAEKAB0WIQQOf870RP6LdEpLO8GTVLMvSAmIlAUCXtRFAAAKCRCTVLMvSAmI
+lEhDA/9FUgvHFkx3XdEjq+ExUF49ncbgJc7uuGm0YZwGYRul+cN4UVIMIfdlWFUs
+xFKHw8Lzx3GGCxJz8ccqzhpXvj4uxvpXkJplIW1SoF3Dc7TdR0LvaetU3zxf/eEH
+jaOUNT65FJFzsFuRwnQIk4vYVHZC2FruLaPOIYWqHVFvPF+nLA==
+=856i
+-----END PGP SIGNATURE-----`
+const revokedSubkeySignature = `-----BEGIN PGP SIGNATURE-----
+
+iLMEAAEKAB0WIQTROw1ym+qHOMi84lZmLaDFqHc0IgUCXtRFAAAKCRBmLaDFqHc0
+IlrTA/4yH9UCE0EfUzA4jlLTUazfpBwpmuqrTicnSaYf1MjNMphKvaZzkX5kkubk
+Q6iE2SIQh6glWYUGcfXxnDcqGWPNc4nlTlxKFtw14XLeRHWEaol2aRq/MZzHlvVI
+G9WvzaCCSBvMrq2QiononSX0Lg0TWJzB974K4EFyqimEG3zw+Q==
+=oa6y
+-----END PGP SIGNATURE-----`
+const expiredSubkeySignature = `-----BEGIN PGP SIGNATURE-----
+
+iLMEAAEKAB0WIQSv37G08DR3ZFPLa+X8O3hhtVYvnQUCXtRFAAAKCRD8O3hhtVYv
+ncC7BADDuJIICLldYInI5VP8KaggKq+ZNIR6BBMbjYoITQSB4DG+dNju+bCoXDXE
+0BILIyg/BLtke2RE/6k0IyNtHaD8WXXjB1aDpw6LCetv3/cMxr6af7nIMaFgcw0T
+ftEuWkiUdHvEy7f65EiWy8ABgGFcVqHOnRmVbOMzC1aLlZ0alw==
+=8by3
+-----END PGP SIGNATURE-----`
+
+// the primary key above along with its first subkey, bound by the signature of the second one
+const forgedBindingKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mI0EXgvhAAEEAMNBMsp037BSgRrprG6mZfV2YAiYRY22y6aWEKq+liSvUzrAPvQf
+DRV2gdfk6dtVG4nruPXWBuqwWLqYkvl7+/ELwkdQH5BtWYcVcdpWtSBnfaaGIvcB
++uLfLA5rb58q4LQbbgLhcxvcyU9ooclpT4zWHl0alV8JiPLYlnXLJddRABEBAAG0
+I1N1YmtleSBSZWxlYXNlIDxzdWJrZXlAZXhhbXBsZS5jb20+iM4EEwEKADgWIQT3
+OcGBRgwssqZ2UCW7siGxl0FB5wUCXgvhAAIbAQULCQgHAgYVCgkICwIEFgIDAQIe
+AQIXgAAKCRC7siGxl0FB5y4kA/9hswv1tdWwTMuf97DI712Jji+FtOV3OeblthD2
+2pIAOsgT3qz32463DaG89PhEgPPJhg2Afcqbm4Ui4R4Ylj7N3pwbyboSRXeMzOtG
+OwhmgAyQRJJr00pRb8xMsd0kK965uub1No9EF8S8v5sVMrzcxecdnbRV/zYFtzNg
+ijDflbiNBF4L4QABBADJ5nfhDXhkNEefBMHgV5XYh1YLvuZ/F74Os244KWRHyw4C
+IaCYOMNUdrTNKL3i2FjGUBuk6fVBzBLamq8ceyQuDr2AUatQXEx8TWQmFF/5y0tV
+e2p/hbq0dzG4yo8GqhYox0F9d6/zt9Sjy7x4Xz94/9SSBwjfTvGA/ZECoFpSlwAR
+AQABiQFrBBgBCgAgFiEE9znBgUYMLLKmdlAlu7IhsZdBQecFAl4L4QACGwIAvwkQ
+u7IhsZdBQee0IAQZAQoAHRYhBNE7DXKb6oc4yLziVmYtoMWodzQiBQJeC+EAAAoJ
+EGYtoMWodzQimJ8D/1XR6P2WXYIR+N/wCRd+UCadrBJq0EDjSJyrKmFgRM+LQRZX
+1+gv1vv/926plcVxiRpvNaeCKC5M9Hljq7Mq7hhC6BF9DlKvW1UqYkCMwqSdgvdr
+K3A/4Vq4y/ZT2hEA30wea17BKJAzyJYBEFB7uhh3UXOcgLUC5HNeE5RADMqTGnYD
+/1KLostH+gNRlWUdiikkjvobP7zfMz+yuMMd/M9B9DJLfwV84vGkjIOjaTPwmig6
+JHt13Wpoh3zXQ3wN5p91DCPcPuUq73LClEduJlk2kwgdM1jBXiEh753JTHqstTWt
+DDTKNVKPt69UIS6IszFeyYIDEPsYjlb+V+boswNQbcov
+=0Flu
+-----END PGP PUBLIC KEY BLOCK-----`
+
+// the primary key above followed by a version 3 key, along with the first subkey and its binding signature
+const skippedPrimaryKey = `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+mI0EXgvhAAEEAMNBMsp037BSgRrprG6mZfV2YAiYRY22y6aWEKq+liSvUzrAPvQf
+DRV2gdfk6dtVG4nruPXWBuqwWLqYkvl7+/ELwkdQH5BtWYcVcdpWtSBnfaaGIvcB
++uLfLA5rb58q4LQbbgLhcxvcyU9ooclpT4zWHl0alV8JiPLYlnXLJddRABEBAAG0
+I1N1YmtleSBSZWxlYXNlIDxzdWJrZXlAZXhhbXBsZS5jb20+iM4EEwEKADgWIQT3
+OcGBRgwssqZ2UCW7siGxl0FB5wUCXgvhAAIbAQULCQgHAgYVCgkICwIEFgIDAQIe
+AQIXgAAKCRC7siGxl0FB5y4kA/9hswv1tdWwTMuf97DI712Jji+FtOV3OeblthD2
+2pIAOsgT3qz32463DaG89PhEgPPJhg2Afcqbm4Ui4R4Ylj7N3pwbyboSRXeMzOtG
+OwhmgAyQRJJr00pRb8xMsd0kK965uub1No9EF8S8v5sVMrzcxecdnbRV/zYFtzNg
+ijDflZgIAwAAAAAAAAG4jQReC+EAAQQAyeZ34Q14ZDRHnwTB4FeV2IdWC77mfxe+
+DrNuOClkR8sOAiGgmDjDVHa0zSi94thYxlAbpOn1QcwS2pqvHHskLg69gFGrUFxM
+fE1kJhRf+ctLVXtqf4W6tHcxuMqPBqoWKMdBfXev87fUo8u8eF8/eP/UkgcI307x
+gP2RAqBaUpcAEQEAAYkBawQYAQoAIBYhBPc5wYFGDCyypnZQJbuyIbGXQUHnBQJe
+C+EAAhsCAL8JELuyIbGXQUHntCAEGQEKAB0WIQQOf870RP6LdEpLO8GTVLMvSAmI
+lAUCXgvhAAAKCRCTVLMvSAmIlPAkA/48D7o4W2H3hsjxcLgju9h9lq57qbrwdxTz
+o+lsauTI5wMvg1FEToq7r1U+ZVz4MspI8ZOdSDZ6lHR0TEyCCVp4FBvXn4fho8uS
+XLh0c1bbZvRjfG0cwWTs7AL3cEDfn6Qi91cr6h4cmm0BBpIQa7YXF8yC6iDCkX6N
+SMeT9hU1xADOBACRgSHxO/F8Y9sxHnn58UFUuGbeO3VS6Ib6ndy8Bp82vPOW//di
+aWr1xqdSiYbZwsuIEvLQEbbiG91JJ+3Ry62fQoZ3+POABQfkkfT6wn5axp2JGZoh
+AadsFCsPB3r+K/R1aJXFO50lK+ZVDCnO8TuP/qmKL4kIrx68+gnNhCZ21w==
+=wh+p
+-----END PGP PUBLIC KEY BLOCK-----`
+
+var data = []byte("not really a JAR")
+
+var _ = Describe("Keyring", func() {
+	var (
+		err     error
+		keyring *Keyring
+		key     *Key
+		keys    string
+		trusted []string
+	)
+
+	BeforeEach(func() {
+		keys = trustedKey + "\n" + otherKey
+		trusted = nil
+	})
+
+	JustBeforeEach(func() {
+		keyring, err = ReadKeyring([]byte(keys), trusted)
+	})
+
+	It("should read every key", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		key, err = keyring.Verify(data, []byte(trustedSignature))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(key.Fingerprint).To(Equal(trustedFingerprint))
+
+		key, err = keyring.Verify(data, []byte(otherSignature))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(key.Fingerprint).To(Equal("9195D75A5DF4636D479B4AF737EC1BD762E0220D"))
+	})
+
+	It("should refuse signatures of other data", func() {
+		_, err = keyring.Verify([]byte("tampered"), []byte(trustedSignature))
+		Expect(errors.Cause(err)).To(Equal(ErrBadSignature))
+	})
+
+	Context("given binary keys", func() {
+		BeforeEach(func() {
+			lines := strings.Split(trustedKey, "\n")
+			bs, err := base64.StdEncoding.DecodeString(strings.Join(lines[2:len(lines)-2], ""))
+			Expect(err).ToNot(HaveOccurred())
+			keys = string(bs)
+		})
+
+		It("should read them", func() {
+			Expect(err).ToNot(HaveOccurred())
+			key, err = keyring.Verify(data, []byte(trustedSignature))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.Fingerprint).To(Equal(trustedFingerprint))
+		})
+	})
+
+	Context("given signatures made with keys missing from the keyring", func() {
+		BeforeEach(func() {
+			keys = trustedKey
+		})
+
+		It("should refuse them", func() {
+			_, err = keyring.Verify(data, []byte(otherSignature))
+			Expect(errors.Cause(err)).To(Equal(ErrUnknownKey))
+			Expect(err.Error()).To(HavePrefix("key [9195D75A5DF4636D479B4AF737EC1BD762E0220D]"))
+		})
+	})
+
+	Context("given subkeys", func() {
+		BeforeEach(func() {
+			keys = subkeysKey
+			trusted = []string{subkeysFingerprint}
+		})
+
+		It("should accept signatures of the subkeys bound for signing", func() {
+			Expect(err).ToNot(HaveOccurred())
+			key, err = keyring.Verify(data, []byte(subkeySignature))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.Fingerprint).To(Equal("0E7FCEF444FE8B744A4B3BC19354B32F48098894"))
+			Expect(key.Primary).To(Equal(subkeysFingerprint))
+		})
+
+		It("should refuse signatures of revoked subkeys", func() {
+			_, err = keyring.Verify(data, []byte(revokedSubkeySignature))
+			Expect(errors.Cause(err)).To(Equal(ErrUnknownKey))
+		})
+
+		It("should refuse signatures made once subkeys expired", func() {
+			_, err = keyring.Verify(data, []byte(expiredSubkeySignature))
+			Expect(errors.Cause(err)).To(Equal(ErrExpiredKey))
+		})
+
+		Context("which are bound by a signature of another key", func() {
+			BeforeEach(func() {
+				keys = forgedBindingKey
+			})
+
+			It("should not trust them", func() {
+				Expect(err).ToNot(HaveOccurred())
+				_, err = keyring.Verify(data, []byte(subkeySignature))
+				Expect(errors.Cause(err)).To(Equal(ErrUnknownKey))
+			})
+		})
+
+		Context("which follow a skipped primary key", func() {
+			BeforeEach(func() {
+				keys = skippedPrimaryKey
+			})
+
+			It("should not attach them to the previous primary key", func() {
+				Expect(err).ToNot(HaveOccurred())
+				_, err = keyring.Verify(data, []byte(subkeySignature))
+				Expect(errors.Cause(err)).To(Equal(ErrUnknownKey))
+			})
+		})
+	})
+
+	Context("given trusted keys", func() {
+		BeforeEach(func() {
+			trusted = []string{"0xF99C2B1997B0A1F9"}
+		})
+
+		It("should only accept their signatures", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = keyring.Verify(data, []byte(trustedSignature))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = keyring.Verify(data, []byte(otherSignature))
+			Expect(errors.Cause(err)).To(Equal(ErrUntrustedKey))
+		})
+
+		Context("which are not in the keyring", func() {
+			BeforeEach(func() {
+				trusted = []string{"0000 0000 0000 0000 0000  0000 0000 0000 0000 0000"}
+			})
+
+			It("should return a meaningful error", func() {
+				Expect(err).To(MatchError("trusted key [0000 0000 0000 0000 0000  0000 0000 0000 0000 0000] is not in the keyring"))
+			})
+		})
+
+		Context("given as short key IDs", func() {
+			BeforeEach(func() {
+				trusted = []string{"97B0A1F9"}
+			})
+
+			It("should refuse them", func() {
+				Expect(err).To(MatchError("invalid trusted key [97B0A1F9], expected a fingerprint or long key ID"))
+			})
+		})
+	})
+})
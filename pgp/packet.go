@@ -0,0 +1,121 @@
+package pgp
+
+import (
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+const (
+	signatureTag = 2
+	publicKeyTag = 6
+	subkeyTag    = 14
+)
+
+type packet struct {
+	tag  int
+	body []byte
+}
+
+// readPackets splits contents into OpenPGP packets, as specified by RFC 4880 section 4.2.
+func readPackets(contents []byte) ([]*packet, error) {
+	packets := make([]*packet, 0)
+	for len(contents) > 0 {
+		header := contents[0]
+		if header&0x80 == 0 {
+			return nil, errors.New("error parsing packet : invalid header")
+		}
+
+		var p *packet
+		var rest []byte
+		var err error
+		if header&0x40 != 0 {
+			p, rest, err = readNewFormat(int(header&0x3f), contents[1:])
+		} else {
+			p, rest, err = readOldFormat(int(header>>2)&0xf, header&0x3, contents[1:])
+		}
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, p)
+		contents = rest
+	}
+	return packets, nil
+}
+
+func readOldFormat(tag int, lengthType byte, contents []byte) (*packet, []byte, error) {
+	var length int
+	switch lengthType {
+	case 0:
+		if len(contents) < 1 {
+			return nil, nil, errTruncated
+		}
+		length, contents = int(contents[0]), contents[1:]
+	case 1:
+		if len(contents) < 2 {
+			return nil, nil, errTruncated
+		}
+		length, contents = int(binary.BigEndian.Uint16(contents)), contents[2:]
+	case 2:
+		if len(contents) < 4 {
+			return nil, nil, errTruncated
+		}
+		length, contents = int(binary.BigEndian.Uint32(contents)), contents[4:]
+	default:
+		// indeterminate length packets extend to the end of the contents
+		length = len(contents)
+	}
+	if length < 0 || length > len(contents) {
+		return nil, nil, errTruncated
+	}
+	return &packet{tag: tag, body: contents[:length]}, contents[length:], nil
+}
+
+func readNewFormat(tag int, contents []byte) (*packet, []byte, error) {
+	body := make([]byte, 0)
+	for {
+		if len(contents) < 1 {
+			return nil, nil, errTruncated
+		}
+		var length int
+		isPartial := false
+		switch first := int(contents[0]); {
+		case first < 192:
+			length, contents = first, contents[1:]
+		case first < 224:
+			if len(contents) < 2 {
+				return nil, nil, errTruncated
+			}
+			length, contents = (first-192)<<8+int(contents[1])+192, contents[2:]
+		case first < 255:
+			length, contents, isPartial = 1<<uint(first&0x1f), contents[1:], true
+		default:
+			if len(contents) < 5 {
+				return nil, nil, errTruncated
+			}
+			length, contents = int(binary.BigEndian.Uint32(contents[1:])), contents[5:]
+		}
+		if length < 0 || length > len(contents) {
+			return nil, nil, errTruncated
+		}
+		body, contents = append(body, contents[:length]...), contents[length:]
+		if !isPartial {
+			return &packet{tag: tag, body: body}, contents, nil
+		}
+	}
+}
+
+var errTruncated = errors.New("error parsing packet : truncated")
+
+// readMPI reads a multiprecision integer, a bit count followed by the big-endian bytes of the integer.
+func readMPI(contents []byte) (*big.Int, []byte, error) {
+	if len(contents) < 2 {
+		return nil, nil, errTruncated
+	}
+	length := (int(binary.BigEndian.Uint16(contents)) + 7) / 8
+	contents = contents[2:]
+	if length > len(contents) {
+		return nil, nil, errTruncated
+	}
+	return new(big.Int).SetBytes(contents[:length]), contents[length:], nil
+}
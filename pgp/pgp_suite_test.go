@@ -0,0 +1,18 @@
+package pgp_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+)
+
+func TestPGP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PGP Suite")
+}
+
+var _ = BeforeSuite(func() {
+	format.TruncatedDiff = false
+})
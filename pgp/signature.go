@@ -0,0 +1,257 @@
+package pgp
+
+import (
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/pkg/errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// types of the signatures read, as specified by RFC 4880 section 5.2.1.
+const (
+	binarySignature        = 0x00
+	subkeyBindingSignature = 0x18
+	keyRevocationSignature = 0x20
+	subkeyRevocation       = 0x28
+)
+
+const (
+	creationTimeSubpacket      = 2
+	keyExpirationSubpacket     = 9
+	issuerSubpacket            = 16
+	keyFlagsSubpacket          = 27
+	issuerFingerprintSubpacket = 33
+)
+
+// signFlag is the key flag allowing a key to sign data.
+const signFlag = 0x02
+
+var hashes = map[byte]crypto.Hash{
+	2:  crypto.SHA1,
+	8:  crypto.SHA256,
+	9:  crypto.SHA384,
+	10: crypto.SHA512,
+	11: crypto.SHA224,
+}
+
+// Reasons signatures fail to verify.
+var (
+	ErrUnknownKey   = errors.New("signed by a key missing from the keyring")
+	ErrUntrustedKey = errors.New("signed by an untrusted key")
+	ErrBadSignature = errors.New("invalid signature")
+	ErrExpiredKey   = errors.New("signed by an expired key")
+)
+
+type signature struct {
+	signatureType byte
+	algorithm     byte
+	hash          crypto.Hash
+	// hashed is the part of the signature packet covered by the signature itself.
+	hashed      []byte
+	issuer      uint64
+	fingerprint string
+	created     time.Time
+	// keyExpiration is how long after its creation the signed key expires, 0 if it never does.
+	keyExpiration time.Duration
+	// keyFlags are nil if the signature doesn't restrict what the signed key may be used for.
+	keyFlags   []byte
+	hashPrefix []byte
+	value      *big.Int
+}
+
+// Verify checks a detached signature of some data, like a `.asc` file published next to an artifact, returning the
+// key which made it. Only version 4 RSA signatures of binary documents are supported, which is what `gpg
+// --detach-sign` produces for RSA keys.
+func (k *Keyring) Verify(data []byte, detached []byte) (*Key, error) {
+	blocks, err := dearmor(detached)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error = errors.New("no signature found")
+	for _, block := range blocks {
+		packets, err := readPackets(block)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range packets {
+			if p.tag != signatureTag {
+				continue
+			}
+			// several signatures may be published together, one valid trusted signature is enough
+			key, err := k.verify(data, p.body)
+			if err == nil {
+				return key, nil
+			}
+			lastErr = err
+		}
+	}
+	return nil, lastErr
+}
+
+func (k *Keyring) verify(data []byte, body []byte) (*Key, error) {
+	s, err := parseSignature(body)
+	if err != nil {
+		return nil, err
+	}
+	if s.signatureType != binarySignature {
+		return nil, errors.Errorf("unsupported signature type [%d]", s.signatureType)
+	}
+	key := k.find(s.issuer, s.fingerprint)
+	if key == nil {
+		issuer := s.fingerprint
+		if issuer == "" {
+			issuer = strings.ToUpper(hex.EncodeToString(uint64Bytes(s.issuer)))
+		}
+		return nil, errors.Wrapf(ErrUnknownKey, "key [%s]", issuer)
+	}
+	if !k.isTrusted(key) {
+		return nil, errors.Wrapf(ErrUntrustedKey, "key [%s]", key.Fingerprint)
+	}
+	if err := key.check(s, data); err != nil {
+		return nil, err
+	}
+	if !key.expires.IsZero() && s.created.After(key.expires) {
+		return nil, errors.Wrapf(ErrExpiredKey, "key [%s]", key.Fingerprint)
+	}
+	return key, nil
+}
+
+// check verifies a signature made by the key over some data, the signed parts being hashed in order, as specified by
+// RFC 4880 section 5.2.4.
+func (key *Key) check(s *signature, signed ...[]byte) error {
+	if key.rsa == nil || s.algorithm != rsaAlgorithm && s.algorithm != rsaSignOnlyAlgorithm {
+		return errors.Errorf("unsupported public key algorithm [%d] of key [%s]", key.algorithm, key.Fingerprint)
+	}
+
+	digest := s.hash.New()
+	for _, part := range signed {
+		digest.Write(part)
+	}
+	digest.Write(s.hashed)
+	trailer := make([]byte, 6)
+	trailer[0], trailer[1] = 4, 0xff
+	binary.BigEndian.PutUint32(trailer[2:], uint32(len(s.hashed)))
+	digest.Write(trailer)
+	sum := digest.Sum(nil)
+	if sum[0] != s.hashPrefix[0] || sum[1] != s.hashPrefix[1] {
+		return errors.Wrapf(ErrBadSignature, "key [%s]", key.Fingerprint)
+	}
+
+	// signatures with leading zeros are shorter than the modulus
+	value := s.value.Bytes()
+	padded := make([]byte, (key.rsa.N.BitLen()+7)/8)
+	if len(value) > len(padded) {
+		return errors.Wrapf(ErrBadSignature, "key [%s]", key.Fingerprint)
+	}
+	copy(padded[len(padded)-len(value):], value)
+	if err := rsa.VerifyPKCS1v15(key.rsa, s.hash, sum, padded); err != nil {
+		return errors.Wrapf(ErrBadSignature, "key [%s]", key.Fingerprint)
+	}
+	return nil
+}
+
+// parseSignature reads a version 4 signature packet, as specified by RFC 4880 section 5.2.3.
+func parseSignature(body []byte) (*signature, error) {
+	if len(body) < 6 {
+		return nil, errTruncated
+	}
+	if body[0] != 4 {
+		return nil, errors.Errorf("unsupported signature version [%d]", body[0])
+	}
+	s := &signature{signatureType: body[1], algorithm: body[2]}
+	hash, isSupported := hashes[body[3]]
+	if !isSupported || !hash.Available() {
+		return nil, errors.Errorf("unsupported hash algorithm [%d]", body[3])
+	}
+	s.hash = hash
+
+	hashedLength := int(binary.BigEndian.Uint16(body[4:]))
+	if 6+hashedLength+2 > len(body) {
+		return nil, errTruncated
+	}
+	s.hashed = body[:6+hashedLength]
+	if err := s.readSubpackets(body[6:6+hashedLength], true); err != nil {
+		return nil, err
+	}
+	rest := body[6+hashedLength:]
+	unhashedLength := int(binary.BigEndian.Uint16(rest))
+	if 2+unhashedLength+2 > len(rest) {
+		return nil, errTruncated
+	}
+	// the issuer is usually left out of the hashed subpackets, which is fine as a wrong one fails verification
+	if err := s.readSubpackets(rest[2:2+unhashedLength], false); err != nil {
+		return nil, err
+	}
+	rest = rest[2+unhashedLength:]
+	s.hashPrefix = rest[:2]
+
+	value, _, err := readMPI(rest[2:])
+	if err != nil {
+		return nil, err
+	}
+	s.value = value
+	return s, nil
+}
+
+// readSubpackets reads what subpackets tell about the signature. Anyone can change unhashed subpackets, so only the
+// issuer is read from them.
+func (s *signature) readSubpackets(contents []byte, isHashed bool) error {
+	for len(contents) > 0 {
+		var length int
+		switch first := int(contents[0]); {
+		case first < 192:
+			length, contents = first, contents[1:]
+		case first < 255:
+			if len(contents) < 2 {
+				return errTruncated
+			}
+			length, contents = (first-192)<<8+int(contents[1])+192, contents[2:]
+		default:
+			if len(contents) < 5 {
+				return errTruncated
+			}
+			length, contents = int(binary.BigEndian.Uint32(contents[1:])), contents[5:]
+		}
+		if length < 1 || length > len(contents) {
+			return errTruncated
+		}
+		subpacket := contents[1:length]
+		switch contents[0] & 0x7f {
+		case issuerSubpacket:
+			if len(subpacket) == 8 {
+				s.issuer = binary.BigEndian.Uint64(subpacket)
+			}
+		case issuerFingerprintSubpacket:
+			if len(subpacket) == 21 && subpacket[0] == 4 {
+				s.fingerprint = strings.ToUpper(hex.EncodeToString(subpacket[1:]))
+			}
+		case creationTimeSubpacket:
+			if isHashed && len(subpacket) == 4 {
+				s.created = time.Unix(int64(binary.BigEndian.Uint32(subpacket)), 0)
+			}
+		case keyExpirationSubpacket:
+			if isHashed && len(subpacket) == 4 {
+				s.keyExpiration = time.Duration(binary.BigEndian.Uint32(subpacket)) * time.Second
+			}
+		case keyFlagsSubpacket:
+			if isHashed {
+				s.keyFlags = append([]byte{}, subpacket...)
+			}
+		}
+		contents = contents[length:]
+	}
+	return nil
+}
+
+func uint64Bytes(value uint64) []byte {
+	bs := make([]byte, 8)
+	binary.BigEndian.PutUint64(bs, value)
+	return bs
+}
@@ -0,0 +1,185 @@
+package policy
+
+import (
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// Policy bans artifacts from resolved dependency graphs, read from a YAML or JSON file like:
+//
+//	rules:
+//	  - deny: log4j:log4j
+//	    versions: "[1.0,2.0)"
+//	    reason: log4j 1.x is no longer maintained
+//	  - deny: commons-collections:commons-collections
+//	    versions: "(,3.2.2)"
+//	  - deny: org.codehaus.jackson:*
+//	  - allow: org.codehaus.jackson:jackson-annotations
+//
+// Artifacts are given as `groupId:artifactId` patterns, where `*` matches any characters, and versions as Maven
+// version ranges, matching any version when left out. Allow rules are exceptions to deny rules, whatever their order.
+type Policy struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+type Rule struct {
+	Deny     string `yaml:"deny,omitempty"`
+	Allow    string `yaml:"allow,omitempty"`
+	Versions string `yaml:"versions,omitempty"`
+	Reason   string `yaml:"reason,omitempty"`
+	pattern  []string
+	versions maven.VersionRange
+}
+
+// Load reads a policy file, YAML being a superset of JSON.
+func Load(file string) (*Policy, error) {
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read policy [%s]", file)
+	}
+	p := &Policy{}
+	if err := yaml.UnmarshalStrict(contents, p); err != nil {
+		return nil, errors.Errorf("error parsing policy [%s] : %s", file, err)
+	}
+	for i, rule := range p.Rules {
+		if err := rule.parse(); err != nil {
+			return nil, errors.Errorf("error parsing policy [%s] : rule %d %s", file, i+1, err)
+		}
+	}
+	return p, nil
+}
+
+func (r *Rule) parse() error {
+	if (r.Deny == "") == (r.Allow == "") {
+		return errors.New("expected either deny or allow")
+	}
+	r.pattern = strings.Split(r.artifacts(), ":")
+	if len(r.pattern) != 2 {
+		return errors.Errorf("has invalid artifacts [%s], expected groupId:artifactId", r.artifacts())
+	}
+	for _, segment := range r.pattern {
+		if _, err := path.Match(segment, ""); err != nil {
+			return errors.Errorf("has invalid artifacts [%s] : %s", r.artifacts(), err)
+		}
+	}
+	if r.Versions != "" {
+		versions, err := maven.ParseVersionRange(r.Versions)
+		if err != nil {
+			return errors.Errorf("has %s", err)
+		}
+		r.versions = versions
+	}
+	return nil
+}
+
+func (r *Rule) artifacts() string {
+	if r.Deny != "" {
+		return r.Deny
+	}
+	return r.Allow
+}
+
+func (r *Rule) matches(artifact *maven.Artifact) bool {
+	for i, value := range []string{artifact.GroupID, artifact.ArtifactID} {
+		if matched, _ := path.Match(r.pattern[i], value); !matched {
+			return false
+		}
+	}
+	return r.versions == nil || r.versions.Contains(artifact.Version)
+}
+
+func (r *Rule) String() string {
+	if r.Versions == "" {
+		return r.artifacts()
+	}
+	return r.artifacts() + " " + r.Versions
+}
+
+// Violation is an artifact of the graph denied by a rule, along with every path it was resolved through, shortest
+// first.
+type Violation struct {
+	Artifact *maven.Artifact
+	Rule     *Rule
+	Paths    [][]*maven.Artifact
+}
+
+// ViolationsError reports every artifact of a graph violating the policy.
+type ViolationsError struct {
+	Violations []*Violation
+}
+
+func (e *ViolationsError) Error() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%d artifact(s) violate the dependency policy :", len(e.Violations))
+	for _, v := range e.Violations {
+		fmt.Fprintf(b, "\n  %s denied by [%s]", v.Artifact.GetMavenCoords(), v.Rule)
+		if v.Rule.Reason != "" {
+			fmt.Fprintf(b, " : %s", v.Rule.Reason)
+		}
+		for _, path := range v.Paths {
+			coords := make([]string, len(path))
+			for i, a := range path {
+				coords[i] = a.GetMavenCoords()
+			}
+			fmt.Fprintf(b, "\n    %s", strings.Join(coords, " > "))
+		}
+	}
+	return b.String()
+}
+
+// Evaluate checks every artifact of a resolved graph, returning a ViolationsError if any is denied.
+func (p *Policy) Evaluate(root *maven.Node) error {
+	violations := make([]*Violation, 0)
+	root.Walk(func(node *maven.Node, _ int) bool {
+		if node.IsOmitted() {
+			return false
+		}
+		if rule := p.denies(node.Artifact); rule != nil {
+			violations = append(violations, &Violation{Artifact: node.Artifact, Rule: rule,
+				Paths: resolvedPaths(root, node)})
+		}
+		return true
+	})
+
+	if len(violations) > 0 {
+		return &ViolationsError{Violations: violations}
+	}
+	return nil
+}
+
+// resolvedPaths lists the artifacts of every path a node was resolved through, the dependencies omitted in its favor
+// ending with the artifact resolved instead.
+func resolvedPaths(root *maven.Node, node *maven.Node) [][]*maven.Artifact {
+	paths := make([][]*maven.Artifact, 0)
+	for _, path := range root.ResolvedPathsTo(node) {
+		artifacts := make([]*maven.Artifact, len(path))
+		for i, n := range path {
+			artifacts[i] = n.Artifact
+		}
+		artifacts[len(path)-1] = node.Artifact
+		paths = append(paths, artifacts)
+	}
+	return paths
+}
+
+// denies finds the first deny rule matching an artifact, unless an allow rule matches it too.
+func (p *Policy) denies(artifact *maven.Artifact) *Rule {
+	var denied *Rule
+	for _, rule := range p.Rules {
+		if !rule.matches(artifact) {
+			continue
+		}
+		if rule.Allow != "" {
+			return nil
+		}
+		if denied == nil {
+			denied = rule
+		}
+	}
+	return denied
+}
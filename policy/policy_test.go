@@ -0,0 +1,110 @@
+package policy_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/policy"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("Policy", func() {
+	var (
+		dir   string
+		root  *maven.Node
+		log4j *maven.Node
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "policy_test")
+		Expect(err).ToNot(HaveOccurred())
+
+		node := func(groupID string, artifactID string, version string, children ...*maven.Node) *maven.Node {
+			return &maven.Node{Artifact: &maven.Artifact{GroupID: groupID, ArtifactID: artifactID, Version: version},
+				Children: children}
+		}
+		log4j = node("log4j", "log4j", "1.2.17")
+		root = node("org.fake", "root", "1",
+			node("org.fake", "a", "1",
+				log4j,
+				node("commons-collections", "commons-collections", "3.2.2"),
+			),
+			node("org.codehaus.jackson", "jackson-core-asl", "1.9.13"),
+			node("org.codehaus.jackson", "jackson-annotations", "1.9.13"),
+			node("org.fake", "b", "1",
+				&maven.Node{Artifact: &maven.Artifact{GroupID: "log4j", ArtifactID: "log4j", Version: "1.2.16"},
+					Omitted: maven.OmittedConflict, Winner: log4j},
+				&maven.Node{Artifact: &maven.Artifact{GroupID: "org.codehaus.jackson", ArtifactID: "jackson-core-asl",
+					Version: "1.9.13"}, Omitted: maven.OmittedExcluded},
+			),
+		)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	load := func(name string, contents string) (*Policy, error) {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return Load(path)
+	}
+
+	It("should report the paths to denied artifacts", func() {
+		p, err := load("policy.yml", `rules:
+  - deny: log4j:log4j
+    versions: "[1.0,2.0)"
+    reason: log4j 1.x is no longer maintained
+  - deny: commons-collections:commons-collections
+    versions: "(,3.2.2)"
+  - deny: org.codehaus.jackson:*
+  - allow: org.codehaus.jackson:jackson-annotations
+`)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = p.Evaluate(root)
+		Expect(err).To(HaveOccurred())
+		violations := err.(*ViolationsError).Violations
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Artifact).To(BeIdenticalTo(log4j.Artifact))
+		Expect(violations[0].Paths).To(Equal([][]*maven.Artifact{
+			{root.Artifact, root.Children[0].Artifact, log4j.Artifact},
+			{root.Artifact, root.Children[3].Artifact, log4j.Artifact},
+		}))
+		Expect(err.Error()).To(Equal(`2 artifact(s) violate the dependency policy :
+  log4j:log4j:1.2.17 denied by [log4j:log4j [1.0,2.0)] : log4j 1.x is no longer maintained
+    org.fake:root:1 > org.fake:a:1 > log4j:log4j:1.2.17
+    org.fake:root:1 > org.fake:b:1 > log4j:log4j:1.2.17
+  org.codehaus.jackson:jackson-core-asl:1.9.13 denied by [org.codehaus.jackson:*]
+    org.fake:root:1 > org.codehaus.jackson:jackson-core-asl:1.9.13`))
+	})
+
+	It("should read JSON policies", func() {
+		p, err := load("policy.json", `{"rules": [{"deny": "org.fake:*", "versions": "[2,)"}]}`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.Evaluate(root)).To(Succeed())
+	})
+
+	It("should return meaningful errors for invalid rules", func() {
+		_, err := load("policy.yml", `rules:
+  - deny: log4j
+`)
+		Expect(err).To(MatchError(HaveSuffix("rule 1 has invalid artifacts [log4j], expected groupId:artifactId")))
+
+		_, err = load("policy.yml", `rules:
+  - deny: log4j:log4j
+    allow: log4j:log4j
+`)
+		Expect(err).To(MatchError(HaveSuffix("rule 1 expected either deny or allow")))
+
+		_, err = load("policy.yml", `rules:
+  - deny: log4j:log4j
+    versions: "[1.0,"
+`)
+		Expect(err).To(MatchError(HaveSuffix("rule 1 has invalid version range [[1.0,]")))
+	})
+})
@@ -0,0 +1,77 @@
+package redact
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mask replaces redacted values, like `url.URL` does for passwords.
+const Mask = `xxxxx`
+
+// MinSecretLength is the length of the shortest secret which can be masked, shorter ones masking too much unrelated
+// text. Credentials are rejected when they are loaded if they are shorter.
+const MinSecretLength = 4
+
+var userInfoRegex = regexp.MustCompile(`(\w+://[^/\s:@]*):[^/\s@]*@`)
+
+var (
+	// secrets are kept longest first, so that a secret containing another is masked as a whole
+	secrets     = make([]string, 0)
+	secretsLock sync.RWMutex
+)
+
+// Register makes a secret, like a password or token, be masked from then on. Secrets shorter than MinSecretLength are
+// ignored.
+func Register(secret string) {
+	if len(secret) < MinSecretLength {
+		return
+	}
+	secretsLock.Lock()
+	defer secretsLock.Unlock()
+	for _, s := range secrets {
+		if s == secret {
+			return
+		}
+	}
+	secrets = append(secrets, secret)
+	sort.SliceStable(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+// String masks registered secrets and the passwords of URLs in a message.
+func String(s string) string {
+	s = userInfoRegex.ReplaceAllString(s, "${1}:"+Mask+"@")
+
+	secretsLock.RLock()
+	defer secretsLock.RUnlock()
+	for _, secret := range secrets {
+		s = strings.Replace(s, secret, Mask, -1)
+	}
+	return s
+}
+
+// Error masks secrets in the message of an error, returning nil for nil errors.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	if redacted := String(err.Error()); redacted != err.Error() {
+		return &redactedError{message: redacted, cause: err}
+	}
+	return err
+}
+
+type redactedError struct {
+	message string
+	cause   error
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+// Cause keeps the original error available to `errors.Cause`.
+func (e *redactedError) Cause() error {
+	return e.cause
+}
@@ -0,0 +1,72 @@
+package settings
+
+import (
+	"net/url"
+	"strings"
+)
+
+const wildcard = `*`
+const externalWildcard = `external:*`
+
+// Mirror replaces every repository with the mirror configured for it, following Maven's rules: a mirror of the exact
+// repository ID wins over the first mirror with a matching pattern. Repositories mirrored by the same mirror are only
+// listed once.
+func (s *Settings) Mirror(repositories []*Repository) []*Repository {
+	mirrored := make([]*Repository, 0, len(repositories))
+	seen := map[string]bool{}
+	for _, repository := range repositories {
+		if mirror := s.mirrorOf(repository); mirror != nil {
+			logger.Debugf("Using mirror [%s] of repository [%s]", mirror.ID, repository.ID)
+			repository = &Repository{ID: mirror.ID, URL: mirror.URL}
+		}
+		if seen[repository.ID+" "+repository.URL] {
+			continue
+		}
+		seen[repository.ID+" "+repository.URL] = true
+		mirrored = append(mirrored, repository)
+	}
+	return mirrored
+}
+
+func (s *Settings) mirrorOf(repository *Repository) *Mirror {
+	for _, mirror := range s.Mirrors {
+		if mirror.MirrorOf == repository.ID {
+			return mirror
+		}
+	}
+	for _, mirror := range s.Mirrors {
+		if matchesMirrorOf(repository, mirror.MirrorOf) {
+			return mirror
+		}
+	}
+	return nil
+}
+
+// matchesMirrorOf evaluates a `mirrorOf` pattern, a comma separated list of repository IDs, `*` for any repository,
+// `external:*` for any repository not on this machine, and `!id` to exclude a repository.
+func matchesMirrorOf(repository *Repository, pattern string) bool {
+	matches := false
+	for _, p := range strings.Split(pattern, ",") {
+		p = strings.TrimSpace(p)
+		switch {
+		case len(p) > 1 && strings.HasPrefix(p, "!"):
+			if p[1:] == repository.ID {
+				return false
+			}
+		case p == repository.ID, p == wildcard:
+			matches = true
+		case p == externalWildcard && isExternal(repository):
+			matches = true
+		}
+	}
+	return matches
+}
+
+func isExternal(repository *Repository) bool {
+	u, err := url.Parse(repository.URL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	return u.Scheme != "file" && u.Scheme != "" && host != "localhost" && host != "127.0.0.1"
+}
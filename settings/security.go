@@ -0,0 +1,137 @@
+package settings
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// masterPasswordKey is the fixed password Maven encrypts the master password with.
+const masterPasswordKey = `settings.security`
+const saltSize = 8
+
+type settingsSecurity struct {
+	XMLName    xml.Name `xml:"settingsSecurity"`
+	Master     string   `xml:"master"`
+	Relocation string   `xml:"relocation"`
+}
+
+// DefaultSecurityPath is where Maven reads the master password from.
+func DefaultSecurityPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".m2", "settings-security.xml")
+}
+
+// LoadMasterPassword reads and decrypts the master password of a `settings-security.xml` file, following relocations
+// to other files.
+func LoadMasterPassword(path string) (string, error) {
+	for visited := map[string]bool{}; !visited[path]; {
+		visited[path] = true
+
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read master password [%s]", path)
+		}
+		security := &settingsSecurity{}
+		if err := xml.Unmarshal(contents, security); err != nil {
+			return "", errors.Wrapf(err, "error parsing master password [%s]", path)
+		}
+
+		if security.Master != "" {
+			master, err := Decrypt(security.Master, masterPasswordKey)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to decrypt master password [%s]", path)
+			}
+			return master, nil
+		}
+		if security.Relocation == "" {
+			break
+		}
+		path = security.Relocation
+	}
+	return "", errors.Errorf("no master password found in [%s]", path)
+}
+
+// Decrypt decrypts a value encrypted with `mvn --encrypt-password`. Encrypted values are enclosed in braces, anything
+// around them is a comment. Values which aren't encrypted are returned as is.
+func Decrypt(value string, password string) (string, error) {
+	encrypted, isEncrypted := encryptedPart(value)
+	if !isEncrypted {
+		return value, nil
+	}
+
+	bs, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid encrypted value")
+	}
+	// salt, followed by the length of the padding appended to the cipher text, followed by the cipher text
+	if len(bs) < saltSize+1 {
+		return "", errors.New("invalid encrypted value")
+	}
+	salt := bs[:saltSize]
+	padding := int(bs[saltSize])
+	cipherText := bs[saltSize+1:]
+	if padding > len(cipherText) {
+		return "", errors.New("invalid encrypted value")
+	}
+	cipherText = cipherText[:len(cipherText)-padding]
+	if len(cipherText) == 0 || len(cipherText)%aes.BlockSize != 0 {
+		return "", errors.New("invalid encrypted value")
+	}
+
+	// key and IV are derived from a single SHA-256 digest of the password and salt
+	digest := sha256.Sum256(append([]byte(password), salt...))
+	block, err := aes.NewCipher(digest[:16])
+	if err != nil {
+		return "", err
+	}
+	plainText := make([]byte, len(cipherText))
+	cipher.NewCBCDecrypter(block, digest[16:]).CryptBlocks(plainText, cipherText)
+
+	plainText, err = unpad(plainText)
+	if err != nil {
+		return "", err
+	}
+	return string(plainText), nil
+}
+
+func isEncrypted(value string) bool {
+	_, isEncrypted := encryptedPart(value)
+	return isEncrypted
+}
+
+// encryptedPart returns what is enclosed by the first pair of braces which aren't escaped.
+func encryptedPart(value string) (string, bool) {
+	start := -1
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+			continue
+		}
+		if value[i] == '{' && start < 0 {
+			start = i
+		} else if value[i] == '}' && start >= 0 {
+			return value[start+1 : i], true
+		}
+	}
+	return "", false
+}
+
+// unpad removes PKCS#5 padding.
+func unpad(bs []byte) ([]byte, error) {
+	if len(bs) == 0 {
+		return nil, errors.New("invalid padding, wrong password?")
+	}
+	n := int(bs[len(bs)-1])
+	if n == 0 || n > aes.BlockSize || n > len(bs) ||
+		!bytes.Equal(bs[len(bs)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, errors.New("invalid padding, wrong password?")
+	}
+	return bs[:len(bs)-n], nil
+}
@@ -0,0 +1,27 @@
+package settings_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/settings"
+)
+
+var _ = Describe("Decrypt", func() {
+	It("should decrypt values encrypted by Maven", func() {
+		Expect(Decrypt("{AQIDBAUGBwgHOgf8kH3tqIKeBcmmRS4gcqqqqqqqqqo=}", "settings.security")).To(Equal("s3cr3t-master"))
+	})
+
+	It("should ignore comments around encrypted values", func() {
+		Expect(Decrypt("rotated 2018-01-01 {ERITFBUWFxgHxpjxAdjVCyOtALeArQg8gaqqqqqqqqo=}", "s3cr3t-master")).To(Equal("hunter2"))
+	})
+
+	It("should return values which aren't encrypted as is", func() {
+		Expect(Decrypt(`plain \{not encrypted\}`, "s3cr3t-master")).To(Equal(`plain \{not encrypted\}`))
+	})
+
+	It("should fail with the wrong password", func() {
+		_, err := Decrypt("{ERITFBUWFxgHxpjxAdjVCyOtALeArQg8gaqqqqqqqqo=}", "wrong")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,173 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/xml"
+	_ "github.com/jspawar/generate-bazel-workspace-gradle/logging"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/net/html/charset"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const CentralID = `central`
+const CentralURL = `https://repo.maven.apache.org/maven2`
+
+var logger = zap.S()
+
+var expressionRegex = regexp.MustCompile(`\${(env\.[^}]+|user\.home)}`)
+
+// Settings is the subset of Maven's `settings.xml` needed to find and authenticate against repositories.
+type Settings struct {
+	XMLName         xml.Name   `xml:"settings"`
+	LocalRepository string     `xml:"localRepository"`
+	Servers         []*Server  `xml:"servers>server"`
+	Mirrors         []*Mirror  `xml:"mirrors>mirror"`
+	Profiles        []*Profile `xml:"profiles>profile"`
+	ActiveProfiles  []string   `xml:"activeProfiles>activeProfile"`
+}
+
+type Server struct {
+	ID       string `xml:"id"`
+	Username string `xml:"username"`
+	Password string `xml:"password"`
+}
+
+type Mirror struct {
+	ID       string `xml:"id"`
+	Name     string `xml:"name"`
+	URL      string `xml:"url"`
+	MirrorOf string `xml:"mirrorOf"`
+}
+
+type Profile struct {
+	ID           string        `xml:"id"`
+	Activation   Activation    `xml:"activation"`
+	Repositories []*Repository `xml:"repositories>repository"`
+}
+
+type Activation struct {
+	ActiveByDefault bool `xml:"activeByDefault"`
+}
+
+type Repository struct {
+	ID  string `xml:"id"`
+	URL string `xml:"url"`
+}
+
+// DefaultPath is where Maven reads user settings from.
+func DefaultPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".m2", "settings.xml")
+}
+
+// Load reads a `settings.xml` file, decrypting server passwords with the master password kept in `securityPath`.
+// Missing files are treated as empty settings, just like Maven does.
+func Load(path string, securityPath string) (*Settings, error) {
+	contents, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		logger.Debugf("No settings file found : %s", path)
+		return &Settings{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read settings [%s]", path)
+	}
+
+	s, err := Unmarshal(contents)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read settings [%s]", path)
+	}
+	if err := s.decryptPasswords(securityPath); err != nil {
+		return nil, errors.Wrapf(err, "failed to read settings [%s]", path)
+	}
+	return s, nil
+}
+
+func Unmarshal(contents []byte) (*Settings, error) {
+	// Maven interpolates environment variables before parsing, which is how CI servers usually inject credentials
+	contents = expressionRegex.ReplaceAllFunc(contents, func(expression []byte) []byte {
+		name := string(expressionRegex.FindSubmatch(expression)[1])
+		if name == "user.home" {
+			return []byte(os.Getenv("HOME"))
+		}
+		value, isSet := os.LookupEnv(name[len("env."):])
+		if !isSet {
+			return expression
+		}
+		return []byte(value)
+	})
+
+	s := &Settings{}
+	decoder := xml.NewDecoder(bytes.NewReader(contents))
+	decoder.CharsetReader = charset.NewReaderLabel
+	if err := decoder.Decode(s); err != nil {
+		return nil, errors.Wrapf(err, "error parsing settings")
+	}
+	return s, nil
+}
+
+// Server returns the server holding the credentials of a repository or mirror.
+func (s *Settings) Server(id string) *Server {
+	for _, server := range s.Servers {
+		if server.ID == id {
+			return server
+		}
+	}
+	return nil
+}
+
+// Repositories lists the repositories of the active profiles, followed by Maven Central.
+func (s *Settings) Repositories() []*Repository {
+	repositories := make([]*Repository, 0)
+	for _, profile := range s.activeProfiles() {
+		repositories = append(repositories, profile.Repositories...)
+	}
+	return append(repositories, &Repository{ID: CentralID, URL: CentralURL})
+}
+
+// activeProfiles are the profiles listed in `<activeProfiles>`. Profiles active by default are only used when none of
+// the other profiles are activated.
+func (s *Settings) activeProfiles() []*Profile {
+	explicit := map[string]bool{}
+	for _, id := range s.ActiveProfiles {
+		explicit[id] = true
+	}
+
+	active := make([]*Profile, 0)
+	byDefault := make([]*Profile, 0)
+	for _, profile := range s.Profiles {
+		if explicit[profile.ID] {
+			active = append(active, profile)
+		} else if profile.Activation.ActiveByDefault {
+			byDefault = append(byDefault, profile)
+		}
+	}
+	if len(active) == 0 {
+		return byDefault
+	}
+	return active
+}
+
+func (s *Settings) decryptPasswords(securityPath string) error {
+	var master string
+	for _, server := range s.Servers {
+		if !isEncrypted(server.Password) {
+			continue
+		}
+		if master == "" {
+			var err error
+			if master, err = LoadMasterPassword(securityPath); err != nil {
+				return err
+			}
+		}
+
+		password, err := Decrypt(server.Password, master)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decrypt password of server [%s]", server.ID)
+		}
+		server.Password = password
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package settings_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+)
+
+func TestSettings(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Settings Suite")
+}
+
+var _ = BeforeSuite(func() {
+	format.TruncatedDiff = false
+})
@@ -0,0 +1,187 @@
+package settings_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/settings"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("Settings", func() {
+	var (
+		err      error
+		dir      string
+		contents string
+		security string
+		s        *Settings
+	)
+
+	BeforeEach(func() {
+		dir, err = ioutil.TempDir("", "settings_test")
+		Expect(err).ToNot(HaveOccurred())
+		security = ""
+	})
+
+	JustBeforeEach(func() {
+		Expect(ioutil.WriteFile(filepath.Join(dir, "settings.xml"), []byte(contents), 0644)).To(Succeed())
+		if security != "" {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "settings-security.xml"), []byte(security), 0644)).To(Succeed())
+		}
+		s, err = Load(filepath.Join(dir, "settings.xml"), filepath.Join(dir, "settings-security.xml"))
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Context("given no settings file", func() {
+		It("should only search Maven Central", func() {
+			s, err = Load(filepath.Join(dir, "missing.xml"), filepath.Join(dir, "missing-security.xml"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Repositories()).To(Equal([]*Repository{{ID: CentralID, URL: CentralURL}}))
+		})
+	})
+
+	Context("given profiles", func() {
+		BeforeEach(func() {
+			contents = `<?xml version="1.0" encoding="UTF-8"?>
+<settings>
+  <profiles>
+    <profile>
+      <id>default</id>
+      <activation><activeByDefault>true</activeByDefault></activation>
+      <repositories>
+        <repository><id>default-repo</id><url>https://default.example.com/maven</url></repository>
+      </repositories>
+    </profile>
+    <profile>
+      <id>internal</id>
+      <repositories>
+        <repository><id>releases</id><url>https://nexus.example.com/releases</url></repository>
+        <repository><id>snapshots</id><url>https://nexus.example.com/snapshots</url></repository>
+      </repositories>
+    </profile>
+    <profile>
+      <id>unused</id>
+      <repositories>
+        <repository><id>unused</id><url>https://unused.example.com/maven</url></repository>
+      </repositories>
+    </profile>
+  </profiles>
+  <activeProfiles>
+    <activeProfile>internal</activeProfile>
+  </activeProfiles>
+</settings>`
+		})
+
+		It("should search the repositories of the active profiles before Maven Central", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Repositories()).To(Equal([]*Repository{
+				{ID: "releases", URL: "https://nexus.example.com/releases"},
+				{ID: "snapshots", URL: "https://nexus.example.com/snapshots"},
+				{ID: CentralID, URL: CentralURL},
+			}))
+		})
+	})
+
+	Context("given mirrors", func() {
+		var (
+			repositories []*Repository
+		)
+
+		BeforeEach(func() {
+			repositories = []*Repository{
+				{ID: "releases", URL: "https://nexus.example.com/releases"},
+				{ID: "local", URL: "http://localhost:8081/maven"},
+				{ID: "excluded", URL: "https://excluded.example.com/maven"},
+				{ID: CentralID, URL: CentralURL},
+			}
+		})
+
+		Context("of every external repository except one", func() {
+			BeforeEach(func() {
+				contents = `<settings>
+  <mirrors>
+    <mirror><id>proxy</id><url>https://proxy.example.com/maven</url><mirrorOf>external:*,!excluded</mirrorOf></mirror>
+    <mirror><id>central-proxy</id><url>https://central.example.com/maven</url><mirrorOf>central</mirrorOf></mirror>
+  </mirrors>
+</settings>`
+			})
+
+			It("should prefer the mirror of the exact repository and list every mirror once", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(s.Mirror(repositories)).To(Equal([]*Repository{
+					{ID: "proxy", URL: "https://proxy.example.com/maven"},
+					{ID: "local", URL: "http://localhost:8081/maven"},
+					{ID: "excluded", URL: "https://excluded.example.com/maven"},
+					{ID: "central-proxy", URL: "https://central.example.com/maven"},
+				}))
+			})
+		})
+
+		Context("of every repository", func() {
+			BeforeEach(func() {
+				contents = `<settings>
+  <mirrors>
+    <mirror><id>all</id><url>https://all.example.com/maven</url><mirrorOf>*</mirrorOf></mirror>
+  </mirrors>
+</settings>`
+			})
+
+			It("should only search the mirror", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(s.Mirror(repositories)).To(Equal([]*Repository{
+					{ID: "all", URL: "https://all.example.com/maven"},
+				}))
+			})
+		})
+	})
+
+	Context("given servers", func() {
+		BeforeEach(func() {
+			os.Setenv("SETTINGS_TEST_USERNAME", "deployer")
+			contents = `<settings>
+  <servers>
+    <server><id>plain</id><username>${env.SETTINGS_TEST_USERNAME}</username><password>not-encrypted</password></server>
+    <server><id>encrypted</id><username>admin</username><password>{ERITFBUWFxgHxpjxAdjVCyOtALeArQg8gaqqqqqqqqo=}</password></server>
+  </servers>
+</settings>`
+			security = `<settingsSecurity>
+  <master>{AQIDBAUGBwgHOgf8kH3tqIKeBcmmRS4gcqqqqqqqqqo=}</master>
+</settingsSecurity>`
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("SETTINGS_TEST_USERNAME")
+		})
+
+		It("should interpolate environment variables", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Server("plain")).To(Equal(&Server{ID: "plain", Username: "deployer", Password: "not-encrypted"}))
+		})
+
+		It("should decrypt passwords with the master password", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Server("encrypted")).To(Equal(&Server{ID: "encrypted", Username: "admin", Password: "hunter2"}))
+		})
+
+		It("should not find unknown servers", func() {
+			Expect(s.Server("unknown")).To(BeNil())
+		})
+
+		Context("without a master password", func() {
+			BeforeEach(func() {
+				security = ""
+			})
+
+			It("should return a meaningful error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("failed to read settings ["))
+				Expect(err.Error()).To(ContainSubstring("failed to read master password ["))
+			})
+		})
+	})
+})
@@ -0,0 +1,141 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxRetryAfter caps how long a server may ask to wait before retrying, beyond which its response is returned as is.
+const maxRetryAfter = 5 * time.Minute
+
+// RetryTransport retries requests which failed for reasons likely to be temporary, waiting for an exponentially
+// growing, randomized delay in between, or for as long as the server asks to with `Retry-After`. Only requests
+// without a body are retried, which is all a repository needs.
+type RetryTransport struct {
+	next   http.RoundTripper
+	config *Config
+	random *rand.Rand
+	lock   sync.Mutex
+}
+
+func NewRetryTransport(next http.RoundTripper, c *Config) *RetryTransport {
+	return &RetryTransport{next: next, config: c, random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := t.attempt(req)
+		if attempt >= t.config.Retries || hasBody(req) || !isRetryable(res, err) || req.Context().Err() != nil {
+			return res, err
+		}
+
+		wait := t.backoff(attempt)
+		if res != nil {
+			if retryAfter, isSet := parseRetryAfter(res.Header.Get("Retry-After")); isSet {
+				if retryAfter > maxRetryAfter {
+					return res, nil
+				}
+				wait = retryAfter
+			}
+			logger.Debugf("Retrying [%s] in %s after status : %d", req.URL, wait, res.StatusCode)
+			res.Body.Close()
+		} else {
+			logger.Debugf("Retrying [%s] in %s after : %s", req.URL, wait, err)
+		}
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends a request, giving up after the configured timeout. The timeout also covers reading the response.
+func (t *RetryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.config.Timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.config.Timeout)
+	res, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+// backoff is a random delay up to an exponentially growing limit, so that clients retrying at once spread out.
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	limit := t.config.MaxBackoff
+	if attempt < 32 {
+		if exp := t.config.MinBackoff << uint(attempt); exp > 0 && exp < limit {
+			limit = exp
+		}
+	}
+	if limit <= 0 {
+		return 0
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return limit/2 + time.Duration(t.random.Int63n(int64(limit/2)+1))
+}
+
+func isRetryable(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch res.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter reads a delay given either in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody
+}
@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	_ "github.com/jspawar/generate-bazel-workspace-gradle/logging"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+const DefaultTimeout = 30 * time.Second
+const DefaultConnectTimeout = 10 * time.Second
+const DefaultRetries = 3
+const DefaultMinBackoff = 500 * time.Millisecond
+const DefaultMaxBackoff = 30 * time.Second
+
+var logger = zap.S()
+
+// Config of the HTTP client used to download from repositories.
+type Config struct {
+	// Timeout limits each attempt at a request, including reading the response.
+	Timeout time.Duration
+	// ConnectTimeout limits establishing connections, including TLS handshakes.
+	ConnectTimeout time.Duration
+	// Retries is how many times a request is retried after a transport error, or a 429 or 5xx status.
+	Retries int
+	// MinBackoff and MaxBackoff bound the exponential backoff between retries.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// CABundles are PEM files of certificate authorities trusted in addition to the system ones.
+	CABundles []string
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout:        DefaultTimeout,
+		ConnectTimeout: DefaultConnectTimeout,
+		Retries:        DefaultRetries,
+		MinBackoff:     DefaultMinBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// NewClient builds an HTTP client which retries failed requests and goes through the proxies configured by the
+// `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.
+func NewClient(c *Config) (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+	if len(c.CABundles) > 0 {
+		pool, err := certPool(c.CABundles)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	dialer := &net.Dialer{Timeout: c.ConnectTimeout, KeepAlive: 30 * time.Second}
+	base := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   c.ConnectTimeout,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &http.Client{Transport: NewRetryTransport(base, c)}, nil
+}
+
+func certPool(bundles []string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		logger.Debugf("Failed to load system certificate authorities : %s", err)
+		pool = x509.NewCertPool()
+	}
+	for _, bundle := range bundles {
+		contents, err := ioutil.ReadFile(bundle)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read CA bundle [%s]", bundle)
+		}
+		if !pool.AppendCertsFromPEM(contents) {
+			return nil, errors.Errorf("failed to read CA bundle [%s] : no PEM certificates found", bundle)
+		}
+	}
+	return pool, nil
+}
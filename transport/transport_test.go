@@ -0,0 +1,239 @@
+package transport_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"encoding/pem"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/transport"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+var _ = Describe("Transport", func() {
+	var (
+		err      error
+		config   *Config
+		client   *http.Client
+		server   *httptest.Server
+		requests int32
+		handler  func(w http.ResponseWriter, r *http.Request, attempt int32)
+	)
+
+	BeforeEach(func() {
+		requests = 0
+		config = DefaultConfig()
+		config.MinBackoff = time.Millisecond
+		config.MaxBackoff = 10 * time.Millisecond
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(w, r, atomic.AddInt32(&requests, 1))
+		}))
+	})
+
+	JustBeforeEach(func() {
+		client, err = NewClient(config)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		Expect(err).ToNot(HaveOccurred())
+		res, err := client.Do(req.WithContext(ctx))
+		if err == nil {
+			res.Body.Close()
+		}
+		return res, err
+	}
+
+	Context("given a server failing temporarily", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, _ *http.Request, attempt int32) {
+				if attempt < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}
+		})
+
+		It("should retry until it succeeds", func() {
+			res, err := get(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(atomic.LoadInt32(&requests)).To(Equal(int32(3)))
+		})
+
+		Context("for longer than it retries", func() {
+			BeforeEach(func() {
+				config.Retries = 1
+			})
+
+			It("should return the last response", func() {
+				res, err := get(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.StatusCode).To(Equal(http.StatusServiceUnavailable))
+				Expect(atomic.LoadInt32(&requests)).To(Equal(int32(2)))
+			})
+		})
+	})
+
+	Context("given a server which does not have a file", func() {
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+
+		It("should not retry", func() {
+			res, err := get(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+			Expect(atomic.LoadInt32(&requests)).To(Equal(int32(1)))
+		})
+	})
+
+	Context("given a server asking to retry later", func() {
+		var (
+			retryAfter string
+		)
+
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, _ *http.Request, attempt int32) {
+				if attempt < 2 {
+					w.Header().Set("Retry-After", retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}
+		})
+
+		Context("within reason", func() {
+			BeforeEach(func() {
+				retryAfter = "1"
+			})
+
+			It("should wait for as long as asked to", func() {
+				start := time.Now()
+				res, err := get(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+			})
+		})
+
+		Context("much later", func() {
+			BeforeEach(func() {
+				retryAfter = "3600"
+			})
+
+			It("should give up straight away", func() {
+				res, err := get(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.StatusCode).To(Equal(http.StatusTooManyRequests))
+				Expect(atomic.LoadInt32(&requests)).To(Equal(int32(1)))
+			})
+		})
+	})
+
+	Context("given a server which hangs", func() {
+		var (
+			release chan struct{}
+		)
+
+		BeforeEach(func() {
+			release = make(chan struct{})
+			handler = func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				<-release
+			}
+		})
+
+		AfterEach(func() {
+			close(release)
+		})
+
+		Context("with a timeout", func() {
+			BeforeEach(func() {
+				config.Timeout = 20 * time.Millisecond
+				config.Retries = 2
+			})
+
+			It("should give up on each attempt after the timeout", func() {
+				_, err := get(context.Background())
+				Expect(err).To(HaveOccurred())
+				Eventually(func() int32 { return atomic.LoadInt32(&requests) }).Should(Equal(int32(3)))
+			})
+		})
+
+		It("should stop once cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+
+			start := time.Now()
+			_, err := get(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			Expect(atomic.LoadInt32(&requests)).To(Equal(int32(1)))
+		})
+	})
+
+	Context("given a server with a certificate from a custom authority", func() {
+		var (
+			dir       string
+			tlsServer *httptest.Server
+		)
+
+		BeforeEach(func() {
+			handler = func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				w.WriteHeader(http.StatusOK)
+			}
+			tlsServer = httptest.NewTLSServer(server.Config.Handler)
+
+			dir, err = ioutil.TempDir("", "transport_test")
+			Expect(err).ToNot(HaveOccurred())
+			bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+			Expect(ioutil.WriteFile(filepath.Join(dir, "ca.pem"), bundle, 0644)).To(Succeed())
+			config.Retries = 0
+		})
+
+		AfterEach(func() {
+			tlsServer.Close()
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should not trust it by default", func() {
+			_, err := client.Get(tlsServer.URL)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should trust it given the authority's bundle", func() {
+			config.CABundles = []string{filepath.Join(dir, "ca.pem")}
+			client, err = NewClient(config)
+			Expect(err).ToNot(HaveOccurred())
+
+			res, err := client.Get(tlsServer.URL)
+			Expect(err).ToNot(HaveOccurred())
+			res.Body.Close()
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+		})
+
+		It("should fail on bundles without certificates", func() {
+			Expect(ioutil.WriteFile(filepath.Join(dir, "empty.pem"), []byte("nothing"), 0644)).To(Succeed())
+			config.CABundles = []string{filepath.Join(dir, "empty.pem")}
+
+			_, err = NewClient(config)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("failed to read CA bundle ["))
+		})
+	})
+})
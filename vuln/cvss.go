@@ -0,0 +1,76 @@
+package vuln
+
+import (
+	"math"
+	"strings"
+)
+
+// weights of the base metrics of CVSS v3, scope changed privileges being looked up with a `PR:C:` prefix.
+// See: https://www.first.org/cvss/v3.1/specification-document#7-4-Metric-Values
+var cvssWeights = map[string]float64{
+	"AV:N": 0.85, "AV:A": 0.62, "AV:L": 0.55, "AV:P": 0.2,
+	"AC:L": 0.77, "AC:H": 0.44,
+	"PR:N": 0.85, "PR:L": 0.62, "PR:H": 0.27,
+	"PR:C:N": 0.85, "PR:C:L": 0.68, "PR:C:H": 0.5,
+	"UI:N": 0.85, "UI:R": 0.62,
+	"C:H": 0.56, "C:L": 0.22, "C:N": 0,
+	"I:H": 0.56, "I:L": 0.22, "I:N": 0,
+	"A:H": 0.56, "A:L": 0.22, "A:N": 0,
+}
+
+// cvssBaseScore computes the base score of a CVSS v3 vector, like `CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H`,
+// returning false if the vector is invalid.
+func cvssBaseScore(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) < 1 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, false
+	}
+	metrics := map[string]string{}
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return 0, false
+		}
+		metrics[kv[0]] = kv[1]
+	}
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, false
+	}
+
+	weights := map[string]float64{}
+	for _, metric := range []string{"AV", "AC", "PR", "UI", "C", "I", "A"} {
+		key := metric + ":" + metrics[metric]
+		if metric == "PR" && changed {
+			key = "PR:C:" + metrics[metric]
+		}
+		weight, ok := cvssWeights[key]
+		if !ok {
+			return 0, false
+		}
+		weights[metric] = weight
+	}
+
+	iss := 1 - (1-weights["C"])*(1-weights["I"])*(1-weights["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	exploitability := 8.22 * weights["AV"] * weights["AC"] * weights["PR"] * weights["UI"]
+	if impact <= 0 {
+		return 0, true
+	}
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp rounds up to one decimal, the way CVSS v3.1 defines it to avoid floating point errors.
+func roundUp(value float64) float64 {
+	i := int(math.Round(value * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
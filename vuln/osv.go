@@ -0,0 +1,162 @@
+package vuln
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const mavenEcosystem = "Maven"
+
+// Vulnerability is an advisory in the OSV format, only keeping what matching Maven artifacts needs.
+// See: https://ossf.github.io/osv-schema/
+type Vulnerability struct {
+	ID               string            `json:"id"`
+	Aliases          []string          `json:"aliases"`
+	Summary          string            `json:"summary"`
+	Withdrawn        string            `json:"withdrawn"`
+	Severity         []*osvSeverity    `json:"severity"`
+	Affected         []*osvAffected    `json:"affected"`
+	DatabaseSpecific *databaseSpecific `json:"database_specific"`
+}
+
+type osvSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges            []*osvRange       `json:"ranges"`
+	Versions          []string          `json:"versions"`
+	Severity          []*osvSeverity    `json:"severity"`
+	EcosystemSpecific *databaseSpecific `json:"ecosystem_specific"`
+	DatabaseSpecific  *databaseSpecific `json:"database_specific"`
+}
+
+type osvRange struct {
+	Type   string      `json:"type"`
+	Events []*osvEvent `json:"events"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// databaseSpecific holds the severity label some databases, like GitHub's, add to advisories.
+type databaseSpecific struct {
+	Severity string `json:"severity"`
+}
+
+// CVEs lists the CVE identifiers of the vulnerability, which may be its own ID or one of its aliases.
+func (v *Vulnerability) CVEs() []string {
+	cves := make([]string, 0, 1)
+	for _, id := range append([]string{v.ID}, v.Aliases...) {
+		if strings.HasPrefix(id, "CVE-") {
+			cves = append(cves, id)
+		}
+	}
+	return cves
+}
+
+// Load reads an OSV export from a directory of JSON files, as unpacked from the `all.zip` of an ecosystem, from that
+// zip archive itself, or from a single JSON file holding one advisory or a list of them. Nothing is ever downloaded.
+func Load(path string) (*Database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read vulnerability database [%s]", path)
+	}
+
+	db := newDatabase()
+	switch {
+	case info.IsDir():
+		err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !strings.HasSuffix(file, ".json") {
+				return err
+			}
+			contents, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			return db.add(file, contents)
+		})
+	case strings.HasSuffix(path, ".zip"):
+		err = loadZip(db, path)
+	default:
+		var contents []byte
+		contents, err = ioutil.ReadFile(path)
+		if err == nil {
+			err = db.add(path, contents)
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read vulnerability database [%s]", path)
+	}
+	return db, nil
+}
+
+func loadZip(db *Database, path string) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	for _, f := range archive.File {
+		if !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+		contents, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+		if err := db.add(f.Name, contents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add indexes the advisories of a file by the Maven artifacts they affect.
+func (db *Database) add(file string, contents []byte) error {
+	var vulns []*Vulnerability
+	if bytes.HasPrefix(bytes.TrimSpace(contents), []byte("[")) {
+		if err := json.Unmarshal(contents, &vulns); err != nil {
+			return errors.Wrapf(err, "error parsing advisories [%s]", file)
+		}
+	} else {
+		v := &Vulnerability{}
+		if err := json.Unmarshal(contents, v); err != nil {
+			return errors.Wrapf(err, "error parsing advisory [%s]", file)
+		}
+		vulns = append(vulns, v)
+	}
+
+	for _, v := range vulns {
+		if v.Withdrawn != "" {
+			continue
+		}
+		for _, affected := range v.Affected {
+			if affected.Package.Ecosystem == mavenEcosystem {
+				db.advisories[affected.Package.Name] = append(db.advisories[affected.Package.Name],
+					&advisory{vulnerability: v, affected: affected})
+			}
+		}
+	}
+	return nil
+}
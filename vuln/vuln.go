@@ -0,0 +1,307 @@
+package vuln
+
+import (
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity ranks vulnerabilities the way CVSS qualitative ratings do, unknown being the lowest.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityNone
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityUnknown:  "UNKNOWN",
+	SeverityNone:     "NONE",
+	SeverityLow:      "LOW",
+	SeverityMedium:   "MEDIUM",
+	SeverityHigh:     "HIGH",
+	SeverityCritical: "CRITICAL",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// ParseSeverity reads a severity name, case insensitively. GitHub's `moderate` stands for medium.
+func ParseSeverity(name string) (Severity, error) {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+	if upper == "MODERATE" {
+		return SeverityMedium, nil
+	}
+	for severity, n := range severityNames {
+		if n == upper && severity != SeverityUnknown {
+			return severity, nil
+		}
+	}
+	return SeverityUnknown, errors.Errorf("unknown severity [%s], expected one of : low, medium, high, critical", name)
+}
+
+func scoreSeverity(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityNone
+}
+
+// Database is a local copy of advisories, indexed by the `groupId:artifactId` of the Maven artifacts they affect.
+type Database struct {
+	advisories map[string][]*advisory
+}
+
+type advisory struct {
+	vulnerability *Vulnerability
+	affected      *osvAffected
+}
+
+func newDatabase() *Database {
+	return &Database{advisories: map[string][]*advisory{}}
+}
+
+// Finding is a vulnerability affecting an artifact of the graph, along with the shortest path it was resolved through.
+type Finding struct {
+	Artifact      *maven.Artifact
+	Vulnerability *Vulnerability
+	Severity      Severity
+	// FixedIn is the nearest version fixing the vulnerability, empty if no fix is known.
+	FixedIn string
+	// Upgrade is the nearest version affected by none of the known vulnerabilities of the artifact, empty if there is
+	// none.
+	Upgrade string
+	Path    []*maven.Artifact
+}
+
+// Match finds the vulnerabilities affecting each artifact of a resolved graph, most severe first.
+func (db *Database) Match(root *maven.Node) []*Finding {
+	findings := make([]*Finding, 0)
+	root.Walk(func(node *maven.Node, _ int) bool {
+		if node.IsOmitted() {
+			return false
+		}
+		artifact := node.Artifact
+		advisories := db.advisories[artifact.GetVersionlessCoords()]
+		for _, a := range advisories {
+			if a.affects(artifact.Version) {
+				findings = append(findings, &Finding{
+					Artifact:      artifact,
+					Vulnerability: a.vulnerability,
+					Severity:      a.severity(),
+					FixedIn:       a.fixedVersion(artifact.Version),
+					Upgrade:       upgrade(advisories, artifact.Version),
+					Path:          shortestPath(root, node),
+				})
+			}
+		}
+		return true
+	})
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity > findings[j].Severity
+		}
+		if findings[i].Artifact.GetMavenCoords() != findings[j].Artifact.GetMavenCoords() {
+			return findings[i].Artifact.GetMavenCoords() < findings[j].Artifact.GetMavenCoords()
+		}
+		return findings[i].Vulnerability.ID < findings[j].Vulnerability.ID
+	})
+	return findings
+}
+
+// shortestPath lists the artifacts of the shortest path a node was resolved through, which may end with a dependency
+// omitted in its favor.
+func shortestPath(root *maven.Node, node *maven.Node) []*maven.Artifact {
+	paths := root.ResolvedPathsTo(node)
+	path := make([]*maven.Artifact, len(paths[0]))
+	for i, n := range paths[0] {
+		path[i] = n.Artifact
+	}
+	path[len(path)-1] = node.Artifact
+	return path
+}
+
+// affects tells whether a version is listed as affected, or falls within one of the affected ranges.
+func (a *advisory) affects(version string) bool {
+	for _, v := range a.affected.Versions {
+		if maven.CompareVersions(v, version) == 0 {
+			return true
+		}
+	}
+	for _, r := range a.affected.Ranges {
+		if r.Type != "GIT" && r.affects(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// affects evaluates the events of a range in version order, as the OSV schema describes.
+func (r *osvRange) affects(version string) bool {
+	events := append([]*osvEvent{}, r.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return compareEventVersions(events[i].version(), events[j].version()) < 0
+	})
+
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "" && compareEventVersions(version, e.Introduced) >= 0:
+			affected = true
+		case e.Fixed != "" && compareEventVersions(version, e.Fixed) >= 0:
+			affected = false
+		case e.LastAffected != "" && compareEventVersions(version, e.LastAffected) > 0:
+			affected = false
+		case e.Limit != "" && compareEventVersions(version, e.Limit) >= 0:
+			affected = false
+		}
+	}
+	return affected
+}
+
+func (e *osvEvent) version() string {
+	for _, v := range []string{e.Introduced, e.Fixed, e.LastAffected, e.Limit} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// compareEventVersions orders versions of events, `0` standing for the very first version.
+func compareEventVersions(a string, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "0":
+		return -1
+	case b == "0":
+		return 1
+	}
+	return maven.CompareVersions(a, b)
+}
+
+// fixedVersion finds the lowest fixed version above a version, which is the shortest upgrade out of the advisory.
+func (a *advisory) fixedVersion(version string) string {
+	fixed := ""
+	for _, r := range a.affected.Ranges {
+		for _, e := range r.Events {
+			if e.Fixed == "" || maven.CompareVersions(e.Fixed, version) <= 0 {
+				continue
+			}
+			if fixed == "" || maven.CompareVersions(e.Fixed, fixed) < 0 {
+				fixed = e.Fixed
+			}
+		}
+	}
+	return fixed
+}
+
+// upgrade follows fixed versions until reaching one no advisory affects, the shortest upgrade out of all of them.
+func upgrade(advisories []*advisory, version string) string {
+	for candidate := version; ; {
+		next := ""
+		for _, a := range advisories {
+			if !a.affects(candidate) {
+				continue
+			}
+			fixed := a.fixedVersion(candidate)
+			if fixed == "" {
+				return ""
+			}
+			if next == "" || maven.CompareVersions(fixed, next) > 0 {
+				next = fixed
+			}
+		}
+		if next == "" {
+			return candidate
+		}
+		candidate = next
+	}
+}
+
+// severity is the highest of the severity labels and CVSS v3 scores given by the advisory.
+func (a *advisory) severity() Severity {
+	severity := SeverityUnknown
+	raise := func(s Severity) {
+		if s > severity {
+			severity = s
+		}
+	}
+	for _, specific := range []*databaseSpecific{a.vulnerability.DatabaseSpecific, a.affected.DatabaseSpecific,
+		a.affected.EcosystemSpecific} {
+		if specific != nil {
+			if s, err := ParseSeverity(specific.Severity); err == nil {
+				raise(s)
+			}
+		}
+	}
+	for _, s := range append(append([]*osvSeverity{}, a.vulnerability.Severity...), a.affected.Severity...) {
+		if score, err := strconv.ParseFloat(s.Score, 64); err == nil {
+			raise(scoreSeverity(score))
+		} else if score, ok := cvssBaseScore(s.Score); ok {
+			raise(scoreSeverity(score))
+		}
+	}
+	return severity
+}
+
+// WriteReport prints findings grouped by artifact, with the upgrade fixing every vulnerability of each artifact.
+func WriteReport(w io.Writer, findings []*Finding) error {
+	b := &strings.Builder{}
+	byArtifact := map[string][]*Finding{}
+	artifacts := make([]string, 0)
+	for _, f := range findings {
+		coords := f.Artifact.GetMavenCoords()
+		if _, isKnown := byArtifact[coords]; !isKnown {
+			artifacts = append(artifacts, coords)
+		}
+		byArtifact[coords] = append(byArtifact[coords], f)
+	}
+
+	for _, coords := range artifacts {
+		artifactFindings := byArtifact[coords]
+		path := make([]string, len(artifactFindings[0].Path))
+		for i, a := range artifactFindings[0].Path {
+			path[i] = a.GetMavenCoords()
+		}
+		fmt.Fprintf(b, "%s\n  via %s\n", coords, strings.Join(path, " > "))
+
+		for _, f := range artifactFindings {
+			ids := f.Vulnerability.CVEs()
+			if len(ids) < 1 || ids[0] != f.Vulnerability.ID {
+				ids = append(ids, f.Vulnerability.ID)
+			}
+			fmt.Fprintf(b, "  %-8s  %s", f.Severity, strings.Join(ids, ", "))
+			if f.Vulnerability.Summary != "" {
+				fmt.Fprintf(b, " : %s", f.Vulnerability.Summary)
+			}
+			b.WriteString("\n")
+		}
+		if upgrade := artifactFindings[0].Upgrade; upgrade != "" {
+			fmt.Fprintf(b, "  upgrade to %s\n", upgrade)
+		} else {
+			b.WriteString("  no fixed version known\n")
+		}
+	}
+	fmt.Fprintf(b, "%d vulnerable artifact(s), %d vulnerabilit(ies)\n", len(artifacts), len(findings))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,18 @@
+package vuln_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+)
+
+func TestVuln(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vuln Suite")
+}
+
+var _ = BeforeSuite(func() {
+	format.TruncatedDiff = false
+})
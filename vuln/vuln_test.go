@@ -0,0 +1,256 @@
+package vuln_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"archive/zip"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/vuln"
+	"github.com/onsi/gomega/gbytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const log4ShellAdvisory = `{
+  "id": "GHSA-jfh8-c2jp-5v3q",
+  "aliases": ["CVE-2021-44228"],
+  "summary": "Remote code injection in Log4j",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.13.0"}, {"fixed": "2.15.0"}, {"introduced": "2.0-beta9"}, {"fixed": "2.3.1"}]}]
+  }],
+  "database_specific": {"severity": "CRITICAL"}
+}`
+
+const contextLookupAdvisory = `{
+  "id": "GHSA-7rjr-3q55-vv33",
+  "aliases": ["CVE-2021-45046"],
+  "summary": "Incomplete fix for Apache Log4j vulnerability",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:C/C:H/I:H/A:H"}],
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "2.16.0"}]}]
+  }]
+}`
+
+const recursionAdvisories = `[{
+  "id": "GHSA-p6xc-xr62-6r2g",
+  "aliases": ["CVE-2021-45105"],
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:L/I:N/A:N"}],
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.16.0"}, {"fixed": "2.17.0"}]}]
+  }]
+}, {
+  "id": "GHSA-withdrawn",
+  "withdrawn": "2022-01-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}]}]
+  }]
+}, {
+  "id": "PYSEC-0000-0",
+  "affected": [{
+    "package": {"ecosystem": "PyPI", "name": "org.apache.commons:commons-text"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}]}]
+  }]
+}]`
+
+const textAdvisory = `{
+  "id": "CVE-2022-42889",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.commons:commons-text"},
+    "versions": ["1.9"],
+    "database_specific": {"severity": "MODERATE"}
+  }]
+}`
+
+var _ = Describe("Vulnerability database", func() {
+	var (
+		err   error
+		dir   string
+		dbDir string
+		db    *Database
+		root  *maven.Node
+		log4j *maven.Artifact
+	)
+
+	BeforeEach(func() {
+		dir, err = ioutil.TempDir("", "vuln_test")
+		Expect(err).ToNot(HaveOccurred())
+		dbDir = filepath.Join(dir, "maven")
+		Expect(os.MkdirAll(filepath.Join(dbDir, "nested"), 0700)).To(Succeed())
+		for name, contents := range map[string]string{
+			"GHSA-jfh8-c2jp-5v3q.json":        log4ShellAdvisory,
+			"nested/GHSA-7rjr-3q55-vv33.json": contextLookupAdvisory,
+			"more.json":                       recursionAdvisories,
+			"CVE-2022-42889.json":             textAdvisory,
+			"README.md":                       "not an advisory",
+		} {
+			Expect(ioutil.WriteFile(filepath.Join(dbDir, name), []byte(contents), 0600)).To(Succeed())
+		}
+
+		log4j = &maven.Artifact{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1"}
+		root = &maven.Node{Artifact: &maven.Artifact{GroupID: "org.fake", ArtifactID: "app", Version: "1.0"},
+			Children: []*maven.Node{
+				{Artifact: &maven.Artifact{GroupID: "org.fake", ArtifactID: "lib", Version: "1.0"},
+					Children: []*maven.Node{{Artifact: log4j}}},
+				{Artifact: &maven.Artifact{GroupID: "org.apache.commons", ArtifactID: "commons-text", Version: "1.9"}},
+			}}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		db, err = Load(dbDir)
+	})
+
+	It("should match artifacts against the affected ranges and versions", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		findings := db.Match(root)
+		Expect(findings).To(HaveLen(3))
+
+		Expect(findings[0].Artifact).To(Equal(log4j))
+		Expect(findings[0].Vulnerability.ID).To(Equal("GHSA-7rjr-3q55-vv33"))
+		Expect(findings[0].Severity).To(Equal(SeverityCritical))
+		Expect(findings[0].FixedIn).To(Equal("2.16.0"))
+		Expect(findings[0].Path).To(Equal([]*maven.Artifact{root.Artifact, root.Children[0].Artifact, log4j}))
+
+		Expect(findings[1].Vulnerability.CVEs()).To(Equal([]string{"CVE-2021-44228"}))
+		Expect(findings[1].Severity).To(Equal(SeverityCritical))
+		Expect(findings[1].FixedIn).To(Equal("2.15.0"))
+
+		Expect(findings[2].Artifact.ArtifactID).To(Equal("commons-text"))
+		Expect(findings[2].Vulnerability.CVEs()).To(Equal([]string{"CVE-2022-42889"}))
+		Expect(findings[2].Severity).To(Equal(SeverityMedium))
+		Expect(findings[2].FixedIn).To(BeEmpty())
+	})
+
+	It("should suggest the nearest upgrade out of every known vulnerability", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		findings := db.Match(root)
+		Expect(findings[0].Upgrade).To(Equal("2.17.0"))
+		Expect(findings[1].Upgrade).To(Equal("2.17.0"))
+		Expect(findings[2].Upgrade).To(BeEmpty())
+	})
+
+	It("should score CVSS v3 vectors", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		log4j.Version = "2.16.0"
+		findings := db.Match(root)
+		Expect(findings).To(HaveLen(2))
+		Expect(findings[0].Vulnerability.ID).To(Equal("CVE-2022-42889"))
+		Expect(findings[1].Vulnerability.ID).To(Equal("GHSA-p6xc-xr62-6r2g"))
+		Expect(findings[1].Severity).To(Equal(SeverityMedium))
+	})
+
+	It("should not match fixed versions", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		log4j.Version = "2.17.1"
+		root.Children[1].Artifact.Version = "1.10.0"
+		Expect(db.Match(root)).To(BeEmpty())
+	})
+
+	It("should print CVE IDs, severities and upgrades", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		out := gbytes.NewBuffer()
+		Expect(WriteReport(out, db.Match(root))).To(Succeed())
+		Expect(string(out.Contents())).To(Equal(
+			`org.apache.logging.log4j:log4j-core:2.14.1
+  via org.fake:app:1.0 > org.fake:lib:1.0 > org.apache.logging.log4j:log4j-core:2.14.1
+  CRITICAL  CVE-2021-45046, GHSA-7rjr-3q55-vv33 : Incomplete fix for Apache Log4j vulnerability
+  CRITICAL  CVE-2021-44228, GHSA-jfh8-c2jp-5v3q : Remote code injection in Log4j
+  upgrade to 2.17.0
+org.apache.commons:commons-text:1.9
+  via org.fake:app:1.0 > org.apache.commons:commons-text:1.9
+  MEDIUM    CVE-2022-42889
+  no fixed version known
+2 vulnerable artifact(s), 3 vulnerabilit(ies)
+`))
+	})
+
+	Context("given an artifact resolved through several paths", func() {
+		BeforeEach(func() {
+			root.Children = append(root.Children, &maven.Node{
+				Artifact: &maven.Artifact{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1"},
+				Omitted:  maven.OmittedDuplicate,
+				Winner:   root.Children[0].Children[0],
+			})
+		})
+
+		It("should report the shortest path to it", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			findings := db.Match(root)
+			Expect(findings).To(HaveLen(3))
+			Expect(findings[0].Path).To(Equal([]*maven.Artifact{root.Artifact, log4j}))
+			Expect(findings[1].Path).To(Equal([]*maven.Artifact{root.Artifact, log4j}))
+		})
+	})
+
+	Context("given a zip archive", func() {
+		BeforeEach(func() {
+			archive, err := os.Create(filepath.Join(dir, "all.zip"))
+			Expect(err).ToNot(HaveOccurred())
+			w := zip.NewWriter(archive)
+			f, err := w.Create("GHSA-jfh8-c2jp-5v3q.json")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = f.Write([]byte(log4ShellAdvisory))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(w.Close()).To(Succeed())
+			Expect(archive.Close()).To(Succeed())
+			dbDir = archive.Name()
+		})
+
+		It("should read the advisories it holds", func() {
+			Expect(err).ToNot(HaveOccurred())
+			findings := db.Match(root)
+			Expect(findings).To(HaveLen(1))
+			Expect(findings[0].Vulnerability.ID).To(Equal("GHSA-jfh8-c2jp-5v3q"))
+		})
+	})
+
+	Context("given an invalid advisory", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(filepath.Join(dbDir, "broken.json"), []byte(`{"id": `), 0600)).To(Succeed())
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("failed to read vulnerability database [" + dbDir + "]: error parsing advisory ["))
+		})
+	})
+
+	Context("given a missing database", func() {
+		BeforeEach(func() {
+			dbDir = filepath.Join(dir, "missing")
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("failed to read vulnerability database [" + dbDir + "]"))
+		})
+	})
+})
+
+var _ = Describe("Severity", func() {
+	It("should parse severity names", func() {
+		for name, severity := range map[string]Severity{
+			"low": SeverityLow, "MEDIUM": SeverityMedium, "moderate": SeverityMedium, "High": SeverityHigh,
+			"critical": SeverityCritical,
+		} {
+			Expect(ParseSeverity(name)).To(Equal(severity), name)
+		}
+		_, err := ParseSeverity("severe")
+		Expect(err).To(MatchError("unknown severity [severe], expected one of : low, medium, high, critical"))
+	})
+})
@@ -0,0 +1,164 @@
+package writer
+
+import (
+	"encoding/json"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"io"
+	"time"
+)
+
+const cycloneDXSpecVersion = "1.4"
+
+var _ GraphWriter = &CycloneDXWriter{}
+
+// CycloneDXWriter writes a CycloneDX JSON SBOM of the dependency graph, the root artifact being the component the
+// SBOM describes.
+type CycloneDXWriter struct {
+	out io.Writer
+}
+
+func NewCycloneDXWriter(w io.Writer) *CycloneDXWriter {
+	return &CycloneDXWriter{out: w}
+}
+
+type cycloneDXBOM struct {
+	BOMFormat    string                 `json:"bomFormat"`
+	SpecVersion  string                 `json:"specVersion"`
+	Version      int                    `json:"version"`
+	Metadata     *cycloneDXMetadata     `json:"metadata"`
+	Components   []*cycloneDXComponent  `json:"components"`
+	Dependencies []*cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string              `json:"timestamp"`
+	Tools     []*cycloneDXTool    `json:"tools"`
+	Component *cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTool struct {
+	Name string `json:"name"`
+}
+
+type cycloneDXComponent struct {
+	Type               string                        `json:"type"`
+	BOMRef             string                        `json:"bom-ref"`
+	Group              string                        `json:"group"`
+	Name               string                        `json:"name"`
+	Version            string                        `json:"version"`
+	Scope              string                        `json:"scope,omitempty"`
+	Hashes             []*cycloneDXHash              `json:"hashes,omitempty"`
+	Licenses           []*cycloneDXLicenseChoice     `json:"licenses,omitempty"`
+	PURL               string                        `json:"purl"`
+	ExternalReferences []*cycloneDXExternalReference `json:"externalReferences,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXLicenseChoice struct {
+	License *cycloneDXLicense `json:"license"`
+}
+
+type cycloneDXLicense struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type cycloneDXExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func (w *CycloneDXWriter) Write(root *maven.Node) error {
+	artifact := root.Artifact
+	bom := &cycloneDXBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: cycloneDXSpecVersion,
+		Version:     1,
+		Metadata: &cycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools:     []*cycloneDXTool{{Name: sbomTool}},
+			Component: newCycloneDXComponent(artifact),
+		},
+		Components:   make([]*cycloneDXComponent, 0),
+		Dependencies: make([]*cycloneDXDependency, 0),
+	}
+	dependsOn := map[string][]string{}
+	for _, edge := range dependencyEdges(root) {
+		// optional dependencies aren't components of the SBOM
+		if edge.optional {
+			continue
+		}
+		dependsOn[edge.from.GetMavenCoords()] = append(dependsOn[edge.from.GetMavenCoords()], PackageURL(edge.to))
+	}
+	for i, a := range resolvedArtifacts(root) {
+		if i > 0 {
+			bom.Components = append(bom.Components, newCycloneDXComponent(a))
+		}
+		dependency := &cycloneDXDependency{Ref: PackageURL(a), DependsOn: dependsOn[a.GetMavenCoords()]}
+		if dependency.DependsOn == nil {
+			dependency.DependsOn = make([]string, 0)
+		}
+		bom.Dependencies = append(bom.Dependencies, dependency)
+	}
+
+	encoder := json.NewEncoder(w.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bom)
+}
+
+func newCycloneDXComponent(artifact *maven.Artifact) *cycloneDXComponent {
+	component := &cycloneDXComponent{
+		Type:    "library",
+		BOMRef:  PackageURL(artifact),
+		Group:   artifact.GroupID,
+		Name:    artifact.ArtifactID,
+		Version: artifact.Version,
+		Scope:   cycloneDXScope(artifact),
+		PURL:    PackageURL(artifact),
+	}
+	if artifact.SHA != "" {
+		component.Hashes = append(component.Hashes, &cycloneDXHash{Algorithm: "SHA-1", Content: artifact.SHA})
+	}
+	for _, license := range artifact.Licenses {
+		if license.Name == "" && license.URL == "" {
+			continue
+		}
+		if id := license.SPDXID(); id != "" {
+			component.Licenses = append(component.Licenses, &cycloneDXLicenseChoice{License: &cycloneDXLicense{ID: id}})
+			continue
+		}
+		component.Licenses = append(component.Licenses, &cycloneDXLicenseChoice{License: &cycloneDXLicense{
+			Name: license.String(),
+			URL:  license.URL,
+		}})
+	}
+	if url := downloadURL(artifact); url != "" {
+		component.ExternalReferences = append(component.ExternalReferences,
+			&cycloneDXExternalReference{Type: "distribution", URL: url})
+	}
+	return component
+}
+
+// cycloneDXScope tells whether a component is needed at runtime, test dependencies being excluded from it.
+func cycloneDXScope(artifact *maven.Artifact) string {
+	switch {
+	case artifact.Scope == maven.ScopeTest:
+		return "excluded"
+	case artifact.Optional:
+		return "optional"
+	case artifact.Scope == "":
+		// the root of the graph
+		return ""
+	}
+	return "required"
+}
@@ -0,0 +1,137 @@
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+const TextFormat = "text"
+
+// GraphDiff is what changed between two resolutions of dependency graphs, artifacts being matched by group and
+// artifact IDs.
+type GraphDiff struct {
+	Added      []*GraphNode     `json:"added"`
+	Removed    []*GraphNode     `json:"removed"`
+	Upgraded   []*VersionChange `json:"upgraded"`
+	Downgraded []*VersionChange `json:"downgraded"`
+}
+
+type VersionChange struct {
+	GroupID    string `json:"groupId"`
+	ArtifactID string `json:"artifactId"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+func (c *VersionChange) String() string {
+	return fmt.Sprintf("%s:%s %s -> %s", c.GroupID, c.ArtifactID, c.From, c.To)
+}
+
+// ReadGraph reads a dependency graph exported in the JSON format.
+func ReadGraph(contents []byte) (*Graph, error) {
+	graph := &Graph{}
+	if err := json.Unmarshal(contents, graph); err != nil {
+		return nil, errors.Wrap(err, "error parsing dependency graph")
+	}
+	if graph.Version != GraphSchemaVersion {
+		return nil, errors.Errorf("unsupported dependency graph schema version [%d], expected %d",
+			graph.Version, GraphSchemaVersion)
+	}
+	return graph, nil
+}
+
+// DiffGraphs compares the artifacts of two dependency graphs, whatever the paths they were resolved through.
+func DiffGraphs(old *Graph, new *Graph) *GraphDiff {
+	diff := &GraphDiff{
+		Added:      make([]*GraphNode, 0),
+		Removed:    make([]*GraphNode, 0),
+		Upgraded:   make([]*VersionChange, 0),
+		Downgraded: make([]*VersionChange, 0),
+	}
+	oldNodes, newNodes := nodesByArtifact(old), nodesByArtifact(new)
+	for key, node := range newNodes {
+		previous, existed := oldNodes[key]
+		if !existed {
+			diff.Added = append(diff.Added, node)
+			continue
+		}
+		change := &VersionChange{GroupID: node.GroupID, ArtifactID: node.ArtifactID, From: previous.Version, To: node.Version}
+		switch result := maven.CompareVersions(previous.Version, node.Version); {
+		case result < 0:
+			diff.Upgraded = append(diff.Upgraded, change)
+		case result > 0:
+			diff.Downgraded = append(diff.Downgraded, change)
+		}
+	}
+	for key, node := range oldNodes {
+		if _, exists := newNodes[key]; !exists {
+			diff.Removed = append(diff.Removed, node)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].ID < diff.Added[j].ID })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].ID < diff.Removed[j].ID })
+	sort.Slice(diff.Upgraded, func(i, j int) bool { return diff.Upgraded[i].String() < diff.Upgraded[j].String() })
+	sort.Slice(diff.Downgraded, func(i, j int) bool { return diff.Downgraded[i].String() < diff.Downgraded[j].String() })
+	return diff
+}
+
+// nodesByArtifact keeps the first node of each artifact, graphs written by hand possibly holding several versions.
+// Omitted nodes aren't part of the resolution.
+func nodesByArtifact(graph *Graph) map[string]*GraphNode {
+	nodes := map[string]*GraphNode{}
+	for _, node := range graph.Nodes {
+		if node.Omitted {
+			continue
+		}
+		key := node.GroupID + ":" + node.ArtifactID
+		if _, isKnown := nodes[key]; !isKnown {
+			nodes[key] = node
+		}
+	}
+	return nodes
+}
+
+// DiffWriter prints a GraphDiff either as text for reviewers, or as JSON for tools.
+type DiffWriter struct {
+	out    io.Writer
+	format string
+}
+
+func NewDiffWriter(w io.Writer, format string) (*DiffWriter, error) {
+	if format != TextFormat && format != JSONFormat {
+		return nil, errors.Errorf("unknown diff format [%s], expected one of : %s, %s", format, JSONFormat, TextFormat)
+	}
+	return &DiffWriter{out: w, format: format}, nil
+}
+
+func (w *DiffWriter) Write(diff *GraphDiff) error {
+	if w.format == JSONFormat {
+		encoder := json.NewEncoder(w.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+
+	b := &strings.Builder{}
+	for _, node := range diff.Added {
+		fmt.Fprintf(b, "+ %s\n", node.ID)
+	}
+	for _, node := range diff.Removed {
+		fmt.Fprintf(b, "- %s\n", node.ID)
+	}
+	for _, change := range diff.Upgraded {
+		fmt.Fprintf(b, "^ %s\n", change)
+	}
+	for _, change := range diff.Downgraded {
+		fmt.Fprintf(b, "v %s (downgrade)\n", change)
+	}
+	fmt.Fprintf(b, "%d added, %d removed, %d upgraded, %d downgraded\n",
+		len(diff.Added), len(diff.Removed), len(diff.Upgraded), len(diff.Downgraded))
+	_, err := io.WriteString(w.out, b.String())
+	return err
+}
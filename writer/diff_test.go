@@ -0,0 +1,102 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("DiffGraphs", func() {
+	var (
+		err    error
+		out    *gbytes.Buffer
+		format string
+		diff   *GraphDiff
+	)
+
+	node := func(groupID string, artifactID string, version string) *GraphNode {
+		return &GraphNode{ID: groupID + ":" + artifactID + ":" + version, GroupID: groupID, ArtifactID: artifactID,
+			Version: version}
+	}
+
+	BeforeEach(func() {
+		out = gbytes.NewBuffer()
+		format = TextFormat
+
+		old := &Graph{Version: GraphSchemaVersion, Nodes: []*GraphNode{
+			node("org.fake", "root", "1.0"),
+			node("org.fake", "removed", "1.0"),
+			node("org.fake", "upgraded", "1.0-rc1"),
+			node("org.fake", "downgraded", "2.0.10"),
+			node("org.fake", "unchanged", "1.0"),
+		}}
+		new := &Graph{Version: GraphSchemaVersion, Nodes: []*GraphNode{
+			node("org.fake", "root", "1.1"),
+			node("org.fake", "unchanged", "1.0.0"),
+			node("org.fake", "downgraded", "2.0.9"),
+			node("org.fake", "upgraded", "1.0"),
+			node("org.fake", "added", "3.0"),
+		}}
+		diff = DiffGraphs(old, new)
+	})
+
+	It("should match artifacts whatever their version, comparing versions like Maven", func() {
+		Expect(diff.Added).To(Equal([]*GraphNode{node("org.fake", "added", "3.0")}))
+		Expect(diff.Removed).To(Equal([]*GraphNode{node("org.fake", "removed", "1.0")}))
+		Expect(diff.Upgraded).To(Equal([]*VersionChange{
+			{GroupID: "org.fake", ArtifactID: "root", From: "1.0", To: "1.1"},
+			{GroupID: "org.fake", ArtifactID: "upgraded", From: "1.0-rc1", To: "1.0"},
+		}))
+		Expect(diff.Downgraded).To(Equal([]*VersionChange{
+			{GroupID: "org.fake", ArtifactID: "downgraded", From: "2.0.10", To: "2.0.9"},
+		}))
+	})
+
+	Describe("DiffWriter", func() {
+		JustBeforeEach(func() {
+			var writer *DiffWriter
+			writer, err = NewDiffWriter(out, format)
+			if err == nil {
+				err = writer.Write(diff)
+			}
+		})
+
+		It("should print a report for humans", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(`+ org.fake:added:3.0
+- org.fake:removed:1.0
+^ org.fake:root 1.0 -> 1.1
+^ org.fake:upgraded 1.0-rc1 -> 1.0
+v org.fake:downgraded 2.0.10 -> 2.0.9 (downgrade)
+1 added, 1 removed, 2 upgraded, 1 downgraded
+`))
+		})
+
+		Context("given the JSON format", func() {
+			BeforeEach(func() {
+				format = JSONFormat
+			})
+
+			It("should print a report for tools", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(out.Contents())).To(ContainSubstring(`"downgraded": [
+    {
+      "groupId": "org.fake",
+      "artifactId": "downgraded",
+      "from": "2.0.10",
+      "to": "2.0.9"
+    }
+  ]`))
+			})
+		})
+	})
+
+	Describe("ReadGraph", func() {
+		It("should refuse graphs of another schema version", func() {
+			_, err = ReadGraph([]byte(`{"version": 2, "nodes": []}`))
+			Expect(err).To(MatchError("unsupported dependency graph schema version [2], expected 1"))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package writer
+
+import (
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var _ GraphWriter = &DOTWriter{}
+
+// DOTWriter exports the dependency graph in the DOT language of Graphviz, labelling dependencies with their scope.
+type DOTWriter struct {
+	out io.Writer
+}
+
+func NewDOTWriter(w io.Writer) *DOTWriter {
+	return &DOTWriter{out: w}
+}
+
+func (w *DOTWriter) Write(root *maven.Node) error {
+	graph := NewGraph(root)
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "digraph %s {\n", strconv.Quote(graph.Root))
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range graph.Nodes {
+		attrs := ""
+		switch {
+		case node.Omitted:
+			attrs = " [style=dotted]"
+		case node.Scope == maven.ScopeTest || node.Scope == maven.ScopeProvided:
+			attrs = " [style=dashed]"
+		}
+		fmt.Fprintf(b, "  %s%s;\n", strconv.Quote(node.ID), attrs)
+	}
+	for _, edge := range graph.Edges {
+		attrs := ""
+		if edge.Optional {
+			attrs = ", style=dotted"
+		}
+		fmt.Fprintf(b, "  %s -> %s [label=%s%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To),
+			strconv.Quote(edge.Scope), attrs)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w.out, b.String())
+	return err
+}
@@ -0,0 +1,177 @@
+package writer
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GraphSchemaVersion is bumped whenever fields of the JSON export change meaning or go away.
+const GraphSchemaVersion = 1
+
+const (
+	DOTFormat     = "dot"
+	JSONFormat    = "json"
+	GraphMLFormat = "graphml"
+)
+
+var graphFormats = map[string]func(w io.Writer) GraphWriter{
+	DOTFormat:     func(w io.Writer) GraphWriter { return NewDOTWriter(w) },
+	JSONFormat:    func(w io.Writer) GraphWriter { return NewJSONWriter(w) },
+	GraphMLFormat: func(w io.Writer) GraphWriter { return NewGraphMLWriter(w) },
+}
+
+// NewGraphWriter creates the writer exporting the dependency graph in one of the graph formats.
+func NewGraphWriter(w io.Writer, format string) (GraphWriter, error) {
+	newWriter, ok := graphFormats[format]
+	if !ok {
+		return nil, errors.Errorf("unknown graph format [%s], expected one of : %s",
+			format, strings.Join(GraphFormatNames(), ", "))
+	}
+	return newWriter(w), nil
+}
+
+func GraphFormatNames() []string {
+	names := make([]string, 0, len(graphFormats))
+	for name := range graphFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Graph is the dependency graph of an artifact flattened into nodes and edges, as exported in the graph formats.
+type Graph struct {
+	Version int          `json:"version"`
+	Root    string       `json:"root"`
+	Nodes   []*GraphNode `json:"nodes"`
+	Edges   []*GraphEdge `json:"edges"`
+}
+
+// GraphNode is an artifact of the graph, identified by its Maven coordinates.
+type GraphNode struct {
+	ID         string `json:"id"`
+	GroupID    string `json:"groupId"`
+	ArtifactID string `json:"artifactId"`
+	Version    string `json:"version"`
+	Scope      string `json:"scope"`
+	Repository string `json:"repository"`
+	SHA1       string `json:"sha1"`
+	// Forced tells the version was forced by an override.
+	Forced bool `json:"forced"`
+	// Omitted tells the artifact is only declared as an optional dependency, and isn't part of the resolution.
+	Omitted bool `json:"omitted"`
+}
+
+// GraphEdge is a dependency of an artifact of the graph on another.
+type GraphEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Scope    string `json:"scope"`
+	Optional bool   `json:"optional"`
+}
+
+// NewGraph flattens a resolved dependency graph, in the order the artifacts were discovered.
+func NewGraph(root *maven.Node) *Graph {
+	graph := &Graph{
+		Version: GraphSchemaVersion,
+		Root:    root.Artifact.GetMavenCoords(),
+		Nodes:   make([]*GraphNode, 0),
+		Edges:   make([]*GraphEdge, 0),
+	}
+	for _, a := range resolvedArtifacts(root) {
+		graph.Nodes = append(graph.Nodes, &GraphNode{
+			ID:         a.GetMavenCoords(),
+			GroupID:    a.GroupID,
+			ArtifactID: a.ArtifactID,
+			Version:    a.Version,
+			Scope:      a.Scope,
+			Repository: a.Repository,
+			SHA1:       a.SHA,
+			Forced:     a.Forced,
+		})
+	}
+	known := map[string]bool{}
+	for _, node := range graph.Nodes {
+		known[node.ID] = true
+	}
+	for _, edge := range dependencyEdges(root) {
+		to := edge.to.GetMavenCoords()
+		if !known[to] {
+			known[to] = true
+			graph.Nodes = append(graph.Nodes, &GraphNode{
+				ID:         to,
+				GroupID:    edge.to.GroupID,
+				ArtifactID: edge.to.ArtifactID,
+				Version:    edge.to.Version,
+				Scope:      edge.scope,
+				Omitted:    true,
+			})
+		}
+		graph.Edges = append(graph.Edges, &GraphEdge{
+			From:     edge.from.GetMavenCoords(),
+			To:       to,
+			Scope:    edge.scope,
+			Optional: edge.optional,
+		})
+	}
+	return graph
+}
+
+// dependencyEdge is a dependency of an artifact of a resolved graph on another, with the scope it was resolved with.
+// Optional dependencies point to the artifact as declared, which isn't resolved unless another dependency asks for it.
+type dependencyEdge struct {
+	from     *maven.Artifact
+	to       *maven.Artifact
+	scope    string
+	optional bool
+}
+
+// resolvedArtifacts lists the artifacts of a resolved graph, in the order they were discovered.
+func resolvedArtifacts(root *maven.Node) []*maven.Artifact {
+	artifacts := make([]*maven.Artifact, 0)
+	root.Walk(func(node *maven.Node, _ int) bool {
+		if node.IsOmitted() {
+			return false
+		}
+		artifacts = append(artifacts, node.Artifact)
+		return true
+	})
+	return artifacts
+}
+
+// dependencyEdges lists every dependency of the artifacts of a resolved graph. Artifact.Dependencies only holds an
+// artifact under the first dependent resolving it, so dependencies omitted as duplicates or conflict losers point to
+// the artifact resolved instead. Excluded dependencies are no dependencies at all, while optional ones are kept.
+func dependencyEdges(root *maven.Node) []*dependencyEdge {
+	edges := make([]*dependencyEdge, 0)
+	seen := map[string]bool{}
+	root.Walk(func(node *maven.Node, _ int) bool {
+		if node.IsOmitted() {
+			return false
+		}
+		for _, child := range node.Children {
+			to := child
+			switch child.Omitted {
+			case maven.OmittedDuplicate, maven.OmittedConflict:
+				to = child.Winner
+			case maven.OmittedExcluded:
+				to = nil
+			}
+			if to == nil {
+				continue
+			}
+			key := node.Artifact.GetMavenCoords() + " -> " + to.Artifact.GetMavenCoords()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			edges = append(edges, &dependencyEdge{from: node.Artifact, to: to.Artifact, scope: child.Scope,
+				optional: child.Omitted == maven.OmittedOptional})
+		}
+		return true
+	})
+	return edges
+}
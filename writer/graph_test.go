@@ -0,0 +1,190 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Graph exports", func() {
+	var (
+		err    error
+		out    *gbytes.Buffer
+		writer GraphWriter
+		format string
+		root   *maven.Node
+	)
+
+	BeforeEach(func() {
+		out = gbytes.NewBuffer()
+		dep := &maven.Artifact{
+			GroupID:    "fake.org",
+			ArtifactID: "another-artifact",
+			Version:    "2.0.3",
+			Scope:      "test",
+			Repository: "http://otherhost",
+			SHA:        "def456",
+		}
+		pom := &maven.Artifact{
+			GroupID:      "org.fake",
+			ArtifactID:   "some-artifact",
+			Version:      "0.0.1",
+			Repository:   "http://localhost",
+			SHA:          "abc123",
+			Dependencies: []*maven.Artifact{dep},
+		}
+		root = &maven.Node{Artifact: pom, Children: []*maven.Node{{Artifact: dep, Scope: "test"}}}
+	})
+
+	JustBeforeEach(func() {
+		writer, err = NewGraphWriter(out, format)
+		if err == nil {
+			err = writer.Write(root)
+		}
+	})
+
+	Context("given the JSON format", func() {
+		BeforeEach(func() {
+			format = JSONFormat
+		})
+
+		It("should export nodes and edges following the schema", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			graph := &Graph{}
+			Expect(json.Unmarshal(out.Contents(), graph)).To(Succeed())
+			Expect(graph).To(Equal(&Graph{
+				Version: GraphSchemaVersion,
+				Root:    "org.fake:some-artifact:0.0.1",
+				Nodes: []*GraphNode{
+					{ID: "org.fake:some-artifact:0.0.1", GroupID: "org.fake", ArtifactID: "some-artifact",
+						Version: "0.0.1", Repository: "http://localhost", SHA1: "abc123"},
+					{ID: "fake.org:another-artifact:2.0.3", GroupID: "fake.org", ArtifactID: "another-artifact",
+						Version: "2.0.3", Scope: "test", Repository: "http://otherhost", SHA1: "def456"},
+				},
+				Edges: []*GraphEdge{
+					{From: "org.fake:some-artifact:0.0.1", To: "fake.org:another-artifact:2.0.3", Scope: "test"},
+				},
+			}))
+			Expect(string(out.Contents())).To(ContainSubstring(`"optional": false`))
+		})
+
+		Context("given an optional dependency", func() {
+			BeforeEach(func() {
+				root.Children[0].Children = []*maven.Node{{
+					Artifact: &maven.Artifact{GroupID: "fake.org", ArtifactID: "optional-artifact", Version: "1.0",
+						Optional: true},
+					Scope:   "test",
+					Omitted: maven.OmittedOptional,
+				}}
+			})
+
+			It("should export an optional edge to an omitted node", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				graph := &Graph{}
+				Expect(json.Unmarshal(out.Contents(), graph)).To(Succeed())
+				Expect(graph.Nodes).To(HaveLen(3))
+				Expect(graph.Nodes[2]).To(Equal(&GraphNode{ID: "fake.org:optional-artifact:1.0", GroupID: "fake.org",
+					ArtifactID: "optional-artifact", Version: "1.0", Scope: "test", Omitted: true}))
+				Expect(graph.Edges[1]).To(Equal(&GraphEdge{From: "fake.org:another-artifact:2.0.3",
+					To: "fake.org:optional-artifact:1.0", Scope: "test", Optional: true}))
+			})
+		})
+
+		Context("given a dependency shared by several artifacts", func() {
+			BeforeEach(func() {
+				artifact := func(artifactID string, version string) *maven.Artifact {
+					return &maven.Artifact{GroupID: "org.fake", ArtifactID: artifactID, Version: version,
+						Scope: "compile"}
+				}
+				c := &maven.Node{Artifact: artifact("c", "2"), Scope: "compile", Children: []*maven.Node{
+					{Artifact: artifact("e", "1"), Scope: "compile", Omitted: maven.OmittedExcluded},
+				}}
+				root = &maven.Node{Artifact: artifact("root", "1"), Children: []*maven.Node{
+					{Artifact: artifact("a", "1"), Scope: "compile", Children: []*maven.Node{c}},
+					{Artifact: artifact("b", "1"), Scope: "compile", Children: []*maven.Node{
+						{Artifact: artifact("c", "2"), Scope: "runtime", Omitted: maven.OmittedDuplicate, Winner: c},
+					}},
+					{Artifact: artifact("d", "1"), Scope: "compile", Children: []*maven.Node{
+						{Artifact: artifact("c", "1"), Scope: "compile", Omitted: maven.OmittedConflict, Winner: c},
+					}},
+				}}
+			})
+
+			It("should keep the edge of every dependent to the artifact resolved", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				graph := &Graph{}
+				Expect(json.Unmarshal(out.Contents(), graph)).To(Succeed())
+				ids := make([]string, 0)
+				for _, node := range graph.Nodes {
+					ids = append(ids, node.ID)
+				}
+				Expect(ids).To(Equal([]string{"org.fake:root:1", "org.fake:a:1", "org.fake:c:2", "org.fake:b:1",
+					"org.fake:d:1"}))
+				Expect(graph.Edges).To(Equal([]*GraphEdge{
+					{From: "org.fake:root:1", To: "org.fake:a:1", Scope: "compile"},
+					{From: "org.fake:root:1", To: "org.fake:b:1", Scope: "compile"},
+					{From: "org.fake:root:1", To: "org.fake:d:1", Scope: "compile"},
+					{From: "org.fake:a:1", To: "org.fake:c:2", Scope: "compile"},
+					{From: "org.fake:b:1", To: "org.fake:c:2", Scope: "runtime"},
+					{From: "org.fake:d:1", To: "org.fake:c:2", Scope: "compile"},
+				}))
+			})
+		})
+	})
+
+	Context("given the DOT format", func() {
+		BeforeEach(func() {
+			format = DOTFormat
+		})
+
+		It("should export a Graphviz digraph", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(`digraph "org.fake:some-artifact:0.0.1" {
+  node [shape=box];
+  "org.fake:some-artifact:0.0.1";
+  "fake.org:another-artifact:2.0.3" [style=dashed];
+  "org.fake:some-artifact:0.0.1" -> "fake.org:another-artifact:2.0.3" [label="test"];
+}
+`))
+		})
+	})
+
+	Context("given the GraphML format", func() {
+		BeforeEach(func() {
+			format = GraphMLFormat
+		})
+
+		It("should export a directed graph with the fields as data", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			contents := string(out.Contents())
+			Expect(contents).To(HavePrefix(`<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`))
+			Expect(contents).To(ContainSubstring(`<key id="sha1" for="node" attr.name="sha1" attr.type="string"></key>`))
+			Expect(contents).To(ContainSubstring(`<graph id="org.fake:some-artifact:0.0.1" edgedefault="directed">`))
+			Expect(contents).To(ContainSubstring(`<node id="fake.org:another-artifact:2.0.3">
+      <data key="groupId">fake.org</data>`))
+			Expect(contents).To(ContainSubstring(`<edge source="org.fake:some-artifact:0.0.1" target="fake.org:another-artifact:2.0.3">
+      <data key="dependencyScope">test</data>
+      <data key="optional">false</data>
+    </edge>`))
+		})
+	})
+
+	Context("given an unknown format", func() {
+		BeforeEach(func() {
+			format = "svg"
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(MatchError("unknown graph format [svg], expected one of : dot, graphml, json"))
+		})
+	})
+})
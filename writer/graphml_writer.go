@@ -0,0 +1,108 @@
+package writer
+
+import (
+	"encoding/xml"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"io"
+	"strconv"
+)
+
+const graphMLNamespace = `http://graphml.graphdrawing.org/xmlns`
+
+var _ GraphWriter = &GraphMLWriter{}
+
+// GraphMLWriter exports the dependency graph as GraphML, e.g. to lay it out with yEd. Fields of the nodes and edges
+// are exported as data, which yEd can map to labels.
+type GraphMLWriter struct {
+	out io.Writer
+}
+
+func NewGraphMLWriter(w io.Writer) *GraphMLWriter {
+	return &GraphMLWriter{out: w}
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+var graphMLKeys = []graphMLKey{
+	{ID: "groupId", For: "node", Name: "groupId", Type: "string"},
+	{ID: "artifactId", For: "node", Name: "artifactId", Type: "string"},
+	{ID: "version", For: "node", Name: "version", Type: "string"},
+	{ID: "scope", For: "node", Name: "scope", Type: "string"},
+	{ID: "repository", For: "node", Name: "repository", Type: "string"},
+	{ID: "sha1", For: "node", Name: "sha1", Type: "string"},
+	{ID: "omitted", For: "node", Name: "omitted", Type: "boolean"},
+	{ID: "dependencyScope", For: "edge", Name: "scope", Type: "string"},
+	{ID: "optional", For: "edge", Name: "optional", Type: "boolean"},
+}
+
+func (w *GraphMLWriter) Write(root *maven.Node) error {
+	graph := NewGraph(root)
+	doc := graphML{
+		XMLNS: graphMLNamespace,
+		Keys:  graphMLKeys,
+		Graph: graphMLGraph{ID: graph.Root, EdgeDefault: "directed"},
+	}
+	for _, node := range graph.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.ID, Data: []graphMLData{
+			{Key: "groupId", Value: node.GroupID},
+			{Key: "artifactId", Value: node.ArtifactID},
+			{Key: "version", Value: node.Version},
+			{Key: "scope", Value: node.Scope},
+			{Key: "repository", Value: node.Repository},
+			{Key: "sha1", Value: node.SHA1},
+			{Key: "omitted", Value: strconv.FormatBool(node.Omitted)},
+		}})
+	}
+	for _, edge := range graph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: edge.From, Target: edge.To, Data: []graphMLData{
+			{Key: "dependencyScope", Value: edge.Scope},
+			{Key: "optional", Value: strconv.FormatBool(edge.Optional)},
+		}})
+	}
+
+	if _, err := io.WriteString(w.out, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w.out)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.out, "\n")
+	return err
+}
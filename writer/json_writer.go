@@ -0,0 +1,24 @@
+package writer
+
+import (
+	"encoding/json"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"io"
+)
+
+var _ GraphWriter = &JSONWriter{}
+
+// JSONWriter exports the dependency graph as JSON, following the schema of Graph.
+type JSONWriter struct {
+	out io.Writer
+}
+
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{out: w}
+}
+
+func (w *JSONWriter) Write(root *maven.Node) error {
+	encoder := json.NewEncoder(w.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewGraph(root))
+}
@@ -0,0 +1,83 @@
+package writer
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"sort"
+)
+
+const licenseRule = `license`
+const rulesLicenseModule = `@rules_license//rules:license.bzl`
+const spdxLicenseKinds = `@rules_license//licenses/spdx:`
+
+// bazelLicenseTypes maps SPDX identifiers to the license types accepted by the legacy `licenses` attribute of rules.
+var bazelLicenseTypes = map[string]string{
+	"Apache-2.0":                       "notice",
+	"Apache-1.1":                       "notice",
+	"MIT":                              "notice",
+	"BSD-2-Clause":                     "notice",
+	"BSD-3-Clause":                     "notice",
+	"ISC":                              "notice",
+	"EPL-1.0":                          "reciprocal",
+	"EPL-2.0":                          "reciprocal",
+	"MPL-1.1":                          "reciprocal",
+	"MPL-2.0":                          "reciprocal",
+	"CDDL-1.0":                         "reciprocal",
+	"CDDL-1.1":                         "reciprocal",
+	"LGPL-2.1-only":                    "restricted",
+	"LGPL-3.0-only":                    "restricted",
+	"GPL-2.0-only":                     "restricted",
+	"GPL-2.0-with-classpath-exception": "restricted",
+	"GPL-3.0-only":                     "restricted",
+	"CC0-1.0":                          "unencumbered",
+	"Unlicense":                        "unencumbered",
+}
+
+// spdxIDs lists the recognized licenses of an artifact, in declaration order.
+func spdxIDs(artifact *maven.Artifact) []string {
+	ids := make([]string, 0, len(artifact.Licenses))
+	seen := map[string]bool{}
+	for _, license := range artifact.Licenses {
+		if id := license.SPDXID(); id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// licenseTypes lists the Bazel license types of an artifact, those of unrecognized licenses being left out.
+func licenseTypes(artifact *maven.Artifact) []string {
+	types := make([]string, 0, len(artifact.Licenses))
+	seen := map[string]bool{}
+	for _, id := range spdxIDs(artifact) {
+		if licenseType := bazelLicenseTypes[id]; !seen[licenseType] {
+			seen[licenseType] = true
+			types = append(types, licenseType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+func licenseRuleName(artifact *maven.Artifact, names *RuleNames) string {
+	return names.Get(artifact) + "_license"
+}
+
+// newLicenseRule builds the `rules_license` target describing the licenses of an artifact, nil if none of them is
+// recognized.
+func newLicenseRule(artifact *maven.Artifact, names *RuleNames) *Rule {
+	ids := spdxIDs(artifact)
+	if len(ids) < 1 {
+		return nil
+	}
+	kinds := &ListExpr{}
+	for _, id := range ids {
+		kinds.Values = append(kinds.Values, &StringExpr{Value: spdxLicenseKinds + id})
+	}
+	return &Rule{Kind: licenseRule, Attrs: []*Attr{
+		{Name: "name", Value: &StringExpr{Value: licenseRuleName(artifact, names)}},
+		{Name: "license_kinds", Value: kinds},
+		{Name: "package_name", Value: &StringExpr{Value: artifact.GetVersionlessCoords()}},
+		{Name: "package_version", Value: &StringExpr{Value: artifact.Version}},
+	}}
+}
@@ -0,0 +1,135 @@
+package writer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const CSVFormat = "csv"
+
+var _ Writer = &LicenseWriter{}
+
+// LicenseReportEntry is an artifact of the dependency graph along with the licenses its POM declared.
+type LicenseReportEntry struct {
+	Coordinates string                  `json:"coordinates"`
+	GroupID     string                  `json:"groupId"`
+	ArtifactID  string                  `json:"artifactId"`
+	Version     string                  `json:"version"`
+	Licenses    []*LicenseReportLicense `json:"licenses"`
+}
+
+// LicenseReportLicense is a declared license, SPDXID being empty if the license wasn't recognized.
+type LicenseReportLicense struct {
+	SPDXID string `json:"spdxId"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+}
+
+// LicenseWriter reports the licenses of every artifact of the dependency graph, as a table for reviewers, or as CSV
+// or JSON for spreadsheets and tools.
+type LicenseWriter struct {
+	out    io.Writer
+	format string
+}
+
+func NewLicenseWriter(w io.Writer, format string) (*LicenseWriter, error) {
+	if format != TextFormat && format != CSVFormat && format != JSONFormat {
+		return nil, errors.Errorf("unknown license report format [%s], expected one of : %s, %s, %s",
+			format, CSVFormat, JSONFormat, TextFormat)
+	}
+	return &LicenseWriter{out: w, format: format}, nil
+}
+
+// NewLicenseReport lists the artifacts of the dependency graph sorted by coordinates.
+func NewLicenseReport(artifact *maven.Artifact) []*LicenseReportEntry {
+	entries := make([]*LicenseReportEntry, 0)
+	for _, a := range uniqueArtifacts(artifact) {
+		entry := &LicenseReportEntry{
+			Coordinates: a.GetMavenCoords(),
+			GroupID:     a.GroupID,
+			ArtifactID:  a.ArtifactID,
+			Version:     a.Version,
+			Licenses:    make([]*LicenseReportLicense, 0, len(a.Licenses)),
+		}
+		for _, license := range a.Licenses {
+			entry.Licenses = append(entry.Licenses, &LicenseReportLicense{
+				SPDXID: license.SPDXID(),
+				Name:   license.Name,
+				URL:    license.URL,
+			})
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Coordinates < entries[j].Coordinates })
+	return entries
+}
+
+func (w *LicenseWriter) Write(artifact *maven.Artifact) error {
+	entries := NewLicenseReport(artifact)
+	switch w.format {
+	case JSONFormat:
+		encoder := json.NewEncoder(w.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case CSVFormat:
+		return w.writeCSV(entries)
+	}
+	return w.writeText(entries)
+}
+
+func (w *LicenseWriter) writeCSV(entries []*LicenseReportEntry) error {
+	out := csv.NewWriter(w.out)
+	out.Write([]string{"groupId", "artifactId", "version", "spdxId", "name", "url"})
+	for _, entry := range entries {
+		if len(entry.Licenses) < 1 {
+			out.Write([]string{entry.GroupID, entry.ArtifactID, entry.Version, "", "", ""})
+		}
+		for _, license := range entry.Licenses {
+			out.Write([]string{entry.GroupID, entry.ArtifactID, entry.Version, license.SPDXID, license.Name, license.URL})
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// writeText prints a table of the licenses of each artifact, flagging those which need a closer look.
+func (w *LicenseWriter) writeText(entries []*LicenseReportEntry) error {
+	out := tabwriter.NewWriter(w.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "ARTIFACT\tLICENSES")
+	unrecognized := 0
+	for _, entry := range entries {
+		licenses := make([]string, 0, len(entry.Licenses))
+		recognized := len(entry.Licenses) > 0
+		for _, license := range entry.Licenses {
+			if license.SPDXID != "" {
+				licenses = append(licenses, license.SPDXID)
+				continue
+			}
+			recognized = false
+			description := license.Name
+			if description == "" {
+				description = license.URL
+			}
+			licenses = append(licenses, description+" (unrecognized)")
+		}
+		if len(licenses) < 1 {
+			licenses = append(licenses, "(none declared)")
+		}
+		if !recognized {
+			unrecognized++
+		}
+		fmt.Fprintf(out, "%s\t%s\n", entry.Coordinates, strings.Join(licenses, ", "))
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w.out, "%d artifact(s), %d without recognized licenses\n", len(entries), unrecognized)
+	return err
+}
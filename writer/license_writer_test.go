@@ -0,0 +1,112 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("LicenseWriter", func() {
+	var (
+		err    error
+		out    *gbytes.Buffer
+		format string
+		pom    *maven.Artifact
+	)
+
+	BeforeEach(func() {
+		out = gbytes.NewBuffer()
+		pom = &maven.Artifact{
+			GroupID:    "org.fake",
+			ArtifactID: "root",
+			Version:    "1.0",
+			Licenses:   []maven.License{{Name: "The Apache Software License, Version 2.0"}},
+			Dependencies: []*maven.Artifact{
+				{GroupID: "org.fake", ArtifactID: "b", Version: "2.0"},
+				{GroupID: "org.fake", ArtifactID: "a", Version: "1.1", Licenses: []maven.License{
+					{Name: "EPL 1.0", URL: "http://www.eclipse.org/legal/epl-v10.html"},
+					{Name: "Some Company License", URL: "https://example.com/license"},
+				}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var writer *LicenseWriter
+		writer, err = NewLicenseWriter(out, format)
+		if err == nil {
+			err = writer.Write(pom)
+		}
+	})
+
+	AfterEach(func() {
+		out.Close()
+	})
+
+	Context("writing text", func() {
+		BeforeEach(func() {
+			format = TextFormat
+		})
+
+		It("should print a table flagging artifacts without recognized licenses", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(
+				`ARTIFACT           LICENSES
+org.fake:a:1.1     EPL-1.0, Some Company License (unrecognized)
+org.fake:b:2.0     (none declared)
+org.fake:root:1.0  Apache-2.0
+3 artifact(s), 2 without recognized licenses
+`))
+		})
+	})
+
+	Context("writing CSV", func() {
+		BeforeEach(func() {
+			format = CSVFormat
+		})
+
+		It("should write a row per license", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(
+				`groupId,artifactId,version,spdxId,name,url
+org.fake,a,1.1,EPL-1.0,EPL 1.0,http://www.eclipse.org/legal/epl-v10.html
+org.fake,a,1.1,,Some Company License,https://example.com/license
+org.fake,b,2.0,,,
+org.fake,root,1.0,Apache-2.0,"The Apache Software License, Version 2.0",
+`))
+		})
+	})
+
+	Context("writing JSON", func() {
+		BeforeEach(func() {
+			format = JSONFormat
+		})
+
+		It("should list every artifact with its licenses", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.Contents()).To(MatchJSON(`[
+				{"coordinates": "org.fake:a:1.1", "groupId": "org.fake", "artifactId": "a", "version": "1.1", "licenses": [
+					{"spdxId": "EPL-1.0", "name": "EPL 1.0", "url": "http://www.eclipse.org/legal/epl-v10.html"},
+					{"spdxId": "", "name": "Some Company License", "url": "https://example.com/license"}
+				]},
+				{"coordinates": "org.fake:b:2.0", "groupId": "org.fake", "artifactId": "b", "version": "2.0", "licenses": []},
+				{"coordinates": "org.fake:root:1.0", "groupId": "org.fake", "artifactId": "root", "version": "1.0", "licenses": [
+					{"spdxId": "Apache-2.0", "name": "The Apache Software License, Version 2.0", "url": ""}
+				]}
+			]`))
+		})
+	})
+
+	Context("given an unknown format", func() {
+		BeforeEach(func() {
+			format = "xlsx"
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(MatchError("unknown license report format [xlsx], expected one of : csv, json, text"))
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package writer
+
+import (
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	LegacyNaming           = "legacy"
+	RulesJVMExternalNaming = "rules_jvm_external"
+	VersionedNaming        = "versioned"
+)
+
+var illegalRuleNameChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// NamingScheme decides which Bazel rule name an artifact is referred to by.
+type NamingScheme interface {
+	RuleName(artifact *maven.Artifact) string
+}
+
+type NamingSchemeFunc func(artifact *maven.Artifact) string
+
+func (f NamingSchemeFunc) RuleName(artifact *maven.Artifact) string {
+	return f(artifact)
+}
+
+var namingSchemes = map[string]NamingScheme{
+	// only replaces `.` in the group ID and `-` in the artifact ID, as this tool always has
+	LegacyNaming: NamingSchemeFunc(func(a *maven.Artifact) string {
+		return a.GetBazelRule()
+	}),
+	// same as the names `rules_jvm_external` generates for `@maven//:group_artifact`
+	RulesJVMExternalNaming: NamingSchemeFunc(func(a *maven.Artifact) string {
+		return sanitizeRuleName(a.GroupID + "_" + a.ArtifactID)
+	}),
+	VersionedNaming: NamingSchemeFunc(func(a *maven.Artifact) string {
+		return sanitizeRuleName(a.GroupID + "_" + a.ArtifactID + "_" + a.Version)
+	}),
+}
+
+func NewNamingScheme(name string) (NamingScheme, error) {
+	scheme, ok := namingSchemes[name]
+	if !ok {
+		return nil, errors.Errorf("unknown naming scheme [%s], expected one of : %s",
+			name, strings.Join(NamingSchemeNames(), ", "))
+	}
+	return scheme, nil
+}
+
+func NamingSchemeNames() []string {
+	names := make([]string, 0, len(namingSchemes))
+	for name := range namingSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sanitizeRuleName(name string) string {
+	name = illegalRuleNameChars.ReplaceAllString(name, "_")
+	// repository names have to start with a letter
+	if name == "" || !isLetter(name[0]) {
+		name = "maven_" + name
+	}
+	return name
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// RuleNames holds the rule name assigned to every artifact of a dependency graph.
+type RuleNames struct {
+	names map[string]string
+}
+
+// AssignRuleNames names every artifact in the graph of `artifact`, failing if two different artifacts would end up
+// with the same rule name.
+func AssignRuleNames(scheme NamingScheme, artifact *maven.Artifact) (*RuleNames, error) {
+	names := &RuleNames{names: map[string]string{}}
+	owners := map[string]*maven.Artifact{}
+
+	for _, a := range collectArtifacts(artifact) {
+		name := scheme.RuleName(a)
+		if owner, taken := owners[name]; taken {
+			if owner.GetVersionlessCoords() == a.GetVersionlessCoords() {
+				continue
+			}
+			return nil, errors.New(fmt.Sprintf(
+				"rule name [%s] is shared by artifacts [%s] and [%s], try a different naming scheme",
+				name, owner.GetMavenCoords(), a.GetMavenCoords()))
+		}
+		owners[name] = a
+		names.names[a.GetVersionlessCoords()] = name
+	}
+
+	return names, nil
+}
+
+func (n *RuleNames) Get(artifact *maven.Artifact) string {
+	return n.names[artifact.GetVersionlessCoords()]
+}
+
+func (n *RuleNames) getAll(artifacts []*maven.Artifact) []string {
+	names := make([]string, 0, len(artifacts))
+	for _, a := range artifacts {
+		names = append(names, n.Get(a))
+	}
+	return names
+}
@@ -0,0 +1,131 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+)
+
+var _ = Describe("Naming", func() {
+	var (
+		err      error
+		scheme   NamingScheme
+		artifact *maven.Artifact
+	)
+
+	BeforeEach(func() {
+		artifact = &maven.Artifact{
+			GroupID:    "com.foo",
+			ArtifactID: "bar-baz.qux",
+			Version:    "1.0+build",
+		}
+	})
+
+	Context("Given an unknown naming scheme", func() {
+		It("should return a meaningful error", func() {
+			_, err = NewNamingScheme("nope")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"unknown naming scheme [nope], expected one of : legacy, rules_jvm_external, versioned"))
+		})
+	})
+
+	Context("Given the legacy naming scheme", func() {
+		BeforeEach(func() {
+			scheme, err = NewNamingScheme(LegacyNaming)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should name rules as this tool always has", func() {
+			Expect(scheme.RuleName(artifact)).To(Equal("com_foo_bar_baz.qux"))
+		})
+	})
+
+	Context("Given the rules_jvm_external naming scheme", func() {
+		BeforeEach(func() {
+			scheme, err = NewNamingScheme(RulesJVMExternalNaming)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should only produce legal rule names", func() {
+			Expect(scheme.RuleName(artifact)).To(Equal("com_foo_bar_baz_qux"))
+		})
+
+		It("should make sure rule names start with a letter", func() {
+			artifact.GroupID = "3com"
+			Expect(scheme.RuleName(artifact)).To(Equal("maven_3com_bar_baz_qux"))
+		})
+	})
+
+	Context("Given the versioned naming scheme", func() {
+		BeforeEach(func() {
+			scheme, err = NewNamingScheme(VersionedNaming)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should suffix rule names with the version", func() {
+			Expect(scheme.RuleName(artifact)).To(Equal("com_foo_bar_baz_qux_1_0_build"))
+		})
+	})
+
+	Context("Given a dependency graph to name", func() {
+		var (
+			names *RuleNames
+		)
+
+		BeforeEach(func() {
+			scheme, err = NewNamingScheme(LegacyNaming)
+			Expect(err).ToNot(HaveOccurred())
+
+			artifact = &maven.Artifact{
+				GroupID:    "com.foo",
+				ArtifactID: "bar-baz",
+				Version:    "1.0",
+				Dependencies: []*maven.Artifact{
+					{GroupID: "org.fake", ArtifactID: "dep", Version: "2.0"},
+				},
+			}
+		})
+
+		JustBeforeEach(func() {
+			names, err = AssignRuleNames(scheme, artifact)
+		})
+
+		Context("without any collisions", func() {
+			It("should name every artifact", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(names.Get(artifact)).To(Equal("com_foo_bar_baz"))
+				Expect(names.Get(artifact.Dependencies[0])).To(Equal("org_fake_dep"))
+			})
+		})
+
+		Context("where two artifacts end up with the same rule name", func() {
+			BeforeEach(func() {
+				artifact.Dependencies = append(artifact.Dependencies, &maven.Artifact{
+					GroupID: "com.foo.bar", ArtifactID: "baz", Version: "1.1",
+				})
+			})
+
+			It("should return an error listing both artifacts", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(
+					"rule name [com_foo_bar_baz] is shared by artifacts [com.foo:bar-baz:1.0] and [com.foo.bar:baz:1.1], try a different naming scheme"))
+			})
+
+			Context("with a naming scheme that tells them apart", func() {
+				BeforeEach(func() {
+					scheme, err = NewNamingScheme(VersionedNaming)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("should name every artifact", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(names.Get(artifact)).To(Equal("com_foo_bar_baz_1_0"))
+					Expect(names.Get(artifact.Dependencies[1])).To(Equal("com_foo_bar_baz_1_1"))
+				})
+			})
+		})
+	})
+})
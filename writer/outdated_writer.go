@@ -0,0 +1,79 @@
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"io"
+	"text/tabwriter"
+)
+
+// OutdatedReportEntry is an artifact of the dependency graph along with the newest versions it could be upgraded to,
+// each one being empty if there is no such update.
+type OutdatedReportEntry struct {
+	Coordinates string `json:"coordinates"`
+	GroupID     string `json:"groupId"`
+	ArtifactID  string `json:"artifactId"`
+	Version     string `json:"version"`
+	Patch       string `json:"patch,omitempty"`
+	Minor       string `json:"minor,omitempty"`
+	Major       string `json:"major,omitempty"`
+}
+
+// OutdatedWriter reports the artifacts which have newer versions available, as a table or as JSON.
+type OutdatedWriter struct {
+	out    io.Writer
+	format string
+}
+
+func NewOutdatedWriter(w io.Writer, format string) (*OutdatedWriter, error) {
+	if format != TextFormat && format != JSONFormat {
+		return nil, errors.Errorf("unknown outdated report format [%s], expected one of : %s, %s",
+			format, JSONFormat, TextFormat)
+	}
+	return &OutdatedWriter{out: w, format: format}, nil
+}
+
+// Write reports the outdated artifacts among the updates checked, leaving out the ones up to date.
+func (w *OutdatedWriter) Write(updates []*maven.Updates) error {
+	entries := make([]*OutdatedReportEntry, 0)
+	for _, u := range updates {
+		if !u.IsOutdated() {
+			continue
+		}
+		entries = append(entries, &OutdatedReportEntry{
+			Coordinates: u.Artifact.GetMavenCoords(),
+			GroupID:     u.Artifact.GroupID,
+			ArtifactID:  u.Artifact.ArtifactID,
+			Version:     u.Artifact.Version,
+			Patch:       u.Patch,
+			Minor:       u.Minor,
+			Major:       u.Major,
+		})
+	}
+
+	if w.format == JSONFormat {
+		encoder := json.NewEncoder(w.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	out := tabwriter.NewWriter(w.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(out, "ARTIFACT\tCURRENT\tPATCH\tMINOR\tMAJOR")
+	orNone := func(version string) string {
+		if version == "" {
+			return "-"
+		}
+		return version
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(out, "%s:%s\t%s\t%s\t%s\t%s\n", entry.GroupID, entry.ArtifactID, entry.Version,
+			orNone(entry.Patch), orNone(entry.Minor), orNone(entry.Major))
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w.out, "%d of %d artifact(s) outdated\n", len(entries), len(updates))
+	return err
+}
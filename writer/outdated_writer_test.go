@@ -0,0 +1,84 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("OutdatedWriter", func() {
+	var (
+		err     error
+		out     *gbytes.Buffer
+		format  string
+		updates []*maven.Updates
+	)
+
+	BeforeEach(func() {
+		out = gbytes.NewBuffer()
+		updates = []*maven.Updates{
+			{Artifact: &maven.Artifact{GroupID: "junit", ArtifactID: "junit", Version: "4.9"}, Minor: "4.13.2"},
+			{Artifact: &maven.Artifact{GroupID: "org.fake", ArtifactID: "a", Version: "1.0"}},
+			{Artifact: &maven.Artifact{GroupID: "org.fake", ArtifactID: "b", Version: "1.2.3"}, Patch: "1.2.10",
+				Major: "2.0"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var writer *OutdatedWriter
+		writer, err = NewOutdatedWriter(out, format)
+		if err == nil {
+			err = writer.Write(updates)
+		}
+	})
+
+	AfterEach(func() {
+		out.Close()
+	})
+
+	Context("writing text", func() {
+		BeforeEach(func() {
+			format = TextFormat
+		})
+
+		It("should print a table of the outdated artifacts only", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(
+				`ARTIFACT     CURRENT  PATCH   MINOR   MAJOR
+junit:junit  4.9      -       4.13.2  -
+org.fake:b   1.2.3    1.2.10  -       2.0
+2 of 3 artifact(s) outdated
+`))
+		})
+	})
+
+	Context("writing JSON", func() {
+		BeforeEach(func() {
+			format = JSONFormat
+		})
+
+		It("should list the updates of the outdated artifacts", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.Contents()).To(MatchJSON(`[
+				{"coordinates": "junit:junit:4.9", "groupId": "junit", "artifactId": "junit", "version": "4.9",
+					"minor": "4.13.2"},
+				{"coordinates": "org.fake:b:1.2.3", "groupId": "org.fake", "artifactId": "b", "version": "1.2.3",
+					"patch": "1.2.10", "major": "2.0"}
+			]`))
+		})
+	})
+
+	Context("given an unknown format", func() {
+		BeforeEach(func() {
+			format = "csv"
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("unknown outdated report format [csv], expected one of : json, text"))
+		})
+	})
+})
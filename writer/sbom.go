@@ -0,0 +1,64 @@
+package writer
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const sbomTool = `generate-bazel-workspace-gradle`
+
+// mavenCentral is the repository package URLs of Maven artifacts point to unless told otherwise.
+const mavenCentral = `https://repo.maven.apache.org/maven2`
+
+const (
+	CycloneDXFormat = "cyclonedx"
+	SPDXFormat      = "spdx"
+)
+
+var sbomFormats = map[string]func(w io.Writer) GraphWriter{
+	CycloneDXFormat: func(w io.Writer) GraphWriter { return NewCycloneDXWriter(w) },
+	SPDXFormat:      func(w io.Writer) GraphWriter { return NewSPDXWriter(w) },
+}
+
+// NewSBOMWriter creates the writer of a software bill of materials of the dependency graph, in one of the SBOM
+// formats.
+func NewSBOMWriter(w io.Writer, format string) (GraphWriter, error) {
+	newWriter, ok := sbomFormats[format]
+	if !ok {
+		return nil, errors.Errorf("unknown SBOM format [%s], expected one of : %s",
+			format, strings.Join(SBOMFormatNames(), ", "))
+	}
+	return newWriter(w), nil
+}
+
+func SBOMFormatNames() []string {
+	names := make([]string, 0, len(sbomFormats))
+	for name := range sbomFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PackageURL identifies an artifact as `pkg:maven/groupId/artifactId@version`, qualified with the repository it was
+// resolved from unless that is Maven Central.
+func PackageURL(artifact *maven.Artifact) string {
+	purl := "pkg:maven/" + url.PathEscape(artifact.GroupID) + "/" + url.PathEscape(artifact.ArtifactID) + "@" +
+		url.PathEscape(artifact.Version)
+	if repository := strings.TrimRight(artifact.Repository, "/"); repository != "" && repository != mavenCentral {
+		purl += "?repository_url=" + url.QueryEscape(repository)
+	}
+	return purl
+}
+
+// downloadURL is where the JAR of an artifact was resolved from, empty if it came from a local repository.
+func downloadURL(artifact *maven.Artifact) string {
+	if !strings.HasPrefix(artifact.Repository, "http://") && !strings.HasPrefix(artifact.Repository, "https://") {
+		return ""
+	}
+	return strings.TrimRight(artifact.Repository, "/") + "/" + artifact.PathToJar()
+}
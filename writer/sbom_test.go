@@ -0,0 +1,273 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("SBOM", func() {
+	var (
+		err    error
+		out    *gbytes.Buffer
+		writer GraphWriter
+		format string
+		pom    *maven.Artifact
+		root   *maven.Node
+		sbom   map[string]interface{}
+	)
+
+	BeforeEach(func() {
+		out = gbytes.NewBuffer()
+		pom = &maven.Artifact{
+			GroupID:    "org.fake",
+			ArtifactID: "some-artifact",
+			Version:    "0.0.1",
+			Repository: "https://repo.maven.apache.org/maven2/",
+			SHA:        "abc123",
+			Licenses:   []maven.License{{Name: "The Apache Software License, Version 2.0"}},
+			Dependencies: []*maven.Artifact{{
+				GroupID:    "fake.org",
+				ArtifactID: "another-artifact",
+				Version:    "2.0.3",
+				Scope:      "compile",
+				Repository: "http://otherhost",
+				SHA:        "def456",
+				Licenses:   []maven.License{{Name: "EPL 1.0"}, {Name: "Some Company License", URL: "https://example.com/license"}},
+			}, {
+				GroupID:    "fake.org",
+				ArtifactID: "test-artifact",
+				Version:    "1.0",
+				Scope:      "test",
+				Repository: "/root/.m2/repository",
+			}},
+		}
+		root = &maven.Node{Artifact: pom, Children: []*maven.Node{
+			{Artifact: pom.Dependencies[0], Scope: "compile"},
+			{Artifact: pom.Dependencies[1], Scope: "test"},
+		}}
+	})
+
+	// shareDependency makes both dependencies of the root depend on the same artifact, resolved through the first one.
+	shareDependency := func() {
+		shared := &maven.Artifact{GroupID: "fake.org", ArtifactID: "shared", Version: "1.0", Scope: "compile"}
+		pom.Dependencies[0].Dependencies = []*maven.Artifact{shared}
+		winner := &maven.Node{Artifact: shared, Scope: "compile"}
+		root.Children[0].Children = []*maven.Node{winner}
+		root.Children[1].Children = []*maven.Node{
+			{Artifact: &maven.Artifact{GroupID: "fake.org", ArtifactID: "shared", Version: "1.0", Scope: "test"},
+				Scope: "test", Omitted: maven.OmittedDuplicate, Winner: winner},
+		}
+	}
+
+	JustBeforeEach(func() {
+		writer, err = NewSBOMWriter(out, format)
+		if err == nil {
+			err = writer.Write(root)
+		}
+		if err == nil {
+			sbom = map[string]interface{}{}
+			Expect(json.Unmarshal(out.Contents(), &sbom)).To(Succeed())
+		}
+	})
+
+	It("should identify artifacts by package URLs", func() {
+		Expect(PackageURL(pom)).To(Equal("pkg:maven/org.fake/some-artifact@0.0.1"))
+		Expect(PackageURL(pom.Dependencies[0])).To(
+			Equal("pkg:maven/fake.org/another-artifact@2.0.3?repository_url=http%3A%2F%2Fotherhost"))
+	})
+
+	Context("given the CycloneDX format", func() {
+		BeforeEach(func() {
+			format = CycloneDXFormat
+		})
+
+		It("should describe the root artifact as the component of the SBOM", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sbom).To(HaveKeyWithValue("bomFormat", "CycloneDX"))
+			Expect(sbom).To(HaveKeyWithValue("specVersion", "1.4"))
+
+			metadata := sbom["metadata"].(map[string]interface{})
+			Expect(metadata).To(HaveKey("timestamp"))
+			Expect(json.Marshal(metadata["component"])).To(MatchJSON(`{
+				"type": "library",
+				"bom-ref": "pkg:maven/org.fake/some-artifact@0.0.1",
+				"group": "org.fake",
+				"name": "some-artifact",
+				"version": "0.0.1",
+				"hashes": [{"alg": "SHA-1", "content": "abc123"}],
+				"licenses": [{"license": {"id": "Apache-2.0"}}],
+				"purl": "pkg:maven/org.fake/some-artifact@0.0.1",
+				"externalReferences": [{
+					"type": "distribution",
+					"url": "https://repo.maven.apache.org/maven2/org/fake/some-artifact/0.0.1/some-artifact-0.0.1.jar"
+				}]
+			}`))
+		})
+
+		It("should list dependencies as components along with their relationships", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Marshal(sbom["components"])).To(MatchJSON(`[{
+				"type": "library",
+				"bom-ref": "pkg:maven/fake.org/another-artifact@2.0.3?repository_url=http%3A%2F%2Fotherhost",
+				"group": "fake.org",
+				"name": "another-artifact",
+				"version": "2.0.3",
+				"scope": "required",
+				"hashes": [{"alg": "SHA-1", "content": "def456"}],
+				"licenses": [
+					{"license": {"id": "EPL-1.0"}},
+					{"license": {"name": "Some Company License", "url": "https://example.com/license"}}
+				],
+				"purl": "pkg:maven/fake.org/another-artifact@2.0.3?repository_url=http%3A%2F%2Fotherhost",
+				"externalReferences": [{
+					"type": "distribution",
+					"url": "http://otherhost/fake/org/another-artifact/2.0.3/another-artifact-2.0.3.jar"
+				}]
+			}, {
+				"type": "library",
+				"bom-ref": "pkg:maven/fake.org/test-artifact@1.0?repository_url=%2Froot%2F.m2%2Frepository",
+				"group": "fake.org",
+				"name": "test-artifact",
+				"version": "1.0",
+				"scope": "excluded",
+				"purl": "pkg:maven/fake.org/test-artifact@1.0?repository_url=%2Froot%2F.m2%2Frepository"
+			}]`))
+			Expect(json.Marshal(sbom["dependencies"])).To(MatchJSON(`[{
+				"ref": "pkg:maven/org.fake/some-artifact@0.0.1",
+				"dependsOn": [
+					"pkg:maven/fake.org/another-artifact@2.0.3?repository_url=http%3A%2F%2Fotherhost",
+					"pkg:maven/fake.org/test-artifact@1.0?repository_url=%2Froot%2F.m2%2Frepository"
+				]
+			}, {
+				"ref": "pkg:maven/fake.org/another-artifact@2.0.3?repository_url=http%3A%2F%2Fotherhost",
+				"dependsOn": []
+			}, {
+				"ref": "pkg:maven/fake.org/test-artifact@1.0?repository_url=%2Froot%2F.m2%2Frepository",
+				"dependsOn": []
+			}]`))
+		})
+
+		Context("given a dependency shared by several artifacts", func() {
+			BeforeEach(shareDependency)
+
+			It("should list it once, as a dependency of every dependent", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sbom["components"]).To(HaveLen(3))
+				Expect(json.Marshal(sbom["dependencies"])).To(MatchJSON(`[{
+					"ref": "pkg:maven/org.fake/some-artifact@0.0.1",
+					"dependsOn": [
+						"pkg:maven/fake.org/another-artifact@2.0.3?repository_url=http%3A%2F%2Fotherhost",
+						"pkg:maven/fake.org/test-artifact@1.0?repository_url=%2Froot%2F.m2%2Frepository"
+					]
+				}, {
+					"ref": "pkg:maven/fake.org/another-artifact@2.0.3?repository_url=http%3A%2F%2Fotherhost",
+					"dependsOn": ["pkg:maven/fake.org/shared@1.0"]
+				}, {
+					"ref": "pkg:maven/fake.org/shared@1.0",
+					"dependsOn": []
+				}, {
+					"ref": "pkg:maven/fake.org/test-artifact@1.0?repository_url=%2Froot%2F.m2%2Frepository",
+					"dependsOn": ["pkg:maven/fake.org/shared@1.0"]
+				}]`))
+			})
+		})
+	})
+
+	Context("given the SPDX format", func() {
+		BeforeEach(func() {
+			format = SPDXFormat
+		})
+
+		It("should describe the root artifact with a unique document", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sbom).To(HaveKeyWithValue("spdxVersion", "SPDX-2.3"))
+			Expect(sbom).To(HaveKeyWithValue("SPDXID", "SPDXRef-DOCUMENT"))
+			Expect(sbom).To(HaveKeyWithValue("name", "org.fake:some-artifact:0.0.1"))
+			Expect(sbom["documentNamespace"]).To(MatchRegexp(
+				`^https://github.com/jspawar/generate-bazel-workspace-gradle/spdx/org.fake-some-artifact-0.0.1-[0-9a-f-]{36}$`))
+		})
+
+		It("should list every artifact as a package", func() {
+			Expect(err).ToNot(HaveOccurred())
+			packages := sbom["packages"].([]interface{})
+			Expect(packages).To(HaveLen(3))
+			Expect(json.Marshal(packages[1])).To(MatchJSON(`{
+				"SPDXID": "SPDXRef-Package-fake.org-another-artifact-2.0.3",
+				"name": "fake.org:another-artifact",
+				"versionInfo": "2.0.3",
+				"downloadLocation": "http://otherhost/fake/org/another-artifact/2.0.3/another-artifact-2.0.3.jar",
+				"filesAnalyzed": false,
+				"checksums": [{"algorithm": "SHA1", "checksumValue": "def456"}],
+				"licenseConcluded": "NOASSERTION",
+				"licenseDeclared": "(EPL-1.0 OR LicenseRef-Some-Company-License)",
+				"copyrightText": "NOASSERTION",
+				"externalRefs": [{
+					"referenceCategory": "PACKAGE-MANAGER",
+					"referenceType": "purl",
+					"referenceLocator": "pkg:maven/fake.org/another-artifact@2.0.3?repository_url=http%3A%2F%2Fotherhost"
+				}]
+			}`))
+			Expect(packages[0]).To(HaveKeyWithValue("licenseDeclared", "Apache-2.0"))
+			Expect(packages[2]).To(HaveKeyWithValue("downloadLocation", "NOASSERTION"))
+			Expect(packages[2]).To(HaveKeyWithValue("licenseDeclared", "NOASSERTION"))
+			Expect(json.Marshal(sbom["hasExtractedLicensingInfos"])).To(MatchJSON(`[{
+				"licenseId": "LicenseRef-Some-Company-License",
+				"name": "Some Company License",
+				"extractedText": "Some Company License",
+				"seeAlsos": ["https://example.com/license"]
+			}]`))
+		})
+
+		It("should relate packages by their dependencies", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Marshal(sbom["relationships"])).To(MatchJSON(`[{
+				"spdxElementId": "SPDXRef-DOCUMENT",
+				"relationshipType": "DESCRIBES",
+				"relatedSpdxElement": "SPDXRef-Package-org.fake-some-artifact-0.0.1"
+			}, {
+				"spdxElementId": "SPDXRef-Package-org.fake-some-artifact-0.0.1",
+				"relationshipType": "DEPENDS_ON",
+				"relatedSpdxElement": "SPDXRef-Package-fake.org-another-artifact-2.0.3"
+			}, {
+				"spdxElementId": "SPDXRef-Package-fake.org-test-artifact-1.0",
+				"relationshipType": "TEST_DEPENDENCY_OF",
+				"relatedSpdxElement": "SPDXRef-Package-org.fake-some-artifact-0.0.1"
+			}]`))
+		})
+
+		Context("given a dependency shared by several artifacts", func() {
+			BeforeEach(shareDependency)
+
+			It("should relate it to every dependent", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sbom["packages"]).To(HaveLen(4))
+				relationships := sbom["relationships"].([]interface{})
+				Expect(relationships).To(HaveLen(5))
+				Expect(json.Marshal(relationships[3:])).To(MatchJSON(`[{
+					"spdxElementId": "SPDXRef-Package-fake.org-another-artifact-2.0.3",
+					"relationshipType": "DEPENDS_ON",
+					"relatedSpdxElement": "SPDXRef-Package-fake.org-shared-1.0"
+				}, {
+					"spdxElementId": "SPDXRef-Package-fake.org-shared-1.0",
+					"relationshipType": "TEST_DEPENDENCY_OF",
+					"relatedSpdxElement": "SPDXRef-Package-fake.org-test-artifact-1.0"
+				}]`))
+			})
+		})
+	})
+
+	Context("given an unknown format", func() {
+		BeforeEach(func() {
+			format = "swid"
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(MatchError("unknown SBOM format [swid], expected one of : cyclonedx, spdx"))
+		})
+	})
+})
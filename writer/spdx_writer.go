@@ -0,0 +1,209 @@
+package writer
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const spdxVersion = "SPDX-2.3"
+const spdxNamespace = `https://github.com/jspawar/generate-bazel-workspace-gradle/spdx/`
+const spdxNoAssertion = `NOASSERTION`
+
+var spdxIDRegex = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+
+// spdxExpressions are the license expressions replacing deprecated SPDX identifiers.
+var spdxExpressions = map[string]string{
+	"GPL-2.0-with-classpath-exception": "GPL-2.0-only WITH Classpath-exception-2.0",
+}
+
+var _ GraphWriter = &SPDXWriter{}
+
+// SPDXWriter writes an SPDX JSON document describing the root artifact, and every artifact of its dependency graph as
+// a package.
+type SPDXWriter struct {
+	out io.Writer
+}
+
+func NewSPDXWriter(w io.Writer) *SPDXWriter {
+	return &SPDXWriter{out: w}
+}
+
+type spdxDocument struct {
+	SPDXVersion       string                  `json:"spdxVersion"`
+	DataLicense       string                  `json:"dataLicense"`
+	SPDXID            string                  `json:"SPDXID"`
+	Name              string                  `json:"name"`
+	DocumentNamespace string                  `json:"documentNamespace"`
+	CreationInfo      *spdxCreationInfo       `json:"creationInfo"`
+	Packages          []*spdxPackage          `json:"packages"`
+	Relationships     []*spdxRelationship     `json:"relationships"`
+	ExtractedLicenses []*spdxExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string             `json:"SPDXID"`
+	Name             string             `json:"name"`
+	VersionInfo      string             `json:"versionInfo"`
+	DownloadLocation string             `json:"downloadLocation"`
+	FilesAnalyzed    bool               `json:"filesAnalyzed"`
+	Checksums        []*spdxChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded string             `json:"licenseConcluded"`
+	LicenseDeclared  string             `json:"licenseDeclared"`
+	CopyrightText    string             `json:"copyrightText"`
+	ExternalRefs     []*spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxChecksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+type spdxExtractedLicense struct {
+	LicenseID     string   `json:"licenseId"`
+	Name          string   `json:"name"`
+	ExtractedText string   `json:"extractedText"`
+	SeeAlsos      []string `json:"seeAlsos,omitempty"`
+}
+
+func (w *SPDXWriter) Write(root *maven.Node) error {
+	artifact := root.Artifact
+	namespace, err := spdxDocumentNamespace(artifact)
+	if err != nil {
+		return err
+	}
+	doc := &spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              artifact.GetMavenCoords(),
+		DocumentNamespace: namespace,
+		CreationInfo: &spdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + sbomTool},
+		},
+		Packages: make([]*spdxPackage, 0),
+		Relationships: []*spdxRelationship{
+			{Element: "SPDXRef-DOCUMENT", Type: "DESCRIBES", Related: spdxPackageID(artifact)},
+		},
+	}
+	extracted := map[string]bool{}
+	for _, a := range resolvedArtifacts(root) {
+		pkg := &spdxPackage{
+			SPDXID:           spdxPackageID(a),
+			Name:             a.GetVersionlessCoords(),
+			VersionInfo:      a.Version,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			ExternalRefs: []*spdxExternalRef{
+				{Category: "PACKAGE-MANAGER", Type: "purl", Locator: PackageURL(a)},
+			},
+		}
+		if url := downloadURL(a); url != "" {
+			pkg.DownloadLocation = url
+		}
+		if a.SHA != "" {
+			pkg.Checksums = append(pkg.Checksums, &spdxChecksum{Algorithm: "SHA1", Value: a.SHA})
+		}
+		expressions := make([]string, 0, len(a.Licenses))
+		for _, license := range a.Licenses {
+			if license.Name == "" && license.URL == "" {
+				continue
+			}
+			expression, info := spdxLicenseExpression(license)
+			if info != nil && !extracted[info.LicenseID] {
+				extracted[info.LicenseID] = true
+				doc.ExtractedLicenses = append(doc.ExtractedLicenses, info)
+			}
+			expressions = append(expressions, expression)
+		}
+		// POMs listing several licenses let users pick any of them
+		if len(expressions) == 1 {
+			pkg.LicenseDeclared = expressions[0]
+		} else if len(expressions) > 1 {
+			pkg.LicenseDeclared = "(" + strings.Join(expressions, " OR ") + ")"
+		}
+		doc.Packages = append(doc.Packages, pkg)
+	}
+	for _, edge := range dependencyEdges(root) {
+		// optional dependencies aren't packages of the document
+		if edge.optional {
+			continue
+		}
+		doc.Relationships = append(doc.Relationships, spdxDependencyRelationship(edge))
+	}
+
+	encoder := json.NewEncoder(w.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+func spdxPackageID(artifact *maven.Artifact) string {
+	return "SPDXRef-Package-" + strings.Trim(spdxIDRegex.ReplaceAllString(artifact.GetMavenCoords(), "-"), "-")
+}
+
+// spdxDocumentNamespace makes up the unique URI SPDX requires of every document.
+func spdxDocumentNamespace(artifact *maven.Artifact) (string, error) {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		return "", err
+	}
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+	return fmt.Sprintf("%s%s-%x-%x-%x-%x-%x", spdxNamespace, spdxPackageID(artifact)[len("SPDXRef-Package-"):],
+		uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]), nil
+}
+
+// spdxLicenseExpression returns the SPDX expression of a license, unrecognized ones being declared as extracted
+// licenses referred to by a `LicenseRef-` identifier.
+func spdxLicenseExpression(license maven.License) (string, *spdxExtractedLicense) {
+	if id := license.SPDXID(); id != "" {
+		if expression, isDeprecated := spdxExpressions[id]; isDeprecated {
+			return "(" + expression + ")", nil
+		}
+		return id, nil
+	}
+	info := &spdxExtractedLicense{
+		LicenseID:     "LicenseRef-" + strings.Trim(spdxIDRegex.ReplaceAllString(license.String(), "-"), "-"),
+		Name:          license.String(),
+		ExtractedText: license.String(),
+	}
+	if license.URL != "" {
+		info.SeeAlsos = []string{license.URL}
+	}
+	return info.LicenseID, info
+}
+
+// spdxDependencyRelationship tells how an artifact depends on another, test dependencies being distinguished from
+// those needed at runtime.
+func spdxDependencyRelationship(edge *dependencyEdge) *spdxRelationship {
+	dependent, dependency := spdxPackageID(edge.from), spdxPackageID(edge.to)
+	if edge.scope == maven.ScopeTest {
+		return &spdxRelationship{Element: dependency, Type: "TEST_DEPENDENCY_OF", Related: dependent}
+	}
+	return &spdxRelationship{Element: dependent, Type: "DEPENDS_ON", Related: dependency}
+}
@@ -0,0 +1,212 @@
+package writer
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const starlarkIndent = `    `
+
+// A small Starlark syntax tree, only covering what generated workspace files need. Printing it produces the same
+// output `buildifier` would for it, so generated files never get reformatted.
+
+type Stmt interface {
+	stmt()
+}
+
+type Expr interface {
+	expr()
+}
+
+// LoadStmt loads symbols from a Starlark module, like `load("@rules_license//rules:license.bzl", "license")`.
+type LoadStmt struct {
+	Module  string
+	Symbols []string
+}
+
+type DefStmt struct {
+	Name string
+	Body []Stmt
+}
+
+type IfStmt struct {
+	Cond Expr
+	Body []Stmt
+}
+
+type AssignStmt struct {
+	Name  string
+	Value Expr
+}
+
+// ExprStmt is an expression statement, with comments printed on their own lines before it.
+type ExprStmt struct {
+	X        Expr
+	Comments []string
+}
+
+type StringExpr struct {
+	Value string
+}
+
+type ListExpr struct {
+	Values []Expr
+}
+
+type IdentExpr struct {
+	Name string
+}
+
+type DotExpr struct {
+	X    Expr
+	Name string
+}
+
+type CallExpr struct {
+	Func Expr
+	Args []*Attr
+}
+
+type BinaryExpr struct {
+	X  Expr
+	Op string
+	Y  Expr
+}
+
+// RawExpr is any expression not understood by this tool, which is preserved verbatim.
+type RawExpr struct {
+	Source string
+}
+
+func (*LoadStmt) stmt()   {}
+func (*DefStmt) stmt()    {}
+func (*IfStmt) stmt()     {}
+func (*AssignStmt) stmt() {}
+func (*ExprStmt) stmt()   {}
+
+func (*StringExpr) expr() {}
+func (*ListExpr) expr()   {}
+func (*IdentExpr) expr()  {}
+func (*DotExpr) expr()    {}
+func (*CallExpr) expr()   {}
+func (*BinaryExpr) expr() {}
+func (*RawExpr) expr()    {}
+
+// PrintStarlark formats a file made up of the given top level statements.
+func PrintStarlark(stmts []Stmt) []byte {
+	p := &starlarkPrinter{}
+	p.printBlock(stmts, 0)
+	return p.out.Bytes()
+}
+
+type starlarkPrinter struct {
+	out bytes.Buffer
+}
+
+func (p *starlarkPrinter) printBlock(stmts []Stmt, depth int) {
+	for i, stmt := range stmts {
+		// statements are always separated by exactly one blank line
+		if i > 0 {
+			p.out.WriteString("\n")
+		}
+		p.printStmt(stmt, depth)
+	}
+}
+
+func (p *starlarkPrinter) printStmt(stmt Stmt, depth int) {
+	p.writeIndent(depth)
+	switch s := stmt.(type) {
+	case *LoadStmt:
+		p.out.WriteString("load(" + strconv.Quote(s.Module))
+		for _, symbol := range s.Symbols {
+			p.out.WriteString(", " + strconv.Quote(symbol))
+		}
+		p.out.WriteString(")\n")
+	case *DefStmt:
+		p.out.WriteString("def " + s.Name + "():\n")
+		p.printBlock(s.Body, depth+1)
+	case *IfStmt:
+		p.out.WriteString("if ")
+		p.printExpr(s.Cond, depth)
+		p.out.WriteString(":\n")
+		p.printBlock(s.Body, depth+1)
+	case *AssignStmt:
+		p.out.WriteString(s.Name + " = ")
+		p.printExpr(s.Value, depth)
+		p.out.WriteString("\n")
+	case *ExprStmt:
+		for _, comment := range s.Comments {
+			p.out.WriteString("# " + comment + "\n")
+			p.writeIndent(depth)
+		}
+		p.printExpr(s.X, depth)
+		p.out.WriteString("\n")
+	}
+}
+
+func (p *starlarkPrinter) printExpr(expr Expr, depth int) {
+	switch e := expr.(type) {
+	case *StringExpr:
+		p.out.WriteString(strconv.Quote(e.Value))
+	case *IdentExpr:
+		p.out.WriteString(e.Name)
+	case *RawExpr:
+		p.out.WriteString(e.Source)
+	case *DotExpr:
+		p.printExpr(e.X, depth)
+		p.out.WriteString("." + e.Name)
+	case *BinaryExpr:
+		p.printExpr(e.X, depth)
+		p.out.WriteString(" " + e.Op + " ")
+		p.printExpr(e.Y, depth)
+	case *ListExpr:
+		p.printList(e, depth)
+	case *CallExpr:
+		p.printCall(e, depth)
+	}
+}
+
+// printList keeps lists of up to one element on a single line, longer ones get one element per line.
+func (p *starlarkPrinter) printList(list *ListExpr, depth int) {
+	if len(list.Values) < 2 {
+		p.out.WriteString("[")
+		for _, v := range list.Values {
+			p.printExpr(v, depth)
+		}
+		p.out.WriteString("]")
+		return
+	}
+
+	p.out.WriteString("[\n")
+	for _, v := range list.Values {
+		p.writeIndent(depth + 1)
+		p.printExpr(v, depth+1)
+		p.out.WriteString(",\n")
+	}
+	p.writeIndent(depth)
+	p.out.WriteString("]")
+}
+
+// printCall puts every argument of a call on its own line.
+func (p *starlarkPrinter) printCall(call *CallExpr, depth int) {
+	p.printExpr(call.Func, depth)
+	if len(call.Args) < 1 {
+		p.out.WriteString("()")
+		return
+	}
+
+	p.out.WriteString("(\n")
+	for _, arg := range call.Args {
+		p.writeIndent(depth + 1)
+		p.out.WriteString(arg.Name + " = ")
+		p.printExpr(arg.Value, depth+1)
+		p.out.WriteString(",\n")
+	}
+	p.writeIndent(depth)
+	p.out.WriteString(")")
+}
+
+func (p *starlarkPrinter) writeIndent(depth int) {
+	p.out.WriteString(strings.Repeat(starlarkIndent, depth))
+}
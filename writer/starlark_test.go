@@ -0,0 +1,91 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Starlark", func() {
+	Context("Given a syntax tree", func() {
+		It("should print it the way buildifier formats it", func() {
+			out := PrintStarlark([]Stmt{
+				&AssignStmt{Name: "FOO", Value: &ListExpr{Values: []Expr{
+					&StringExpr{Value: "a"}, &StringExpr{Value: `quoted "b"`},
+				}}},
+				&DefStmt{Name: "bar", Body: []Stmt{
+					&IfStmt{
+						Cond: &BinaryExpr{X: &StringExpr{Value: "x"}, Op: "in", Y: &IdentExpr{Name: "FOO"}},
+						Body: []Stmt{&ExprStmt{X: &CallExpr{
+							Func: &DotExpr{X: &IdentExpr{Name: "native"}, Name: "thing"},
+							Args: []*Attr{
+								{Name: "name", Value: &StringExpr{Value: "x"}},
+								{Name: "empty", Value: &ListExpr{}},
+								{Name: "raw", Value: &RawExpr{Source: `select({"//conditions:default": []})`}},
+							},
+						}}},
+					},
+					&ExprStmt{X: &CallExpr{Func: &IdentExpr{Name: "print"}}},
+				}},
+			})
+
+			Expect(string(out)).To(Equal(`FOO = [
+    "a",
+    "quoted \"b\"",
+]
+
+def bar():
+    if "x" in FOO:
+        native.thing(
+            name = "x",
+            empty = [],
+            raw = select({"//conditions:default": []}),
+        )
+
+    print()
+`))
+		})
+	})
+
+	Context("Given artifacts discovered in different orders", func() {
+		var (
+			naming NamingScheme
+		)
+
+		BeforeEach(func() {
+			var err error
+			naming, err = NewNamingScheme(LegacyNaming)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		write := func(deps ...*maven.Artifact) string {
+			out := gbytes.NewBuffer()
+			defer out.Close()
+
+			err := NewWorkspaceWriter(out, naming).Write(&maven.Artifact{
+				GroupID:      "org.fake",
+				ArtifactID:   "root",
+				Version:      "1.0",
+				Dependencies: deps,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			return string(out.Contents())
+		}
+
+		It("should write byte-for-byte identical output", func() {
+			a := &maven.Artifact{GroupID: "org.fake", ArtifactID: "a", Version: "1.0"}
+			b := &maven.Artifact{GroupID: "org.fake", ArtifactID: "b", Version: "1.0"}
+
+			first := write(a, b)
+			Expect(first).To(Equal(write(b, a)))
+			Expect(first).To(ContainSubstring(`            deps = [
+                ":org_fake_a",
+                ":org_fake_b",
+            ],
+`))
+		})
+	})
+})
@@ -0,0 +1,120 @@
+package writer
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var _ Writer = &TemplateWriter{}
+
+var templateFuncs = template.FuncMap{
+	"quote": strconv.Quote,
+	"join":  strings.Join,
+	"labels": func(names []string) []string {
+		labels := make([]string, len(names))
+		for i, name := range names {
+			labels[i] = ":" + name
+		}
+		return labels
+	},
+}
+
+// TemplateData is what a user supplied template gets executed with.
+type TemplateData struct {
+	Root         *TemplateArtifact
+	Artifacts    []*TemplateArtifact
+	Repositories []string
+}
+
+type TemplateArtifact struct {
+	Coordinates string
+	GroupID     string
+	ArtifactID  string
+	Version     string
+	Scope       string
+	Repository  string
+	SHA1        string
+	RuleName    string
+	Deps        []string
+	RuntimeDeps []string
+	NeverLink   bool
+	TestOnly    bool
+	Artifact    *maven.Artifact
+}
+
+// TemplateWriter renders the dependency graph through a `text/template`, e.g. to target custom macros.
+type TemplateWriter struct {
+	out      io.Writer
+	naming   NamingScheme
+	template *template.Template
+}
+
+func NewTemplateWriter(w io.Writer, templateFile string, naming NamingScheme) (*TemplateWriter, error) {
+	contents, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read template [%s]", templateFile)
+	}
+	tmpl, err := template.New(filepath.Base(templateFile)).Funcs(templateFuncs).Parse(string(contents))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse template [%s]", templateFile)
+	}
+	return &TemplateWriter{out: w, naming: naming, template: tmpl}, nil
+}
+
+func (w *TemplateWriter) Write(artifact *maven.Artifact) error {
+	logger.Debugf("Rendering template [%s] for artifact: [%s]", w.template.Name(), artifact.GetMavenCoords())
+
+	data, err := NewTemplateData(artifact, w.naming)
+	if err != nil {
+		return err
+	}
+	if err := w.template.Execute(w.out, data); err != nil {
+		return errors.Wrapf(err, "failed to render template [%s]", w.template.Name())
+	}
+	return nil
+}
+
+func NewTemplateData(artifact *maven.Artifact, naming NamingScheme) (*TemplateData, error) {
+	names, err := AssignRuleNames(naming, artifact)
+	if err != nil {
+		return nil, err
+	}
+	data := &TemplateData{}
+	seenRepositories := map[string]bool{}
+
+	for _, a := range collectArtifacts(artifact) {
+		deps, runtimeDeps := partitionDependencies(a)
+		templateArtifact := &TemplateArtifact{
+			Coordinates: a.GetMavenCoords(),
+			GroupID:     a.GroupID,
+			ArtifactID:  a.ArtifactID,
+			Version:     a.Version,
+			Scope:       a.Scope,
+			Repository:  a.Repository,
+			SHA1:        a.SHA,
+			RuleName:    names.Get(a),
+			Deps:        names.getAll(deps),
+			RuntimeDeps: names.getAll(runtimeDeps),
+			NeverLink:   a.Scope == maven.ScopeProvided,
+			TestOnly:    a.Scope == maven.ScopeTest,
+			Artifact:    a,
+		}
+		if data.Root == nil {
+			data.Root = templateArtifact
+		}
+		data.Artifacts = append(data.Artifacts, templateArtifact)
+
+		if a.Repository != "" && !seenRepositories[a.Repository] {
+			seenRepositories[a.Repository] = true
+			data.Repositories = append(data.Repositories, a.Repository)
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,134 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var _ = Describe("TemplateWriter", func() {
+	var (
+		err          error
+		tmpDir       string
+		templateFile string
+		template     string
+		out          *gbytes.Buffer
+		writer       *TemplateWriter
+		naming       NamingScheme
+		pom          *maven.Artifact
+	)
+
+	BeforeEach(func() {
+		tmpDir, err = ioutil.TempDir("", "template_writer_test")
+		Expect(err).ToNot(HaveOccurred())
+		templateFile = filepath.Join(tmpDir, "workspace.tmpl")
+		naming, err = NewNamingScheme(LegacyNaming)
+		Expect(err).ToNot(HaveOccurred())
+
+		out = gbytes.NewBuffer()
+		pom = &maven.Artifact{
+			GroupID:    "org.fake",
+			ArtifactID: "some-artifact",
+			Version:    "0.0.1",
+			Repository: "http://localhost/",
+			SHA:        "abc123",
+			Dependencies: []*maven.Artifact{{
+				GroupID:    "fake.org",
+				ArtifactID: "another-artifact",
+				Version:    "2.0.3",
+				Scope:      "runtime",
+				Repository: "http://otherhost/",
+			}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(ioutil.WriteFile(templateFile, []byte(template), 0644)).To(Succeed())
+
+		writer, err = NewTemplateWriter(out, templateFile, naming)
+		if err == nil {
+			err = writer.Write(pom)
+		}
+	})
+
+	AfterEach(func() {
+		out.Close()
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	Context("given a template targeting a custom macro", func() {
+		BeforeEach(func() {
+			template = `{{range .Artifacts}}company_java_import(
+    name = {{quote .RuleName}},
+    artifact = {{quote .Coordinates}},
+    repository = {{quote .Repository}},
+    sha1 = {{quote .SHA1}},
+    deps = [{{join (labels .Deps) ", "}}],
+    runtime_deps = [{{join (labels .RuntimeDeps) ", "}}],
+)
+{{end}}`
+		})
+
+		It("should render every resolved artifact through the template", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(string(out.Contents())).To(Equal(
+				`company_java_import(
+    name = "org_fake_some_artifact",
+    artifact = "org.fake:some-artifact:0.0.1",
+    repository = "http://localhost/",
+    sha1 = "abc123",
+    deps = [],
+    runtime_deps = [:fake_org_another_artifact],
+)
+company_java_import(
+    name = "fake_org_another_artifact",
+    artifact = "fake.org:another-artifact:2.0.3",
+    repository = "http://otherhost/",
+    sha1 = "",
+    deps = [],
+    runtime_deps = [],
+)
+`))
+		})
+	})
+
+	Context("given a template referring to the root and repositories", func() {
+		BeforeEach(func() {
+			template = `{{.Root.Coordinates}}{{range .Repositories}} {{.}}{{end}}`
+		})
+
+		It("should expose them", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal("org.fake:some-artifact:0.0.1 http://localhost/ http://otherhost/"))
+		})
+	})
+
+	Context("given an invalid template", func() {
+		BeforeEach(func() {
+			template = `{{range .Artifacts}}`
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("failed to parse template [" + templateFile + "]"))
+		})
+	})
+
+	Context("given a template referring to unknown fields", func() {
+		BeforeEach(func() {
+			template = `{{.Nope}}`
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("failed to render template [workspace.tmpl]"))
+		})
+	})
+})
@@ -0,0 +1,120 @@
+package writer
+
+import (
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// TreeWriter prints a resolved dependency graph the way `mvn dependency:tree -Dverbose` does, along with the
+// dependencies omitted as duplicates, conflict losers or exclusions.
+type TreeWriter struct {
+	out io.Writer
+	// depth limits how many levels of dependencies are printed, 0 printing all of them
+	depth int
+	// filter is a `groupId:artifactId[:version]` pattern split by segment, nil printing every artifact
+	filter []string
+}
+
+// NewTreeWriter only prints the paths to the artifacts matching `filter`, if given, each segment of which may hold
+// shell patterns like `*`.
+func NewTreeWriter(w io.Writer, depth int, filter string) (*TreeWriter, error) {
+	if depth < 0 {
+		return nil, errors.Errorf("invalid depth [%d], expected a positive number", depth)
+	}
+	t := &TreeWriter{out: w, depth: depth}
+	if filter == "" {
+		return t, nil
+	}
+	t.filter = strings.Split(filter, ":")
+	if len(t.filter) > 3 {
+		return nil, errors.Errorf("invalid filter [%s], expected groupId:artifactId[:version]", filter)
+	}
+	for _, segment := range t.filter {
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid filter [%s]", filter)
+		}
+	}
+	return t, nil
+}
+
+func (t *TreeWriter) Write(root *maven.Node) error {
+	b := &strings.Builder{}
+	b.WriteString(label(root) + "\n")
+	t.writeChildren(b, root, "", 1)
+	_, err := io.WriteString(t.out, b.String())
+	return err
+}
+
+func (t *TreeWriter) writeChildren(b *strings.Builder, node *maven.Node, prefix string, depth int) {
+	if t.depth > 0 && depth > t.depth {
+		return
+	}
+	children := make([]*maven.Node, 0, len(node.Children))
+	for _, child := range node.Children {
+		if t.keeps(child, depth) {
+			children = append(children, child)
+		}
+	}
+	for i, child := range children {
+		branch, indent := "+- ", "|  "
+		if i == len(children)-1 {
+			branch, indent = `\- `, "   "
+		}
+		b.WriteString(prefix + branch + label(child) + "\n")
+		t.writeChildren(b, child, prefix+indent, depth+1)
+	}
+}
+
+// keeps tells whether a node matches the filter, or leads to a node which does within the printed depth.
+func (t *TreeWriter) keeps(node *maven.Node, depth int) bool {
+	if t.filter == nil || t.matches(node.Artifact) {
+		return true
+	}
+	if t.depth > 0 && depth >= t.depth {
+		return false
+	}
+	for _, child := range node.Children {
+		if t.keeps(child, depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TreeWriter) matches(artifact *maven.Artifact) bool {
+	segments := []string{artifact.GroupID, artifact.ArtifactID, artifact.Version}
+	for i, pattern := range t.filter {
+		if matched, _ := path.Match(pattern, segments[i]); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func label(node *maven.Node) string {
+	coords := node.Artifact.GetMavenCoords()
+	if node.Scope != "" {
+		coords += ":" + node.Scope
+	}
+	switch node.Omitted {
+	case maven.OmittedDuplicate:
+		return fmt.Sprintf("(%s - omitted for duplicate)", coords)
+	case maven.OmittedConflict:
+		return fmt.Sprintf("(%s - omitted for conflict with %s)", coords, node.Winner.Artifact.Version)
+	case maven.OmittedExcluded:
+		return fmt.Sprintf("(%s - excluded)", coords)
+	case maven.OmittedOptional:
+		return fmt.Sprintf("(%s - optional)", coords)
+	}
+	switch {
+	case node.Selection == maven.SelectedManaged:
+		return fmt.Sprintf("%s (version managed from %s)", coords, node.Requested)
+	case node.Selection == maven.SelectedOverride && node.Requested != node.Artifact.Version:
+		return fmt.Sprintf("%s (version forced from %s)", coords, node.Requested)
+	}
+	return coords
+}
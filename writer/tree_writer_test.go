@@ -0,0 +1,122 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("TreeWriter", func() {
+	var (
+		err    error
+		out    *gbytes.Buffer
+		writer *TreeWriter
+		depth  int
+		filter string
+		root   *maven.Node
+	)
+
+	artifact := func(artifactID string, version string) *maven.Artifact {
+		return &maven.Artifact{GroupID: "org.fake", ArtifactID: artifactID, Version: version}
+	}
+
+	BeforeEach(func() {
+		out = gbytes.NewBuffer()
+		depth = 0
+		filter = ""
+
+		c := &maven.Node{Artifact: artifact("c", "1"), Scope: "compile", Children: []*maven.Node{
+			{Artifact: artifact("e", "1"), Scope: "compile", Omitted: maven.OmittedExcluded},
+		}}
+		d := &maven.Node{Artifact: artifact("d", "1"), Scope: "runtime", Children: []*maven.Node{
+			{Artifact: artifact("g", "1"), Scope: "runtime", Omitted: maven.OmittedOptional},
+		}}
+		root = &maven.Node{Artifact: artifact("root", "1"), Children: []*maven.Node{
+			{Artifact: artifact("a", "1"), Scope: "compile", Children: []*maven.Node{c}},
+			{Artifact: artifact("b", "1"), Scope: "test", Children: []*maven.Node{
+				{Artifact: artifact("c", "2"), Scope: "test", Omitted: maven.OmittedConflict, Winner: c},
+				{Artifact: artifact("d", "1"), Scope: "test", Omitted: maven.OmittedDuplicate, Winner: d},
+			}},
+			d,
+		}}
+	})
+
+	JustBeforeEach(func() {
+		writer, err = NewTreeWriter(out, depth, filter)
+		if err == nil {
+			err = writer.Write(root)
+		}
+	})
+
+	It("should print the whole graph, marking omitted dependencies", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out.Contents())).To(Equal(`org.fake:root:1
++- org.fake:a:1:compile
+|  \- org.fake:c:1:compile
+|     \- (org.fake:e:1:compile - excluded)
++- org.fake:b:1:test
+|  +- (org.fake:c:2:test - omitted for conflict with 1)
+|  \- (org.fake:d:1:test - omitted for duplicate)
+\- org.fake:d:1:runtime
+   \- (org.fake:g:1:runtime - optional)
+`))
+	})
+
+	Context("given a depth", func() {
+		BeforeEach(func() {
+			depth = 1
+		})
+
+		It("should only print as many levels", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(`org.fake:root:1
++- org.fake:a:1:compile
++- org.fake:b:1:test
+\- org.fake:d:1:runtime
+`))
+		})
+	})
+
+	Context("given a filter", func() {
+		BeforeEach(func() {
+			filter = "org.fake:c*"
+		})
+
+		It("should only print the paths to matching artifacts", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(`org.fake:root:1
++- org.fake:a:1:compile
+|  \- org.fake:c:1:compile
+\- org.fake:b:1:test
+   \- (org.fake:c:2:test - omitted for conflict with 1)
+`))
+		})
+
+		Context("matching a version", func() {
+			BeforeEach(func() {
+				filter = "*:c:2"
+			})
+
+			It("should not print other versions", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(out.Contents())).To(Equal(`org.fake:root:1
+\- org.fake:b:1:test
+   \- (org.fake:c:2:test - omitted for conflict with 1)
+`))
+			})
+		})
+
+		Context("with too many segments", func() {
+			BeforeEach(func() {
+				filter = "org.fake:c:1:jar"
+			})
+
+			It("should return a meaningful error", func() {
+				Expect(err).To(MatchError("invalid filter [org.fake:c:1:jar], expected groupId:artifactId[:version]"))
+			})
+		})
+	})
+})
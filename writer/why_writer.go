@@ -0,0 +1,73 @@
+package writer
+
+import (
+	"fmt"
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	"github.com/pkg/errors"
+	"io"
+	"strings"
+)
+
+// WhyWriter explains how an artifact entered a resolved dependency graph, printing every path from the root to the
+// artifact along with the version requested on each, and the rule which selected the resolved version.
+type WhyWriter struct {
+	out        io.Writer
+	groupID    string
+	artifactID string
+}
+
+// NewWhyWriter explains the artifact given as `groupId:artifactId`, whatever its version.
+func NewWhyWriter(w io.Writer, artifact string) (*WhyWriter, error) {
+	coords := strings.Split(artifact, ":")
+	if len(coords) != 2 || coords[0] == "" || coords[1] == "" {
+		return nil, errors.Errorf("invalid artifact [%s], expected groupId:artifactId", artifact)
+	}
+	return &WhyWriter{out: w, groupID: coords[0], artifactID: coords[1]}, nil
+}
+
+func (wr *WhyWriter) Write(root *maven.Node) error {
+	b := &strings.Builder{}
+	name := wr.groupID + ":" + wr.artifactID
+	paths := root.PathsTo(wr.groupID, wr.artifactID)
+
+	var selected *maven.Node
+	for _, path := range paths {
+		if last := path[len(path)-1]; !last.IsOmitted() {
+			selected = last
+			break
+		}
+	}
+	switch {
+	case len(paths) == 0:
+		fmt.Fprintf(b, "%s is not part of the graph of %s\n", name, root.Artifact.GetMavenCoords())
+	case selected == nil:
+		fmt.Fprintf(b, "%s is excluded from the graph of %s\n", name, root.Artifact.GetMavenCoords())
+	default:
+		fmt.Fprintf(b, "%s resolves to %s in the graph of %s, selected by %s\n", name,
+			selected.Artifact.GetMavenCoords(), root.Artifact.GetMavenCoords(), selection(root, selected))
+	}
+
+	for _, path := range paths {
+		labels := make([]string, len(path))
+		for i, node := range path {
+			labels[i] = label(node)
+		}
+		fmt.Fprintf(b, "  requested %s : %s\n", requested(path[len(path)-1]), strings.Join(labels, " > "))
+	}
+	_, err := io.WriteString(wr.out, b.String())
+	return err
+}
+
+func selection(root *maven.Node, selected *maven.Node) string {
+	if selected.Selection == maven.SelectedManaged {
+		return fmt.Sprintf("%s of %s", selected.Selection, root.Artifact.GetMavenCoords())
+	}
+	return string(selected.Selection)
+}
+
+func requested(node *maven.Node) string {
+	if node.Requested == "" {
+		return "latest"
+	}
+	return node.Requested
+}
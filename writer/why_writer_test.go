@@ -0,0 +1,109 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("WhyWriter", func() {
+	var (
+		err      error
+		out      *gbytes.Buffer
+		writer   *WhyWriter
+		artifact string
+		root     *maven.Node
+		c        *maven.Node
+	)
+
+	node := func(artifactID string, version string, scope string, children ...*maven.Node) *maven.Node {
+		return &maven.Node{
+			Artifact:  &maven.Artifact{GroupID: "org.fake", ArtifactID: artifactID, Version: version},
+			Scope:     scope,
+			Requested: version,
+			Selection: maven.SelectedNearest,
+			Children:  children,
+		}
+	}
+
+	BeforeEach(func() {
+		out = gbytes.NewBuffer()
+		artifact = "org.fake:c"
+
+		c = node("c", "1", "compile")
+		loser := node("c", "2", "test")
+		loser.Omitted, loser.Winner, loser.Selection = maven.OmittedConflict, c, ""
+		excluded := node("e", "1", "compile")
+		excluded.Omitted, excluded.Selection = maven.OmittedExcluded, ""
+		root = node("root", "1", "",
+			node("a", "1", "compile", c),
+			node("b", "1", "test", loser, excluded),
+		)
+	})
+
+	JustBeforeEach(func() {
+		writer, err = NewWhyWriter(out, artifact)
+		if err == nil {
+			err = writer.Write(root)
+		}
+	})
+
+	It("should print every path to the artifact along with the rule selecting its version", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out.Contents())).To(Equal(`org.fake:c resolves to org.fake:c:1 in the graph of org.fake:root:1, selected by nearest wins
+  requested 1 : org.fake:root:1 > org.fake:a:1:compile > org.fake:c:1:compile
+  requested 2 : org.fake:root:1 > org.fake:b:1:test > (org.fake:c:2:test - omitted for conflict with 1)
+`))
+	})
+
+	Context("given an artifact managed by the root", func() {
+		BeforeEach(func() {
+			c.Requested, c.Selection = "0.9", maven.SelectedManaged
+		})
+
+		It("should tell its version was managed", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(`org.fake:c resolves to org.fake:c:1 in the graph of org.fake:root:1, selected by dependencyManagement of org.fake:root:1
+  requested 0.9 : org.fake:root:1 > org.fake:a:1:compile > org.fake:c:1:compile (version managed from 0.9)
+  requested 2 : org.fake:root:1 > org.fake:b:1:test > (org.fake:c:2:test - omitted for conflict with 1)
+`))
+		})
+	})
+
+	Context("given an excluded artifact", func() {
+		BeforeEach(func() {
+			artifact = "org.fake:e"
+		})
+
+		It("should tell it was excluded", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(`org.fake:e is excluded from the graph of org.fake:root:1
+  requested 1 : org.fake:root:1 > org.fake:b:1:test > (org.fake:e:1:compile - excluded)
+`))
+		})
+	})
+
+	Context("given an artifact outside of the graph", func() {
+		BeforeEach(func() {
+			artifact = "org.fake:z"
+		})
+
+		It("should tell it is not part of it", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal("org.fake:z is not part of the graph of org.fake:root:1\n"))
+		})
+	})
+
+	Context("given coordinates with a version", func() {
+		BeforeEach(func() {
+			artifact = "org.fake:c:1"
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(MatchError("invalid artifact [org.fake:c:1], expected groupId:artifactId"))
+		})
+	})
+})
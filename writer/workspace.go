@@ -0,0 +1,175 @@
+package writer
+
+import (
+	"sort"
+)
+
+// attributes this tool owns on the rules it generates, anything else found on an existing rule is a hand edit
+var generatedAttrs = map[string]bool{
+	"name":         true,
+	"artifact":     true,
+	"repository":   true,
+	"sha1":         true,
+	"visibility":   true,
+	"exports":      true,
+	"neverlink":    true,
+	"testonly":     true,
+	"deps":         true,
+	"runtime_deps": true,
+	"licenses":     true,
+	// applicable_licenses points to the generated `license` targets, which are merged like any other rule
+	"applicable_licenses": true,
+}
+
+// Workspace is the model of a generated workspace file.
+type Workspace struct {
+	MavenJars     []*Rule
+	JavaLibraries []*Rule
+}
+
+type Rule struct {
+	Kind  string
+	Attrs []*Attr
+	// Comments are notes about generated rules, like forced versions, which are regenerated on merge.
+	Comments []string
+}
+
+type Attr struct {
+	Name  string
+	Value Expr
+}
+
+// Statements returns the syntax tree of the workspace file.
+func (ws *Workspace) Statements() []Stmt {
+	stmts := []Stmt{
+		newRulesFunction(mavenJarsFunction, ws.MavenJars),
+		newRulesFunction(javaLibsFunction, ws.JavaLibraries),
+	}
+	for _, rule := range ws.JavaLibraries {
+		if rule.Kind == licenseRule {
+			return append([]Stmt{&LoadStmt{Module: rulesLicenseModule, Symbols: []string{licenseRule}}}, stmts...)
+		}
+	}
+	return stmts
+}
+
+// newRulesFunction wraps rules in a function, skipping any of them the calling workspace already defines.
+func newRulesFunction(name string, rules []*Rule) *DefStmt {
+	def := &DefStmt{Name: name, Body: []Stmt{
+		&AssignStmt{Name: excludesVariable, Value: &CallExpr{Func: &DotExpr{
+			X:    &CallExpr{Func: &DotExpr{X: &IdentExpr{Name: "native"}, Name: "existing_rules"}},
+			Name: "keys",
+		}}},
+	}}
+	for _, rule := range rules {
+		def.Body = append(def.Body, &IfStmt{
+			Cond: &BinaryExpr{X: &StringExpr{Value: rule.Name()}, Op: "not in", Y: &IdentExpr{Name: excludesVariable}},
+			Body: []Stmt{&ExprStmt{X: &CallExpr{Func: &IdentExpr{Name: rule.Kind}, Args: rule.Attrs},
+				Comments: rule.Comments}},
+		})
+	}
+	return def
+}
+
+// sort orders rules by name so the output doesn't depend on the order artifacts were discovered in.
+func (ws *Workspace) sort() {
+	for _, rules := range [][]*Rule{ws.MavenJars, ws.JavaLibraries} {
+		sort.SliceStable(rules, func(i, j int) bool {
+			return rules[i].Name() < rules[j].Name()
+		})
+	}
+}
+
+func (r *Rule) Name() string {
+	return r.StringAttr("name")
+}
+
+func (r *Rule) StringAttr(name string) string {
+	if attr := r.Attr(name); attr != nil {
+		if s, ok := attr.Value.(*StringExpr); ok {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+func (r *Rule) Attr(name string) *Attr {
+	for _, attr := range r.Attrs {
+		if attr.Name == name {
+			return attr
+		}
+	}
+	return nil
+}
+
+// MergeReport lists how an existing workspace changed when merged with a newly generated one.
+type MergeReport struct {
+	Added   []string
+	Updated []string
+	Removed []string
+}
+
+// MergeWorkspaces merges newly generated rules into an existing workspace. Generated attributes of existing rules are
+// replaced while hand edits are kept, and rules which are no longer generated are kept and reported as removed.
+func MergeWorkspaces(existing, generated *Workspace) (*Workspace, *MergeReport) {
+	report := &MergeReport{}
+	merged := &Workspace{
+		MavenJars:     mergeRules(existing.MavenJars, generated.MavenJars, report),
+		JavaLibraries: mergeRules(existing.JavaLibraries, generated.JavaLibraries, nil),
+	}
+	merged.sort()
+	return merged, report
+}
+
+func mergeRules(existing, generated []*Rule, report *MergeReport) []*Rule {
+	generatedByName := map[string]*Rule{}
+	for _, rule := range generated {
+		generatedByName[rule.Name()] = rule
+	}
+
+	merged := make([]*Rule, 0, len(existing)+len(generated))
+	seen := map[string]bool{}
+	for _, rule := range existing {
+		seen[rule.Name()] = true
+		newRule, isGenerated := generatedByName[rule.Name()]
+		if !isGenerated {
+			if report != nil {
+				report.Removed = append(report.Removed, describeRule(rule))
+			}
+			merged = append(merged, rule)
+			continue
+		}
+		if report != nil && describeRule(rule) != describeRule(newRule) {
+			report.Updated = append(report.Updated, describeRule(rule)+" -> "+describeRule(newRule))
+		}
+		merged = append(merged, mergeRule(rule, newRule))
+	}
+
+	for _, rule := range generated {
+		if !seen[rule.Name()] {
+			if report != nil {
+				report.Added = append(report.Added, describeRule(rule))
+			}
+			merged = append(merged, rule)
+		}
+	}
+
+	return merged
+}
+
+func mergeRule(existing, generated *Rule) *Rule {
+	merged := &Rule{Kind: generated.Kind, Attrs: append([]*Attr{}, generated.Attrs...), Comments: generated.Comments}
+	for _, attr := range existing.Attrs {
+		if !generatedAttrs[attr.Name] {
+			merged.Attrs = append(merged.Attrs, attr)
+		}
+	}
+	return merged
+}
+
+func describeRule(rule *Rule) string {
+	if artifact := rule.StringAttr("artifact"); artifact != "" {
+		return artifact
+	}
+	return rule.Name()
+}
@@ -0,0 +1,267 @@
+package writer
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// ParseWorkspace reads the rules back out of a workspace file previously generated by this tool, including its
+// `license` targets. Only the subset of Starlark this tool writes is understood, any other expression used as an
+// attribute value is kept verbatim.
+func ParseWorkspace(contents []byte) (*Workspace, error) {
+	p := &workspaceParser{src: contents}
+	if err := p.parse(); err != nil {
+		return nil, errors.Wrapf(err, "error parsing workspace file")
+	}
+	return p.workspace, nil
+}
+
+type workspaceParser struct {
+	src       []byte
+	pos       int
+	function  string
+	workspace *Workspace
+}
+
+func (p *workspaceParser) parse() error {
+	p.workspace = &Workspace{}
+
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '#':
+			p.skipComment()
+		case c == '"' || c == '\'':
+			if _, err := p.parseString(); err != nil {
+				return err
+			}
+		case p.atLineStart() && p.consume("def "):
+			p.function = p.parseIdent()
+		case p.atIdentStart() && p.consume("native."):
+			kind := p.parseIdent()
+			p.skipSpace()
+			if kind == "existing_rules" || !p.consume("(") {
+				continue
+			}
+			rule, err := p.parseRule(`native.` + kind)
+			if err != nil {
+				return err
+			}
+			if p.function == mavenJarsFunction {
+				p.workspace.MavenJars = append(p.workspace.MavenJars, rule)
+			} else {
+				p.workspace.JavaLibraries = append(p.workspace.JavaLibraries, rule)
+			}
+		case p.atIdentStart() && p.consume(licenseRule+"("):
+			// license targets are kept along with the java_library rules applying them
+			rule, err := p.parseRule(licenseRule)
+			if err != nil {
+				return err
+			}
+			p.workspace.JavaLibraries = append(p.workspace.JavaLibraries, rule)
+		default:
+			p.pos++
+		}
+	}
+
+	return nil
+}
+
+func (p *workspaceParser) parseRule(kind string) (*Rule, error) {
+	rule := &Rule{Kind: kind}
+	for {
+		p.skipSpace()
+		if p.consume(")") {
+			break
+		}
+		name := p.parseIdent()
+		if name == "" {
+			return nil, p.errorf("expected attribute name in rule [%s]", kind)
+		}
+		p.skipSpace()
+		if !p.consume("=") {
+			return nil, p.errorf("expected `=` after attribute [%s]", name)
+		}
+		p.skipSpace()
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		rule.Attrs = append(rule.Attrs, &Attr{Name: name, Value: value})
+		p.skipSpace()
+		p.consume(",")
+	}
+	if rule.Name() == "" {
+		return nil, p.errorf("rule [%s] has no name", kind)
+	}
+	return rule, nil
+}
+
+func (p *workspaceParser) parseExpr() (Expr, error) {
+	start := p.pos
+	expr, err := p.parseSimpleExpr()
+	if err == nil {
+		p.skipSpace()
+		if p.peekAny(",)]") {
+			return expr, nil
+		}
+	}
+	// fall back to preserving the expression as it was written
+	p.pos = start
+	return p.parseRawExpr()
+}
+
+func (p *workspaceParser) parseSimpleExpr() (Expr, error) {
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of file")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '"' || c == '\'':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &StringExpr{Value: s}, nil
+	case c == '[':
+		return p.parseList()
+	case p.atIdentStart():
+		return &IdentExpr{Name: p.parseIdent()}, nil
+	}
+	return nil, p.errorf("unexpected character [%c]", p.src[p.pos])
+}
+
+func (p *workspaceParser) parseList() (Expr, error) {
+	p.pos++
+	list := &ListExpr{Values: []Expr{}}
+	for {
+		p.skipSpace()
+		if p.consume("]") {
+			break
+		}
+		value, err := p.parseSimpleExpr()
+		if err != nil {
+			return nil, err
+		}
+		list.Values = append(list.Values, value)
+		p.skipSpace()
+		if !p.consume(",") && !p.peekAny("]") {
+			return nil, p.errorf("expected `,` or `]` in list")
+		}
+	}
+	return list, nil
+}
+
+func (p *workspaceParser) parseRawExpr() (Expr, error) {
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case '"', '\'':
+			if _, err := p.parseString(); err != nil {
+				return nil, err
+			}
+			continue
+		case '#':
+			p.skipComment()
+			continue
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return &RawExpr{Source: strings.TrimSpace(string(p.src[start:p.pos]))}, nil
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return &RawExpr{Source: strings.TrimSpace(string(p.src[start:p.pos]))}, nil
+			}
+		}
+		p.pos++
+	}
+	return nil, p.errorf("unexpected end of file")
+}
+
+func (p *workspaceParser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+			continue
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case quote:
+			p.pos++
+			body := string(p.src[start+1 : p.pos-1])
+			if quote == '\'' {
+				body = strings.Replace(strings.Replace(body, `\'`, `'`, -1), `"`, `\"`, -1)
+			}
+			s, err := strconv.Unquote(`"` + body + `"`)
+			if err != nil {
+				return "", p.errorf("invalid string literal")
+			}
+			return s, nil
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *workspaceParser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.src) && isIdentChar(p.src[p.pos]) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *workspaceParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+func (p *workspaceParser) skipComment() {
+	for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+		p.pos++
+	}
+}
+
+func (p *workspaceParser) consume(s string) bool {
+	if bytes.HasPrefix(p.src[p.pos:], []byte(s)) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *workspaceParser) peekAny(chars string) bool {
+	return p.pos < len(p.src) && strings.IndexByte(chars, p.src[p.pos]) >= 0
+}
+
+func (p *workspaceParser) atLineStart() bool {
+	return p.pos == 0 || p.src[p.pos-1] == '\n'
+}
+
+func (p *workspaceParser) atIdentStart() bool {
+	return p.pos < len(p.src) && isLetter(p.src[p.pos]) && (p.pos == 0 || !isIdentChar(p.src[p.pos-1]))
+}
+
+func (p *workspaceParser) errorf(format string, args ...interface{}) error {
+	line := bytes.Count(p.src[:p.pos], []byte("\n")) + 1
+	return errors.Errorf("line %d : "+format, append([]interface{}{line}, args...)...)
+}
+
+func isIdentChar(c byte) bool {
+	return isLetter(c) || (c >= '0' && c <= '9') || c == '_'
+}
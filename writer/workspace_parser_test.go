@@ -0,0 +1,152 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+)
+
+var _ = Describe("ParseWorkspace", func() {
+	var (
+		err       error
+		contents  string
+		workspace *Workspace
+	)
+
+	JustBeforeEach(func() {
+		workspace, err = ParseWorkspace([]byte(contents))
+	})
+
+	Context("given a generated workspace file", func() {
+		BeforeEach(func() {
+			contents = `def generated_maven_jars():
+  excludes = native.existing_rules().keys()
+
+  if "junit_junit" not in excludes:
+    native.maven_jar(
+        name = "junit_junit",
+        artifact = "junit:junit:4.9",
+        repository = "https://repo.maven.apache.org/maven2",
+    )
+
+def generated_java_libraries():
+  excludes = native.existing_rules().keys()
+
+  if "junit_junit" not in excludes:
+    native.java_library(
+        name = "junit_junit",
+        visibility = ["//visibility:public"],
+        exports = ["@junit_junit//jar"],
+        neverlink = True,
+        deps = [
+            ":org_hamcrest_hamcrest_core",
+        ],
+    )
+
+`
+		})
+
+		It("should read back every rule", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(workspace.MavenJars).To(ConsistOf(PointTo(MatchAllFields(Fields{
+				"Kind":     Equal("native.maven_jar"),
+				"Comments": BeEmpty(),
+				"Attrs": Equal([]*Attr{
+					{Name: "name", Value: &StringExpr{Value: "junit_junit"}},
+					{Name: "artifact", Value: &StringExpr{Value: "junit:junit:4.9"}},
+					{Name: "repository", Value: &StringExpr{Value: "https://repo.maven.apache.org/maven2"}},
+				}),
+			}))))
+			Expect(workspace.JavaLibraries).To(ConsistOf(PointTo(MatchAllFields(Fields{
+				"Kind":     Equal("native.java_library"),
+				"Comments": BeEmpty(),
+				"Attrs": Equal([]*Attr{
+					{Name: "name", Value: &StringExpr{Value: "junit_junit"}},
+					{Name: "visibility", Value: &ListExpr{Values: []Expr{&StringExpr{Value: "//visibility:public"}}}},
+					{Name: "exports", Value: &ListExpr{Values: []Expr{&StringExpr{Value: "@junit_junit//jar"}}}},
+					{Name: "neverlink", Value: &IdentExpr{Name: "True"}},
+					{Name: "deps", Value: &ListExpr{Values: []Expr{&StringExpr{Value: ":org_hamcrest_hamcrest_core"}}}},
+				}),
+			}))))
+		})
+	})
+
+	Context("given a workspace file with hand edits", func() {
+		BeforeEach(func() {
+			contents = `def generated_maven_jars():
+  excludes = native.existing_rules().keys()
+
+  # pinned by hand, see ticket
+  if "junit_junit" not in excludes:
+    native.maven_jar(
+        name = 'junit_junit',
+        artifact = "junit:junit:4.9",  # trailing comment
+        repository = "https://repo.maven.apache.org/maven2",
+        sha1 = SHAS["junit"] + "",
+    )
+`
+		})
+
+		It("should keep expressions it does not understand verbatim", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(workspace.MavenJars).To(HaveLen(1))
+			Expect(workspace.MavenJars[0].Name()).To(Equal("junit_junit"))
+			Expect(workspace.MavenJars[0].Attr("sha1").Value).To(Equal(&RawExpr{Source: `SHAS["junit"] + ""`}))
+		})
+	})
+
+	Context("given a workspace file with license rules", func() {
+		BeforeEach(func() {
+			contents = `load("@rules_license//rules:license.bzl", "license")
+
+def generated_java_libraries():
+    excludes = native.existing_rules().keys()
+
+    if "junit_junit" not in excludes:
+        native.java_library(
+            name = "junit_junit",
+            licenses = ["reciprocal"],
+            applicable_licenses = [":junit_junit_license"],
+        )
+
+    if "junit_junit_license" not in excludes:
+        license(
+            name = "junit_junit_license",
+            license_kinds = ["@rules_license//licenses/spdx:EPL-1.0"],
+        )
+`
+		})
+
+		It("should read back the license targets along with the java_library rules", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(workspace.JavaLibraries).To(HaveLen(2))
+			Expect(workspace.JavaLibraries[0].Name()).To(Equal("junit_junit"))
+			Expect(workspace.JavaLibraries[1].Kind).To(Equal("license"))
+			Expect(workspace.JavaLibraries[1].Name()).To(Equal("junit_junit_license"))
+			Expect(workspace.JavaLibraries[1].Attr("license_kinds").Value).To(Equal(&ListExpr{
+				Values: []Expr{&StringExpr{Value: "@rules_license//licenses/spdx:EPL-1.0"}},
+			}))
+		})
+	})
+
+	Context("given a malformed workspace file", func() {
+		BeforeEach(func() {
+			contents = `def generated_maven_jars():
+  native.maven_jar(
+      name = "junit_junit",
+      artifact "junit:junit:4.9",
+  )
+`
+		})
+
+		It("should return a meaningful error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("error parsing workspace file: line 4 : expected `=` after attribute [artifact]"))
+		})
+	})
+})
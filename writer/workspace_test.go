@@ -0,0 +1,273 @@
+package writer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+	. "github.com/jspawar/generate-bazel-workspace-gradle/writer"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Merging workspaces", func() {
+	var (
+		err          error
+		naming       NamingScheme
+		existing     string
+		pom          *maven.Artifact
+		licenseRules bool
+		out          *gbytes.Buffer
+		writer       *WorkspaceWriter
+	)
+
+	BeforeEach(func() {
+		naming, err = NewNamingScheme(LegacyNaming)
+		Expect(err).ToNot(HaveOccurred())
+
+		existing = `def generated_maven_jars():
+    excludes = native.existing_rules().keys()
+
+    if "org_fake_other_root" not in excludes:
+        native.maven_jar(
+            name = "org_fake_other_root",
+            artifact = "org.fake:other-root:1.0",
+            repository = "http://localhost/",
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.maven_jar(
+            name = "org_fake_some_artifact",
+            artifact = "org.fake:some-artifact:0.0.1",
+            repository = "http://localhost/",
+        )
+
+def generated_java_libraries():
+    excludes = native.existing_rules().keys()
+
+    if "org_fake_other_root" not in excludes:
+        native.java_library(
+            name = "org_fake_other_root",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_other_root//jar"],
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.java_library(
+            name = "org_fake_some_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_some_artifact//jar"],
+            tags = ["hand-edited"],
+        )
+`
+		pom = &maven.Artifact{
+			GroupID:    "org.fake",
+			ArtifactID: "some-artifact",
+			Version:    "0.0.2",
+			Repository: "http://localhost/",
+			Dependencies: []*maven.Artifact{{
+				GroupID:    "fake.org",
+				ArtifactID: "another-artifact",
+				Version:    "2.0.3",
+				Repository: "http://localhost/",
+			}},
+		}
+		licenseRules = false
+		out = gbytes.NewBuffer()
+	})
+
+	JustBeforeEach(func() {
+		workspace, parseErr := ParseWorkspace([]byte(existing))
+		Expect(parseErr).ToNot(HaveOccurred())
+
+		writer = NewMergingWorkspaceWriter(out, naming, workspace)
+		if licenseRules {
+			writer.WithLicenseRules()
+		}
+		err = writer.Write(pom)
+	})
+
+	AfterEach(func() {
+		out.Close()
+	})
+
+	It("should add new artifacts, update changed versions and keep hand edits", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(string(out.Contents())).To(Equal(`def generated_maven_jars():
+    excludes = native.existing_rules().keys()
+
+    if "fake_org_another_artifact" not in excludes:
+        native.maven_jar(
+            name = "fake_org_another_artifact",
+            artifact = "fake.org:another-artifact:2.0.3",
+            repository = "http://localhost/",
+        )
+
+    if "org_fake_other_root" not in excludes:
+        native.maven_jar(
+            name = "org_fake_other_root",
+            artifact = "org.fake:other-root:1.0",
+            repository = "http://localhost/",
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.maven_jar(
+            name = "org_fake_some_artifact",
+            artifact = "org.fake:some-artifact:0.0.2",
+            repository = "http://localhost/",
+        )
+
+def generated_java_libraries():
+    excludes = native.existing_rules().keys()
+
+    if "fake_org_another_artifact" not in excludes:
+        native.java_library(
+            name = "fake_org_another_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@fake_org_another_artifact//jar"],
+        )
+
+    if "org_fake_other_root" not in excludes:
+        native.java_library(
+            name = "org_fake_other_root",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_other_root//jar"],
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.java_library(
+            name = "org_fake_some_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_some_artifact//jar"],
+            deps = [":fake_org_another_artifact"],
+            tags = ["hand-edited"],
+        )
+`))
+	})
+
+	It("should report what changed", func() {
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(writer.MergeReport()).To(Equal(&MergeReport{
+			Added:   []string{"fake.org:another-artifact:2.0.3"},
+			Updated: []string{"org.fake:some-artifact:0.0.1 -> org.fake:some-artifact:0.0.2"},
+			Removed: []string{"org.fake:other-root:1.0"},
+		}))
+	})
+
+	Context("when merging the same resolution again", func() {
+		var (
+			firstOutput string
+		)
+
+		JustBeforeEach(func() {
+			firstOutput = string(out.Contents())
+
+			workspace, parseErr := ParseWorkspace([]byte(firstOutput))
+			Expect(parseErr).ToNot(HaveOccurred())
+
+			out = gbytes.NewBuffer()
+			writer = NewMergingWorkspaceWriter(out, naming, workspace)
+			if licenseRules {
+				writer.WithLicenseRules()
+			}
+			err = writer.Write(pom)
+		})
+
+		It("should be idempotent", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(firstOutput))
+
+			Expect(writer.MergeReport().Added).To(BeEmpty())
+			Expect(writer.MergeReport().Updated).To(BeEmpty())
+		})
+	})
+
+	Context("given license rules of an artifact no longer resolved", func() {
+		BeforeEach(func() {
+			licenseRules = true
+			existing = `load("@rules_license//rules:license.bzl", "license")
+
+def generated_maven_jars():
+    excludes = native.existing_rules().keys()
+
+    if "org_fake_other_root" not in excludes:
+        native.maven_jar(
+            name = "org_fake_other_root",
+            artifact = "org.fake:other-root:1.0",
+            repository = "http://localhost/",
+        )
+
+def generated_java_libraries():
+    excludes = native.existing_rules().keys()
+
+    if "org_fake_other_root" not in excludes:
+        native.java_library(
+            name = "org_fake_other_root",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_other_root//jar"],
+            applicable_licenses = [":org_fake_other_root_license"],
+        )
+
+    if "org_fake_other_root_license" not in excludes:
+        license(
+            name = "org_fake_other_root_license",
+            license_kinds = ["@rules_license//licenses/spdx:MIT"],
+            package_name = "org.fake:other-root",
+            package_version = "1.0",
+        )
+`
+			pom.Dependencies = nil
+		})
+
+		It("should keep the license target the kept rule applies", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out.Contents())).To(Equal(`load("@rules_license//rules:license.bzl", "license")
+
+def generated_maven_jars():
+    excludes = native.existing_rules().keys()
+
+    if "org_fake_other_root" not in excludes:
+        native.maven_jar(
+            name = "org_fake_other_root",
+            artifact = "org.fake:other-root:1.0",
+            repository = "http://localhost/",
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.maven_jar(
+            name = "org_fake_some_artifact",
+            artifact = "org.fake:some-artifact:0.0.2",
+            repository = "http://localhost/",
+        )
+
+def generated_java_libraries():
+    excludes = native.existing_rules().keys()
+
+    if "org_fake_other_root" not in excludes:
+        native.java_library(
+            name = "org_fake_other_root",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_other_root//jar"],
+            applicable_licenses = [":org_fake_other_root_license"],
+        )
+
+    if "org_fake_other_root_license" not in excludes:
+        license(
+            name = "org_fake_other_root_license",
+            license_kinds = ["@rules_license//licenses/spdx:MIT"],
+            package_name = "org.fake:other-root",
+            package_version = "1.0",
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.java_library(
+            name = "org_fake_some_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_some_artifact//jar"],
+        )
+`))
+			Expect(writer.MergeReport().Removed).To(Equal([]string{"org.fake:other-root:1.0"}))
+		})
+	})
+})
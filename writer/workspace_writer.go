@@ -1,132 +1,170 @@
 package writer
 
 import (
-	"fmt"
 	_ "github.com/jspawar/generate-bazel-workspace-gradle/logging"
 	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
 	"go.uber.org/zap"
 	"io"
+	"sort"
 )
 
-const indent = `  `
-const mavenJarsBlockHeader = `def generated_maven_jars():`
-const javaLibsBlockHeader = `def generated_java_libraries():`
-const excludesDefinition = `excludes = native.existing_rules().keys()`
-const artifactDefinitionHeader = `if "%s" not in excludes:`
+const mavenJarsFunction = `generated_maven_jars`
+const javaLibsFunction = `generated_java_libraries`
+const excludesVariable = `excludes`
 const mavenJarRule = `native.maven_jar`
 const javaLibRule = `native.java_library`
 
 var logger = zap.S()
 
+var _ Writer = &WorkspaceWriter{}
+
 type WorkspaceWriter struct {
-	out io.Writer
+	out      io.Writer
+	naming   NamingScheme
+	existing *Workspace
+	report   *MergeReport
+	// licenseRules adds a `rules_license` target for the licenses of each artifact
+	licenseRules bool
 }
 
-func NewWorkspaceWriter(w io.Writer) *WorkspaceWriter {
-	return &WorkspaceWriter{out: w}
+func NewWorkspaceWriter(w io.Writer, naming NamingScheme) *WorkspaceWriter {
+	return &WorkspaceWriter{out: w, naming: naming}
 }
 
-func (w *WorkspaceWriter) Write(artifact *maven.Artifact) error {
-	w.out.Write([]byte(mavenJarsBlockHeader))
-	w.out.Write([]byte("\n"))
+// NewMergingWorkspaceWriter creates a writer which merges the generated rules into those of an existing workspace.
+func NewMergingWorkspaceWriter(w io.Writer, naming NamingScheme, existing *Workspace) *WorkspaceWriter {
+	return &WorkspaceWriter{out: w, naming: naming, existing: existing}
+}
 
-	w.writeWithIndents(1, []byte(excludesDefinition))
-	w.writeWithIndents(0, []byte("\n\n"))
+// WithLicenseRules makes the writer declare a `license` target of `rules_license` for each artifact whose licenses
+// are recognized, which the `java_library` rules then apply.
+func (w *WorkspaceWriter) WithLicenseRules() *WorkspaceWriter {
+	w.licenseRules = true
+	return w
+}
 
-	// write `maven_jar` rules
-	if err := w.writeMavenJarRule(artifact); err != nil {
+func (w *WorkspaceWriter) Write(artifact *maven.Artifact) error {
+	workspace, err := newWorkspace(artifact, w.naming, w.licenseRules)
+	if err != nil {
 		return err
 	}
-
-	w.out.Write([]byte(javaLibsBlockHeader))
-	w.out.Write([]byte("\n"))
-
-	w.writeWithIndents(1, []byte(excludesDefinition))
-	w.writeWithIndents(0, []byte("\n\n"))
-
-	// write `java_library` rules
-	if err := w.writeJavaLibraryRule(artifact); err != nil {
-		return err
+	if w.existing != nil {
+		workspace, w.report = MergeWorkspaces(w.existing, workspace)
 	}
 
-	return nil
+	_, err = w.out.Write(PrintStarlark(workspace.Statements()))
+	return err
 }
 
-func (w *WorkspaceWriter) writeWithIndents(n int, bs []byte) {
-	for i := 0; i < n; i++ {
-		w.out.Write([]byte(indent))
-	}
-	w.out.Write(bs)
+// MergeReport returns what changed in the existing workspace, if this writer was merging into one.
+func (w *WorkspaceWriter) MergeReport() *MergeReport {
+	return w.report
 }
 
-func (w *WorkspaceWriter) writeMavenJarRule(artifact *maven.Artifact) error {
-	logger.Debugf("Writing Maven JAR rule for artifact: [%s]", artifact.GetMavenCoords())
+// NewWorkspace builds the `maven_jar` and `java_library` rules for an artifact and its transitive dependencies.
+func NewWorkspace(artifact *maven.Artifact, naming NamingScheme) (*Workspace, error) {
+	return newWorkspace(artifact, naming, false)
+}
 
-	w.writeWithIndents(1, []byte(fmt.Sprintf(artifactDefinitionHeader, artifact.GetBazelRule())))
-	w.writeWithIndents(0, []byte("\n"))
+func newWorkspace(artifact *maven.Artifact, naming NamingScheme, licenseRules bool) (*Workspace, error) {
+	names, err := AssignRuleNames(naming, artifact)
+	if err != nil {
+		return nil, err
+	}
 
-	w.writeWithIndents(2, []byte(mavenJarRule+`(`))
+	workspace := &Workspace{}
+	for _, a := range collectArtifacts(artifact) {
+		workspace.MavenJars = append(workspace.MavenJars, newMavenJarRule(a, names))
+		javaLibrary := newJavaLibraryRule(a, names)
+		if license := newLicenseRule(a, names); licenseRules && license != nil {
+			javaLibrary.Attrs = append(javaLibrary.Attrs, &Attr{Name: "applicable_licenses", Value: &ListExpr{
+				Values: []Expr{&StringExpr{Value: ":" + license.Name()}},
+			}})
+			workspace.JavaLibraries = append(workspace.JavaLibraries, license)
+		}
+		workspace.JavaLibraries = append(workspace.JavaLibraries, javaLibrary)
+	}
+	workspace.sort()
+	return workspace, nil
+}
 
-	w.writeWithIndents(0, []byte("\n"))
-	w.writeWithIndents(4, []byte(fmt.Sprintf(`name = "%s",`, artifact.GetBazelRule())))
-	w.writeWithIndents(0, []byte("\n"))
-	w.writeWithIndents(4, []byte(fmt.Sprintf(`artifact = "%s",`, artifact.GetMavenCoords())))
-	w.writeWithIndents(0, []byte("\n"))
-	w.writeWithIndents(4, []byte(fmt.Sprintf(`repository = "%s",`, artifact.Repository)))
-	w.writeWithIndents(0, []byte("\n"))
+func newMavenJarRule(artifact *maven.Artifact, names *RuleNames) *Rule {
+	rule := &Rule{Kind: mavenJarRule, Attrs: []*Attr{
+		{Name: "name", Value: &StringExpr{Value: names.Get(artifact)}},
+		{Name: "artifact", Value: &StringExpr{Value: artifact.GetMavenCoords()}},
+		{Name: "repository", Value: &StringExpr{Value: artifact.Repository}},
+	}}
+	if artifact.Forced {
+		comment := "version forced by override"
+		if artifact.ForcedFrom != artifact.Version {
+			comment += ", requested " + artifact.ForcedFrom
+		}
+		rule.Comments = append(rule.Comments, comment)
+	}
+	return rule
+}
 
-	w.writeWithIndents(2, []byte(`)`))
+func newJavaLibraryRule(artifact *maven.Artifact, names *RuleNames) *Rule {
+	rule := &Rule{Kind: javaLibRule, Attrs: []*Attr{
+		{Name: "name", Value: &StringExpr{Value: names.Get(artifact)}},
+		{Name: "visibility", Value: &ListExpr{Values: []Expr{&StringExpr{Value: "//visibility:public"}}}},
+		{Name: "exports", Value: &ListExpr{Values: []Expr{&StringExpr{Value: "@" + names.Get(artifact) + "//jar"}}}},
+	}}
+	if types := licenseTypes(artifact); len(types) > 0 {
+		list := &ListExpr{}
+		for _, licenseType := range types {
+			list.Values = append(list.Values, &StringExpr{Value: licenseType})
+		}
+		rule.Attrs = append(rule.Attrs, &Attr{Name: "licenses", Value: list})
+	}
 
-	w.writeWithIndents(0, []byte("\n\n"))
+	// scope of the artifact itself decides how it may be linked
+	switch artifact.Scope {
+	case maven.ScopeProvided:
+		rule.Attrs = append(rule.Attrs, &Attr{Name: "neverlink", Value: &IdentExpr{Name: "True"}})
+	case maven.ScopeTest:
+		rule.Attrs = append(rule.Attrs, &Attr{Name: "testonly", Value: &IdentExpr{Name: "True"}})
+	}
 
-	// now add all dependencies as their own `maven_jar` rules
-	if artifact.Dependencies != nil && len(artifact.Dependencies) > 0 {
-		for _, dep := range artifact.Dependencies {
-			w.writeMavenJarRule(dep)
-		}
+	// add `deps` and `runtime_deps` properties for input
+	deps, runtimeDeps := partitionDependencies(artifact)
+	if len(deps) > 0 {
+		rule.Attrs = append(rule.Attrs, &Attr{Name: "deps", Value: labelList(deps, names)})
+	}
+	if len(runtimeDeps) > 0 {
+		rule.Attrs = append(rule.Attrs, &Attr{Name: "runtime_deps", Value: labelList(runtimeDeps, names)})
 	}
 
-	return nil
+	return rule
 }
 
-func (w *WorkspaceWriter) writeJavaLibraryRule(artifact *maven.Artifact) error {
-	logger.Debugf("Writing Java library rule for artifact: [%s]", artifact.GetMavenCoords())
-
-	w.writeWithIndents(1, []byte(fmt.Sprintf(artifactDefinitionHeader, artifact.GetBazelRule())))
-	w.writeWithIndents(0, []byte("\n"))
-
-	w.writeWithIndents(2, []byte(javaLibRule+`(`))
-
-	w.writeWithIndents(0, []byte("\n"))
-	w.writeWithIndents(4, []byte(fmt.Sprintf(`name = "%s",`, artifact.GetBazelRule())))
-	w.writeWithIndents(0, []byte("\n"))
-	w.writeWithIndents(4, []byte(`visibility = ["//visibility:public"],`))
-	w.writeWithIndents(0, []byte("\n"))
-	w.writeWithIndents(4, []byte(fmt.Sprintf(`exports = ["@%s//jar"],`, artifact.GetBazelRule())))
-	w.writeWithIndents(0, []byte("\n"))
-
-	// write `deps` property for input
-	if artifact.Dependencies != nil && len(artifact.Dependencies) > 0 {
-		w.writeWithIndents(4, []byte(`deps = [`))
-		w.writeWithIndents(0, []byte("\n"))
-		for _, dep := range artifact.Dependencies {
-			w.writeWithIndents(6, []byte(fmt.Sprintf(`":%s",`, dep.GetBazelRule())))
-			w.writeWithIndents(0, []byte("\n"))
-		}
-		w.writeWithIndents(4, []byte(`],`))
-		w.writeWithIndents(0, []byte("\n"))
+func labelList(artifacts []*maven.Artifact, names *RuleNames) *ListExpr {
+	labels := make([]string, 0, len(artifacts))
+	for _, a := range artifacts {
+		labels = append(labels, ":"+names.Get(a))
 	}
+	sort.Strings(labels)
 
-	w.writeWithIndents(2, []byte(`)`))
-
-	w.writeWithIndents(0, []byte("\n\n"))
+	list := &ListExpr{}
+	for _, label := range labels {
+		list.Values = append(list.Values, &StringExpr{Value: label})
+	}
+	return list
+}
 
-	// now add all dependencies as their own `java_library` rules
-	if artifact.Dependencies != nil && len(artifact.Dependencies) > 0 {
-		for _, dep := range artifact.Dependencies {
-			w.writeJavaLibraryRule(dep)
+// partitionDependencies splits the dependencies of an artifact into those needed on the compile classpath and those
+// only needed at runtime. Test dependencies are left out unless the artifact is itself test only, since Bazel does
+// not allow regular targets to depend on `testonly` ones.
+func partitionDependencies(artifact *maven.Artifact) (deps, runtimeDeps []*maven.Artifact) {
+	for _, dep := range artifact.Dependencies {
+		switch {
+		case dep.Scope == maven.ScopeTest && artifact.Scope != maven.ScopeTest:
+			continue
+		case dep.Scope == maven.ScopeRuntime && artifact.Scope != maven.ScopeRuntime:
+			runtimeDeps = append(runtimeDeps, dep)
+		default:
+			deps = append(deps, dep)
 		}
 	}
-
-	return nil
+	return deps, runtimeDeps
 }
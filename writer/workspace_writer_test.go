@@ -15,9 +15,15 @@ var _ = Describe("WorkspaceWriter", func() {
 	var (
 		err    error
 		writer *WorkspaceWriter
+		naming NamingScheme
 		pom    *maven.Artifact
 	)
 
+	BeforeEach(func() {
+		naming, err = NewNamingScheme(LegacyNaming)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	JustBeforeEach(func() {
 		err = writer.Write(pom)
 	})
@@ -29,7 +35,7 @@ var _ = Describe("WorkspaceWriter", func() {
 
 		BeforeEach(func() {
 			out = gbytes.NewBuffer()
-			writer = NewWorkspaceWriter(out)
+			writer = NewWorkspaceWriter(out, naming)
 		})
 
 		AfterEach(func() {
@@ -52,30 +58,116 @@ var _ = Describe("WorkspaceWriter", func() {
 
 				Expect(string(out.Contents())).To(Equal(
 `def generated_maven_jars():
-  excludes = native.existing_rules().keys()
+    excludes = native.existing_rules().keys()
 
-  if "org_fake_some_artifact" not in excludes:
-    native.maven_jar(
-        name = "org_fake_some_artifact",
-        artifact = "org.fake:some-artifact:0.0.1",
-        repository = "http://localhost/",
-    )
+    if "org_fake_some_artifact" not in excludes:
+        native.maven_jar(
+            name = "org_fake_some_artifact",
+            artifact = "org.fake:some-artifact:0.0.1",
+            repository = "http://localhost/",
+        )
 
 def generated_java_libraries():
-  excludes = native.existing_rules().keys()
-
-  if "org_fake_some_artifact" not in excludes:
-    native.java_library(
-        name = "org_fake_some_artifact",
-        visibility = ["//visibility:public"],
-        exports = ["@org_fake_some_artifact//jar"],
-    )
-
+    excludes = native.existing_rules().keys()
+
+    if "org_fake_some_artifact" not in excludes:
+        native.java_library(
+            name = "org_fake_some_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_some_artifact//jar"],
+        )
 `,
 				))
 			})
 		})
 
+		Context("given an artifact with a version forced by an override", func() {
+			BeforeEach(func() {
+				pom = &maven.Artifact{
+					GroupID:    "org.fake",
+					ArtifactID: "some-artifact",
+					Version:    "0.0.2",
+					Repository: "http://localhost/",
+					Forced:     true,
+					ForcedFrom: "0.0.1",
+				}
+			})
+
+			It("should record it in a comment", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(out.Contents())).To(ContainSubstring(`    if "org_fake_some_artifact" not in excludes:
+        # version forced by override, requested 0.0.1
+        native.maven_jar(
+            name = "org_fake_some_artifact",`))
+			})
+		})
+
+		Context("given an artifact with licenses", func() {
+			BeforeEach(func() {
+				pom = &maven.Artifact{
+					GroupID:    "org.fake",
+					ArtifactID: "some-artifact",
+					Version:    "0.0.1",
+					Repository: "http://localhost/",
+					Licenses: []maven.License{
+						{Name: "The Apache Software License, Version 2.0"},
+						{Name: "Eclipse Public License - v 2.0"},
+						{Name: "Some Company License"},
+					},
+				}
+			})
+
+			It("should declare the Bazel license types of the recognized ones", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(out.Contents())).To(ContainSubstring(`            exports = ["@org_fake_some_artifact//jar"],
+            licenses = [
+                "notice",
+                "reciprocal",
+            ],
+        )`))
+				Expect(string(out.Contents())).ToNot(ContainSubstring("load("))
+			})
+
+			Context("when writing license rules", func() {
+				BeforeEach(func() {
+					writer.WithLicenseRules()
+				})
+
+				It("should declare a license target for the artifact", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(string(out.Contents())).To(HavePrefix(`load("@rules_license//rules:license.bzl", "license")
+
+def generated_maven_jars():`))
+					Expect(string(out.Contents())).To(HaveSuffix(`def generated_java_libraries():
+    excludes = native.existing_rules().keys()
+
+    if "org_fake_some_artifact" not in excludes:
+        native.java_library(
+            name = "org_fake_some_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_some_artifact//jar"],
+            licenses = [
+                "notice",
+                "reciprocal",
+            ],
+            applicable_licenses = [":org_fake_some_artifact_license"],
+        )
+
+    if "org_fake_some_artifact_license" not in excludes:
+        license(
+            name = "org_fake_some_artifact_license",
+            license_kinds = [
+                "@rules_license//licenses/spdx:Apache-2.0",
+                "@rules_license//licenses/spdx:EPL-2.0",
+            ],
+            package_name = "org.fake:some-artifact",
+            package_version = "0.0.1",
+        )
+`))
+				})
+			})
+		})
+
 		Context("given an artifact with dependencies", func() {
 			BeforeEach(func() {
 				pom = &maven.Artifact{
@@ -97,46 +189,103 @@ def generated_java_libraries():
 
 				Expect(string(out.Contents())).To(Equal(
 `def generated_maven_jars():
-  excludes = native.existing_rules().keys()
-
-  if "org_fake_some_artifact" not in excludes:
-    native.maven_jar(
-        name = "org_fake_some_artifact",
-        artifact = "org.fake:some-artifact:0.0.1",
-        repository = "http://localhost/",
-    )
-
-  if "fake_org_another_artifact" not in excludes:
-    native.maven_jar(
-        name = "fake_org_another_artifact",
-        artifact = "fake.org:another-artifact:2.0.3",
-        repository = "http://localhost/",
-    )
+    excludes = native.existing_rules().keys()
+
+    if "fake_org_another_artifact" not in excludes:
+        native.maven_jar(
+            name = "fake_org_another_artifact",
+            artifact = "fake.org:another-artifact:2.0.3",
+            repository = "http://localhost/",
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.maven_jar(
+            name = "org_fake_some_artifact",
+            artifact = "org.fake:some-artifact:0.0.1",
+            repository = "http://localhost/",
+        )
 
 def generated_java_libraries():
-  excludes = native.existing_rules().keys()
-
-  if "org_fake_some_artifact" not in excludes:
-    native.java_library(
-        name = "org_fake_some_artifact",
-        visibility = ["//visibility:public"],
-        exports = ["@org_fake_some_artifact//jar"],
-        deps = [
-            ":fake_org_another_artifact",
-        ],
-    )
-
-  if "fake_org_another_artifact" not in excludes:
-    native.java_library(
-        name = "fake_org_another_artifact",
-        visibility = ["//visibility:public"],
-        exports = ["@fake_org_another_artifact//jar"],
-    )
-
+    excludes = native.existing_rules().keys()
+
+    if "fake_org_another_artifact" not in excludes:
+        native.java_library(
+            name = "fake_org_another_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@fake_org_another_artifact//jar"],
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.java_library(
+            name = "org_fake_some_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_some_artifact//jar"],
+            deps = [":fake_org_another_artifact"],
+        )
 `,
 				))
 			})
 		})
+
+		Context("given an artifact with dependencies of different scopes", func() {
+			BeforeEach(func() {
+				pom = &maven.Artifact{
+					GroupID:    "org.fake",
+					ArtifactID: "some-artifact",
+					Version:    "0.0.1",
+					Repository: "http://localhost/",
+					Dependencies: []*maven.Artifact{{
+						GroupID:    "fake.org",
+						ArtifactID: "runtime-artifact",
+						Version:    "1.0",
+						Scope:      "runtime",
+						Repository: "http://localhost/",
+					}, {
+						GroupID:    "fake.org",
+						ArtifactID: "provided-artifact",
+						Version:    "1.0",
+						Scope:      "provided",
+						Repository: "http://localhost/",
+					}, {
+						GroupID:    "fake.org",
+						ArtifactID: "test-artifact",
+						Version:    "1.0",
+						Scope:      "test",
+						Repository: "http://localhost/",
+					}},
+				}
+			})
+
+			It("should map Maven scopes to the equivalent Bazel attributes", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(string(out.Contents())).To(ContainSubstring(
+					`        native.java_library(
+            name = "org_fake_some_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_some_artifact//jar"],
+            deps = [":fake_org_provided_artifact"],
+            runtime_deps = [":fake_org_runtime_artifact"],
+        )
+`))
+				Expect(string(out.Contents())).To(ContainSubstring(
+					`        native.java_library(
+            name = "fake_org_provided_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@fake_org_provided_artifact//jar"],
+            neverlink = True,
+        )
+`))
+				Expect(string(out.Contents())).To(ContainSubstring(
+					`        native.java_library(
+            name = "fake_org_test_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@fake_org_test_artifact//jar"],
+            testonly = True,
+        )
+`))
+			})
+		})
 	})
 
 	Context("writing to a file", func() {
@@ -149,7 +298,7 @@ def generated_java_libraries():
 			out, err = os.Create(os.TempDir() + "workspace_writer_test")
 			Expect(err).ToNot(HaveOccurred())
 
-			writer = NewWorkspaceWriter(out)
+			writer = NewWorkspaceWriter(out, naming)
 		})
 
 		JustBeforeEach(func() {
@@ -179,25 +328,24 @@ def generated_java_libraries():
 
 				Expect(string(outContents)).To(Equal(
 `def generated_maven_jars():
-  excludes = native.existing_rules().keys()
+    excludes = native.existing_rules().keys()
 
-  if "org_fake_some_artifact" not in excludes:
-    native.maven_jar(
-        name = "org_fake_some_artifact",
-        artifact = "org.fake:some-artifact:0.0.1",
-        repository = "http://localhost/",
-    )
+    if "org_fake_some_artifact" not in excludes:
+        native.maven_jar(
+            name = "org_fake_some_artifact",
+            artifact = "org.fake:some-artifact:0.0.1",
+            repository = "http://localhost/",
+        )
 
 def generated_java_libraries():
-  excludes = native.existing_rules().keys()
-
-  if "org_fake_some_artifact" not in excludes:
-    native.java_library(
-        name = "org_fake_some_artifact",
-        visibility = ["//visibility:public"],
-        exports = ["@org_fake_some_artifact//jar"],
-    )
-
+    excludes = native.existing_rules().keys()
+
+    if "org_fake_some_artifact" not in excludes:
+        native.java_library(
+            name = "org_fake_some_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_some_artifact//jar"],
+        )
 `,
 				))
 			})
@@ -224,42 +372,39 @@ def generated_java_libraries():
 
 				Expect(string(outContents)).To(Equal(
 `def generated_maven_jars():
-  excludes = native.existing_rules().keys()
-
-  if "org_fake_some_artifact" not in excludes:
-    native.maven_jar(
-        name = "org_fake_some_artifact",
-        artifact = "org.fake:some-artifact:0.0.1",
-        repository = "http://localhost/",
-    )
-
-  if "fake_org_another_artifact" not in excludes:
-    native.maven_jar(
-        name = "fake_org_another_artifact",
-        artifact = "fake.org:another-artifact:2.0.3",
-        repository = "http://localhost/",
-    )
+    excludes = native.existing_rules().keys()
+
+    if "fake_org_another_artifact" not in excludes:
+        native.maven_jar(
+            name = "fake_org_another_artifact",
+            artifact = "fake.org:another-artifact:2.0.3",
+            repository = "http://localhost/",
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.maven_jar(
+            name = "org_fake_some_artifact",
+            artifact = "org.fake:some-artifact:0.0.1",
+            repository = "http://localhost/",
+        )
 
 def generated_java_libraries():
-  excludes = native.existing_rules().keys()
-
-  if "org_fake_some_artifact" not in excludes:
-    native.java_library(
-        name = "org_fake_some_artifact",
-        visibility = ["//visibility:public"],
-        exports = ["@org_fake_some_artifact//jar"],
-        deps = [
-            ":fake_org_another_artifact",
-        ],
-    )
-
-  if "fake_org_another_artifact" not in excludes:
-    native.java_library(
-        name = "fake_org_another_artifact",
-        visibility = ["//visibility:public"],
-        exports = ["@fake_org_another_artifact//jar"],
-    )
-
+    excludes = native.existing_rules().keys()
+
+    if "fake_org_another_artifact" not in excludes:
+        native.java_library(
+            name = "fake_org_another_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@fake_org_another_artifact//jar"],
+        )
+
+    if "org_fake_some_artifact" not in excludes:
+        native.java_library(
+            name = "org_fake_some_artifact",
+            visibility = ["//visibility:public"],
+            exports = ["@org_fake_some_artifact//jar"],
+            deps = [":fake_org_another_artifact"],
+        )
 `,
 				))
 			})
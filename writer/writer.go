@@ -0,0 +1,38 @@
+package writer
+
+import (
+	"github.com/jspawar/generate-bazel-workspace-gradle/maven"
+)
+
+// Writer renders a traversed artifact, along with its transitive dependencies, into some output format.
+type Writer interface {
+	Write(artifact *maven.Artifact) error
+}
+
+// GraphWriter renders a resolved dependency graph, which unlike a traversed artifact tells every dependency of each
+// artifact, including those on artifacts resolved through another dependent.
+type GraphWriter interface {
+	Write(root *maven.Node) error
+}
+
+// collectArtifacts flattens the dependency graph of an artifact, in the order the artifacts were discovered.
+func collectArtifacts(artifact *maven.Artifact) []*maven.Artifact {
+	artifacts := []*maven.Artifact{artifact}
+	for _, dep := range artifact.Dependencies {
+		artifacts = append(artifacts, collectArtifacts(dep)...)
+	}
+	return artifacts
+}
+
+// uniqueArtifacts flattens the dependency graph of an artifact, keeping the first of artifacts found several times.
+func uniqueArtifacts(artifact *maven.Artifact) []*maven.Artifact {
+	artifacts := make([]*maven.Artifact, 0)
+	seen := map[string]bool{}
+	for _, a := range collectArtifacts(artifact) {
+		if !seen[a.GetMavenCoords()] {
+			seen[a.GetMavenCoords()] = true
+			artifacts = append(artifacts, a)
+		}
+	}
+	return artifacts
+}